@@ -0,0 +1,174 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package raft
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"slices"
+
+	pb "go.etcd.io/raft/v3/raftpb"
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// entryChecksum returns the checksum of the term, index, type and data of the
+// entry. It is never zero, since zero stands for no checksum.
+func entryChecksum(e pb.Entry) uint32 {
+	var hdr [20]byte
+	binary.LittleEndian.PutUint64(hdr[0:], e.Term)
+	binary.LittleEndian.PutUint64(hdr[8:], e.Index)
+	binary.LittleEndian.PutUint32(hdr[16:], uint32(e.Type))
+	sum := crc32.Update(crc32.Checksum(hdr[:], crcTable), crcTable, e.Data)
+	return max(sum, 1)
+}
+
+// verifyEntries returns the first entry whose checksum doesn't match its
+// contents, if any. Entries without a checksum are not verified.
+func verifyEntries(ents []pb.Entry) (pb.Entry, bool) {
+	for _, e := range ents {
+		if e.Checksum != 0 && e.Checksum != entryChecksum(e) {
+			return e, false
+		}
+	}
+	return pb.Entry{}, true
+}
+
+// HashMismatch reports that the hash of the state machine of this replica at
+// a hash check marker (see Config.HashCheckInterval) differs from the one of
+// the leader.
+type HashMismatch struct {
+	// Index is the index of the marker entry.
+	Index uint64
+	// Hash is the hash reported by this replica, and LeaderHash the one
+	// reported by the leader.
+	Hash, LeaderHash uint64
+}
+
+// maxHashChecks bounds the number of hash checks tracked while waiting for
+// the counterpart to compare against. Older ones are dropped first.
+const maxHashChecks = 16
+
+// hashChecker tracks the periodic cross-replica consistency checks.
+type hashChecker struct {
+	// interval is Config.HashCheckInterval, and elapsed the number of ticks
+	// since the leader proposed the last marker.
+	interval, elapsed int
+	onMismatch        func(HashMismatch)
+	// local holds the hashes reported by this replica, and leader those of
+	// the leader, by the index of the marker.
+	local, leader map[uint64]uint64
+}
+
+func addHashCheck(m map[uint64]uint64, index, hash uint64) {
+	m[index] = hash
+	if len(m) > maxHashChecks {
+		delete(m, slices.Min(mapKeys(m)))
+	}
+}
+
+func mapKeys(m map[uint64]uint64) []uint64 {
+	keys := make([]uint64, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// compare reports a mismatch if both hashes for the marker at the given
+// index are known, and stops tracking it in that case.
+func (h *hashChecker) compare(index uint64) {
+	local, ok1 := h.local[index]
+	leader, ok2 := h.leader[index]
+	if !ok1 || !ok2 {
+		return
+	}
+	delete(h.local, index)
+	delete(h.leader, index)
+	if local != leader && h.onMismatch != nil {
+		h.onMismatch(HashMismatch{Index: index, Hash: local, LeaderHash: leader})
+	}
+}
+
+// maybeProposeHashCheck proposes a hash check marker if the leader's hash
+// check interval has elapsed.
+func (r *raft) maybeProposeHashCheck() {
+	h := &r.hashCheck
+	if h.interval <= 0 {
+		return
+	}
+	if h.elapsed++; h.elapsed < h.interval {
+		return
+	}
+	h.elapsed = 0
+	m := pb.Message{From: r.id, Type: pb.MsgProp, Entries: []pb.Entry{{Type: pb.EntryHashCheck}}}
+	if err := r.Step(m); err != nil {
+		r.logger.Debugf("%x not proposing hash check: %v", r.id, err)
+	}
+}
+
+// stateHashMsg returns the MsgStateHash reporting the hash of the state
+// machine at the marker at the given index.
+func stateHashMsg(index, hash uint64) pb.Message {
+	hc := pb.HashCheck{Index: index, Hash: hash}
+	data, err := hc.Marshal()
+	if err != nil {
+		panic(err)
+	}
+	return pb.Message{Type: pb.MsgStateHash, Entries: []pb.Entry{{Type: pb.EntryHashCheck, Data: data}}}
+}
+
+// handleStateHash handles the hash of the state machine at a marker, as
+// reported by the application. The leader proposes it for the other replicas
+// to compare against.
+func (r *raft) handleStateHash(m pb.Message) {
+	var hc pb.HashCheck
+	if len(m.Entries) != 1 || hc.Unmarshal(m.Entries[0].Data) != nil {
+		r.logger.Errorf("%x invalid format of MsgStateHash", r.id)
+		return
+	}
+	if r.hashCheck.local == nil {
+		r.hashCheck.local = map[uint64]uint64{}
+	}
+	addHashCheck(r.hashCheck.local, hc.Index, hc.Hash)
+	r.hashCheck.compare(hc.Index)
+	if r.state != StateLeader {
+		return
+	}
+	prop := pb.Message{From: r.id, Type: pb.MsgProp, Entries: []pb.Entry{{Type: pb.EntryHashCheck, Data: m.Entries[0].Data}}}
+	if err := r.Step(prop); err != nil {
+		r.logger.Debugf("%x not proposing hash at index %d: %v", r.id, hc.Index, err)
+	}
+}
+
+// checkHashes records the hashes of the leader in the given committed entries
+// and compares them to those of this replica.
+func (r *raft) checkHashes(ents []pb.Entry) {
+	for _, e := range ents {
+		if e.Type != pb.EntryHashCheck || len(e.Data) == 0 {
+			continue
+		}
+		var hc pb.HashCheck
+		if err := hc.Unmarshal(e.Data); err != nil {
+			r.logger.Errorf("%x invalid hash check entry %d: %v", r.id, e.Index, err)
+			continue
+		}
+		if r.hashCheck.leader == nil {
+			r.hashCheck.leader = map[uint64]uint64{}
+		}
+		addHashCheck(r.hashCheck.leader, hc.Index, hc.Hash)
+		r.hashCheck.compare(hc.Index)
+	}
+}
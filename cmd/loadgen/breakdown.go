@@ -0,0 +1,207 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"go.etcd.io/raft/v3/kvclient"
+)
+
+const failureCanceled = "canceled"
+
+type targetStats struct {
+	Attempts  int            `json:"attempts"`
+	Successes int            `json:"successes"`
+	Redirects int            `json:"redirects"`
+	Failures  map[string]int `json:"failures,omitempty"`
+	Latency   *histogram     `json:"latency"`
+}
+
+func newTargetStats() *targetStats {
+	return &targetStats{Failures: make(map[string]int), Latency: newHistogram()}
+}
+
+func (t *targetStats) merge(o *targetStats) {
+	t.Attempts += o.Attempts
+	t.Successes += o.Successes
+	t.Redirects += o.Redirects
+	for cause, n := range o.Failures {
+		t.Failures[cause] += n
+	}
+	t.Latency.merge(o.Latency)
+}
+
+type targetSummary struct {
+	Attempts     int            `json:"attempts"`
+	Successes    int            `json:"successes"`
+	Redirects    int            `json:"redirects"`
+	Failures     map[string]int `json:"failures,omitempty"`
+	AvgLatencyMs float64        `json:"avg_latency_ms"`
+	P99LatencyMs float64        `json:"p99_latency_ms"`
+}
+
+type window struct {
+	StartMs float64 `json:"start_ms"`
+	EndMs   float64 `json:"end_ms"`
+}
+
+type tracker struct {
+	ctx       context.Context
+	origin    time.Time
+	targets   map[string]*targetStats
+	redirects int
+	known     bool
+	lostAt    time.Time
+	windows   []window
+}
+
+func newTracker(ctx context.Context, origin time.Time) *tracker {
+	return &tracker{ctx: ctx, origin: origin, targets: make(map[string]*targetStats)}
+}
+
+func (t *tracker) onAttempt(a kvclient.Attempt) {
+	if a.Target == "" {
+		return
+	}
+	ts, ok := t.targets[a.Target]
+	if !ok {
+		ts = newTargetStats()
+		t.targets[a.Target] = ts
+	}
+	ts.Attempts++
+	switch {
+	case t.ctx.Err() != nil && a.Outcome != kvclient.OK:
+		ts.Failures[failureCanceled]++
+	case a.Outcome == kvclient.OK:
+		ts.Successes++
+		ts.Latency.record(a.Duration)
+	case a.Outcome == kvclient.Redirect:
+		ts.Redirects++
+		t.redirects++
+	default:
+		ts.Failures[a.Outcome.String()]++
+	}
+}
+
+func (t *tracker) onLeaderChange(leader string) {
+	now := time.Now()
+	switch {
+	case leader == "":
+		if t.known && t.lostAt.IsZero() {
+			t.lostAt = now
+		}
+	default:
+		t.known = true
+		t.closeWindow(now)
+	}
+}
+
+func (t *tracker) closeWindow(end time.Time) {
+	if t.lostAt.IsZero() {
+		return
+	}
+	t.windows = append(t.windows, window{
+		StartMs: msSince(t.origin, t.lostAt),
+		EndMs:   msSince(t.origin, end),
+	})
+	t.lostAt = time.Time{}
+}
+
+func (t *tracker) finish(res *clientResult) {
+	t.closeWindow(time.Now())
+	res.Targets = t.targets
+	res.Redirects = t.redirects
+	res.Windows = t.windows
+}
+
+func (r *clientResult) fail(ctx context.Context, err error) {
+	r.Errors++
+	if r.Failures == nil {
+		r.Failures = make(map[string]int)
+	}
+	cause := failureCanceled
+	if ctx.Err() == nil {
+		cause = kvclient.Classify(err).String()
+	}
+	r.Failures[cause]++
+}
+
+func msSince(origin, t time.Time) float64 {
+	return float64(t.Sub(origin).Microseconds()) / 1000.0
+}
+
+func applyBreakdown(m *aggregatedMetrics, results []clientResult) {
+	m.Failures = make(map[string]int)
+	targets := make(map[string]*targetStats)
+	var windows []window
+	for _, r := range results {
+		for cause, n := range r.Failures {
+			m.Failures[cause] += n
+		}
+		m.Redirects += r.Redirects
+		for addr, ts := range r.Targets {
+			if _, ok := targets[addr]; !ok {
+				targets[addr] = newTargetStats()
+			}
+			targets[addr].merge(ts)
+		}
+		windows = append(windows, r.Windows...)
+	}
+	m.Targets = make(map[string]targetSummary, len(targets))
+	for addr, ts := range targets {
+		m.Targets[addr] = targetSummary{
+			Attempts:     ts.Attempts,
+			Successes:    ts.Successes,
+			Redirects:    ts.Redirects,
+			Failures:     ts.Failures,
+			AvgLatencyMs: ts.Latency.meanMs(),
+			P99LatencyMs: ts.Latency.percentiles()["p99"],
+		}
+	}
+	m.Unavailability = mergeWindows(windows)
+	m.UnavailableMs = 0
+	for _, w := range m.Unavailability {
+		m.UnavailableMs += w.EndMs - w.StartMs
+	}
+}
+
+func mergeWindows(windows []window) []window {
+	if len(windows) == 0 {
+		return nil
+	}
+	sorted := slices.Clone(windows)
+	slices.SortFunc(sorted, func(a, b window) int {
+		switch {
+		case a.StartMs < b.StartMs:
+			return -1
+		case a.StartMs > b.StartMs:
+			return 1
+		}
+		return 0
+	})
+	out := []window{sorted[0]}
+	for _, w := range sorted[1:] {
+		last := &out[len(out)-1]
+		if w.StartMs <= last.EndMs {
+			last.EndMs = max(last.EndMs, w.EndMs)
+			continue
+		}
+		out = append(out, w)
+	}
+	return out
+}
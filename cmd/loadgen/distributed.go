@@ -0,0 +1,413 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"go.etcd.io/raft/v3/kvclient"
+	"go.etcd.io/raft/v3/rafttest/linearizability"
+)
+
+const (
+	modeStandalone  = "standalone"
+	modeWorker      = "worker"
+	modeCoordinator = "coordinator"
+)
+
+type workerTask struct {
+	Targets      []string      `json:"targets"`
+	Duration     time.Duration `json:"duration"`
+	FirstClient  int           `json:"first_client"`
+	Clients      int           `json:"clients"`
+	PayloadSize  int           `json:"payload_size"`
+	Compressible bool          `json:"compressible"`
+	Delay        time.Duration `json:"delay"`
+	Workload     string        `json:"workload"`
+	Keys         int           `json:"keys"`
+	ReadRatio    float64       `json:"read_ratio"`
+	Consistency  string        `json:"consistency"`
+	StartAt      time.Time     `json:"start_at"`
+}
+
+type workerClientResult struct {
+	ID        int                     `json:"id"`
+	Requests  int                     `json:"requests"`
+	Errors    int                     `json:"errors"`
+	Latency   *histogram              `json:"latency"`
+	Failures  map[string]int          `json:"failures,omitempty"`
+	Redirects int                     `json:"redirects"`
+	Targets   map[string]*targetStats `json:"targets,omitempty"`
+	Windows   []window                `json:"windows,omitempty"`
+}
+
+type workerResult struct {
+	Clients []workerClientResult        `json:"clients"`
+	History []linearizability.Operation `json:"history,omitempty"`
+}
+
+type worker struct {
+	mu     sync.Mutex
+	done   chan struct{}
+	result workerResult
+}
+
+func runWorker(listen string) error {
+	w := &worker{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(rw http.ResponseWriter, r *http.Request) {
+		io.WriteString(rw, "ok")
+	})
+	mux.HandleFunc("/start", w.handleStart)
+	mux.HandleFunc("/result", w.handleResult)
+	log.Printf("worker escutando em %s", listen)
+	return http.ListenAndServe(listen, mux)
+}
+
+func (w *worker) handleStart(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(rw, "método não suportado", http.StatusMethodNotAllowed)
+		return
+	}
+	var task workerTask
+	if err := json.NewDecoder(r.Body).Decode(&task); err != nil {
+		http.Error(rw, fmt.Sprintf("tarefa inválida: %v", err), http.StatusBadRequest)
+		return
+	}
+	if len(task.Targets) == 0 || task.Clients <= 0 {
+		http.Error(rw, "tarefa sem endpoints ou clientes", http.StatusBadRequest)
+		return
+	}
+	w.mu.Lock()
+	if w.done != nil {
+		w.mu.Unlock()
+		http.Error(rw, "execução em andamento", http.StatusConflict)
+		return
+	}
+	done := make(chan struct{})
+	w.done = done
+	w.mu.Unlock()
+	go func() {
+		res := w.execute(task)
+		w.mu.Lock()
+		w.result = res
+		w.mu.Unlock()
+		close(done)
+	}()
+	rw.WriteHeader(http.StatusAccepted)
+}
+
+func (w *worker) handleResult(rw http.ResponseWriter, r *http.Request) {
+	w.mu.Lock()
+	done := w.done
+	w.mu.Unlock()
+	if done == nil {
+		http.Error(rw, "nenhuma execução em andamento", http.StatusNotFound)
+		return
+	}
+	select {
+	case <-done:
+	case <-r.Context().Done():
+		return
+	}
+	w.mu.Lock()
+	res := w.result
+	w.result = workerResult{}
+	w.done = nil
+	w.mu.Unlock()
+	_ = json.NewEncoder(rw).Encode(res)
+}
+
+func (w *worker) execute(task workerTask) workerResult {
+	cfg := runConfig{
+		Targets:      task.Targets,
+		Duration:     task.Duration,
+		ClientCount:  task.Clients,
+		PayloadSize:  task.PayloadSize,
+		Compressible: task.Compressible,
+		Delay:        task.Delay,
+		Workload:     task.Workload,
+		Keys:         task.Keys,
+		ReadRatio:    task.ReadRatio,
+		Origin:       task.StartAt,
+	}
+	consistency, err := kvclient.ParseConsistency(task.Consistency)
+	if err != nil {
+		log.Printf("%v", err)
+	}
+	cfg.Consistency = consistency
+	if wait := time.Until(task.StartAt); wait > 0 {
+		time.Sleep(wait)
+	} else {
+		log.Printf("início sincronizado perdido por %v", -wait)
+	}
+	log.Printf("iniciando %d clientes (ids %d..%d)", task.Clients, task.FirstClient, task.FirstClient+task.Clients-1)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Duration)
+	defer cancel()
+	results := runClients(ctx, cfg, task.FirstClient, task.Clients)
+	out := workerResult{
+		Clients: make([]workerClientResult, len(results)),
+		History: mergeHistories(results),
+	}
+	for i, r := range results {
+		out.Clients[i] = workerClientResult{
+			ID:        task.FirstClient + i,
+			Requests:  r.Requests,
+			Errors:    r.Errors,
+			Latency:   r.Latency,
+			Failures:  r.Failures,
+			Redirects: r.Redirects,
+			Targets:   r.Targets,
+			Windows:   r.Windows,
+		}
+	}
+	return out
+}
+
+func runCoordinator(cfg runConfig, workers []string, spawn int, startDelay time.Duration) (aggregatedMetrics, []linearizability.Operation, error) {
+	if spawn > 0 {
+		spawned, stop, err := spawnWorkers(spawn)
+		if err != nil {
+			return aggregatedMetrics{}, nil, err
+		}
+		defer stop()
+		workers = append(workers, spawned...)
+	}
+	if len(workers) == 0 {
+		return aggregatedMetrics{}, nil, errors.New("nenhum worker informado em --workers ou --spawn")
+	}
+	if cfg.ClientCount < len(workers) {
+		return aggregatedMetrics{}, nil, fmt.Errorf("%d clientes não bastam para %d workers", cfg.ClientCount, len(workers))
+	}
+	for i := range workers {
+		if !strings.Contains(workers[i], "://") {
+			workers[i] = "http://" + workers[i]
+		}
+		workers[i] = strings.TrimRight(workers[i], "/")
+		if err := waitHealthy(workers[i], 10*time.Second); err != nil {
+			return aggregatedMetrics{}, nil, err
+		}
+	}
+	startAt := time.Now().Add(startDelay)
+	next := 0
+	for i, addr := range workers {
+		n := cfg.ClientCount / len(workers)
+		if i < cfg.ClientCount%len(workers) {
+			n++
+		}
+		task := workerTask{
+			Targets:      cfg.Targets,
+			Duration:     cfg.Duration,
+			FirstClient:  next,
+			Clients:      n,
+			PayloadSize:  cfg.PayloadSize,
+			Compressible: cfg.Compressible,
+			Delay:        cfg.Delay,
+			Workload:     cfg.Workload,
+			Keys:         cfg.Keys,
+			ReadRatio:    cfg.ReadRatio,
+			Consistency:  cfg.Consistency.String(),
+			StartAt:      startAt,
+		}
+		next += n
+		if err := startWorker(addr, task); err != nil {
+			return aggregatedMetrics{}, nil, err
+		}
+	}
+	log.Printf("%d workers iniciam às %s", len(workers), startAt.Format(time.RFC3339Nano))
+	results := make([]workerResult, len(workers))
+	errs := make([]error, len(workers))
+	var wg sync.WaitGroup
+	for i, addr := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = fetchResult(addr)
+		}()
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return aggregatedMetrics{}, nil, err
+	}
+	metrics, history := mergeWorkerResults(results, cfg.Duration)
+	metrics.ClientCount = cfg.ClientCount
+	metrics.PayloadBytes = cfg.PayloadSize
+	metrics.Compressible = cfg.Compressible
+	metrics.DelayMs = float64(cfg.Delay.Microseconds()) / 1000.0
+	metrics.Timestamp = time.Now().UTC()
+	metrics.Workload = cfg.Workload
+	metrics.Workers = len(workers)
+	if cfg.Check {
+		res := linearizability.Check(history, linearizability.Options{})
+		metrics.Linearizable = res.Outcome.String()
+		log.Print(res.Report())
+	}
+	return metrics, history, nil
+}
+
+func mergeWorkerResults(results []workerResult, runtime time.Duration) (aggregatedMetrics, []linearizability.Operation) {
+	global := newHistogram()
+	var (
+		summaries []clientSummary
+		totalReq  int
+		totalErr  int
+		clients   []clientResult
+	)
+	for _, wr := range results {
+		for _, c := range wr.Clients {
+			global.merge(c.Latency)
+			totalReq += c.Requests
+			totalErr += c.Errors
+			avg := 0.0
+			if c.Latency != nil {
+				avg = c.Latency.meanMs()
+			}
+			summaries = append(summaries, clientSummary{
+				ID:            c.ID,
+				Requests:      c.Requests,
+				ThroughputOps: throughput(c.Requests, runtime),
+				AvgLatencyMs:  avg,
+				Errors:        c.Errors,
+				Failures:      c.Failures,
+				Redirects:     c.Redirects,
+			})
+			clients = append(clients, clientResult{
+				Failures:  c.Failures,
+				Redirects: c.Redirects,
+				Targets:   c.Targets,
+				Windows:   c.Windows,
+			})
+		}
+		clients = append(clients, clientResult{History: wr.History})
+	}
+	metrics := aggregatedMetrics{
+		TotalRequests: totalReq,
+		ErrorCount:    totalErr,
+		Throughput:    throughput(totalReq, runtime),
+		AvgLatencyMs:  global.meanMs(),
+		DurationSec:   runtime.Seconds(),
+		ClientStats:   summaries,
+		PercentilesMs: global.percentiles(),
+		CDF:           global.cdf(100),
+	}
+	applyBreakdown(&metrics, clients)
+	return metrics, mergeHistories(clients)
+}
+
+func spawnWorkers(n int) ([]string, func(), error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, nil, err
+	}
+	var (
+		addrs []string
+		cmds  []*exec.Cmd
+	)
+	stop := func() {
+		for _, cmd := range cmds {
+			_ = cmd.Process.Kill()
+			_ = cmd.Wait()
+		}
+	}
+	for i := 0; i < n; i++ {
+		addr, err := freeLocalAddr()
+		if err != nil {
+			stop()
+			return nil, nil, err
+		}
+		cmd := exec.Command(exe, "--mode", modeWorker, "--listen", addr)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Start(); err != nil {
+			stop()
+			return nil, nil, err
+		}
+		cmds = append(cmds, cmd)
+		addrs = append(addrs, addr)
+	}
+	return addrs, stop, nil
+}
+
+func freeLocalAddr() (string, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	defer l.Close()
+	return l.Addr().String(), nil
+}
+
+func waitHealthy(addr string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		resp, err := http.Get(addr + "/healthz")
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				return nil
+			}
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("worker %s não respondeu: %v", addr, err)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+func startWorker(addr string, task workerTask) error {
+	data, err := json.Marshal(task)
+	if err != nil {
+		return err
+	}
+	resp, err := http.Post(addr+"/start", "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("worker %s recusou a tarefa: %s %s", addr, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+func fetchResult(addr string) (workerResult, error) {
+	var res workerResult
+	resp, err := http.Get(addr + "/result")
+	if err != nil {
+		return res, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return res, fmt.Errorf("worker %s: %s", addr, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return res, fmt.Errorf("worker %s: %w", addr, err)
+	}
+	return res, nil
+}
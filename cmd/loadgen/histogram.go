@@ -0,0 +1,152 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"slices"
+	"time"
+)
+
+const histogramSubBuckets = 512
+
+type histogram struct {
+	Counts map[int]uint64 `json:"counts"`
+	Total  uint64         `json:"total"`
+	SumUs  int64          `json:"sum_us"`
+	MinUs  int64          `json:"min_us"`
+	MaxUs  int64          `json:"max_us"`
+}
+
+func newHistogram() *histogram {
+	return &histogram{Counts: make(map[int]uint64)}
+}
+
+func histogramBucket(us int64) int {
+	if us < histogramSubBuckets {
+		return int(us)
+	}
+	shift := bits.Len64(uint64(us)) - bits.Len64(histogramSubBuckets-1)
+	return shift*histogramSubBuckets + int(us>>shift)
+}
+
+func histogramBucketValue(idx int) int64 {
+	if idx < histogramSubBuckets {
+		return int64(idx)
+	}
+	shift := idx / histogramSubBuckets
+	sub := int64(idx % histogramSubBuckets)
+	lo := sub << shift
+	return lo + (int64(1)<<shift)/2
+}
+
+func (h *histogram) record(d time.Duration) {
+	us := d.Microseconds()
+	if us < 0 {
+		us = 0
+	}
+	if h.Total == 0 || us < h.MinUs {
+		h.MinUs = us
+	}
+	if us > h.MaxUs {
+		h.MaxUs = us
+	}
+	h.Counts[histogramBucket(us)]++
+	h.Total++
+	h.SumUs += us
+}
+
+func (h *histogram) merge(o *histogram) {
+	if o == nil || o.Total == 0 {
+		return
+	}
+	if h.Total == 0 || o.MinUs < h.MinUs {
+		h.MinUs = o.MinUs
+	}
+	if o.MaxUs > h.MaxUs {
+		h.MaxUs = o.MaxUs
+	}
+	for idx, c := range o.Counts {
+		h.Counts[idx] += c
+	}
+	h.Total += o.Total
+	h.SumUs += o.SumUs
+}
+
+func (h *histogram) meanMs() float64 {
+	if h.Total == 0 {
+		return 0
+	}
+	return float64(h.SumUs) / 1000.0 / float64(h.Total)
+}
+
+func (h *histogram) sortedBuckets() []int {
+	idxs := make([]int, 0, len(h.Counts))
+	for idx := range h.Counts {
+		idxs = append(idxs, idx)
+	}
+	slices.Sort(idxs)
+	return idxs
+}
+
+func (h *histogram) quantileMs(q float64, buckets []int) float64 {
+	if h.Total == 0 {
+		return 0
+	}
+	rank := uint64(math.Ceil(q * float64(h.Total)))
+	if rank == 0 {
+		rank = 1
+	}
+	var seen uint64
+	for _, idx := range buckets {
+		seen += h.Counts[idx]
+		if seen >= rank {
+			v := histogramBucketValue(idx)
+			v = max(min(v, h.MaxUs), h.MinUs)
+			return float64(v) / 1000.0
+		}
+	}
+	return float64(h.MaxUs) / 1000.0
+}
+
+func (h *histogram) percentiles() map[string]float64 {
+	if h.Total == 0 {
+		return map[string]float64{}
+	}
+	buckets := h.sortedBuckets()
+	result := make(map[string]float64)
+	for _, p := range []int{50, 75, 90, 95, 99} {
+		result[fmt.Sprintf("p%d", p)] = h.quantileMs(float64(p)/100.0, buckets)
+	}
+	return result
+}
+
+func (h *histogram) cdf(points int) []cdfPoint {
+	if h.Total == 0 || points <= 0 {
+		return nil
+	}
+	buckets := h.sortedBuckets()
+	result := make([]cdfPoint, 0, points)
+	for i := 1; i <= points; i++ {
+		q := float64(i) / float64(points)
+		result = append(result, cdfPoint{
+			LatencyMs:   h.quantileMs(q, buckets),
+			Probability: q,
+		})
+	}
+	return result
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"slices"
+	"time"
+
+	"go.etcd.io/raft/v3/kvclient"
+	"go.etcd.io/raft/v3/rafttest/linearizability"
+)
+
+const (
+	workloadOp = "op"
+	workloadKV = "kv"
+)
+
+func runKVClient(ctx context.Context, id int, cfg runConfig, client *kvclient.Client) clientResult {
+	res := clientResult{
+		LatencySamples: make([]time.Duration, 0, 1024),
+		Latency:        newHistogram(),
+	}
+	seq := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return res
+		default:
+		}
+		op := linearizability.Operation{
+			ClientID: id,
+			Kind:     linearizability.Put,
+			Key:      fmt.Sprintf("k%d", rand.Intn(cfg.Keys)),
+		}
+		if rand.Float64() < cfg.ReadRatio {
+			op.Kind = linearizability.Get
+		} else {
+			seq++
+			op.Value = fmt.Sprintf("%d-%d", id, seq)
+		}
+		begin := time.Now()
+		op.Call = int64(begin.Sub(cfg.Origin))
+		var err error
+		if op.Kind == linearizability.Get {
+			var value []byte
+			value, op.Found, err = client.Get(ctx, op.Key, cfg.Consistency)
+			op.Value = string(value)
+		} else {
+			err = client.Put(ctx, op.Key, []byte(op.Value))
+		}
+		op.Return = int64(time.Since(cfg.Origin))
+		if err != nil {
+			res.fail(ctx, err)
+			if errors.Is(err, kvclient.ErrNoLeader) {
+				continue
+			}
+			op.Unknown = true
+		} else {
+			lat := time.Since(begin)
+			res.LatencySamples = append(res.LatencySamples, lat)
+			res.Latency.record(lat)
+			res.Requests++
+		}
+		res.History = append(res.History, op)
+		if cfg.Delay > 0 {
+			select {
+			case <-ctx.Done():
+				return res
+			case <-time.After(cfg.Delay):
+			}
+		}
+	}
+}
+
+func mergeHistories(results []clientResult) []linearizability.Operation {
+	var ops []linearizability.Operation
+	for _, r := range results {
+		ops = append(ops, r.History...)
+	}
+	slices.SortStableFunc(ops, func(a, b linearizability.Operation) int {
+		switch {
+		case a.Call < b.Call:
+			return -1
+		case a.Call > b.Call:
+			return 1
+		}
+		return 0
+	})
+	return ops
+}
+
+func writeHistory(path string, ops []linearizability.Operation) error {
+	if err := ensureDir(path); err != nil {
+		return err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return linearizability.WriteHistory(file, ops)
+}
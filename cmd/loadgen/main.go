@@ -1,7 +1,6 @@
 package main
 
 import (
-	"bytes"
 	"context"
 	"encoding/csv"
 	"encoding/json"
@@ -16,12 +15,21 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"go.etcd.io/raft/v3/kvclient"
+	"go.etcd.io/raft/v3/rafttest/linearizability"
 )
 
 type clientResult struct {
 	LatencySamples []time.Duration
 	Requests       int
 	Errors         int
+	Latency        *histogram
+	History        []linearizability.Operation
+	Failures       map[string]int
+	Redirects      int
+	Targets        map[string]*targetStats
+	Windows        []window
 }
 
 type cdfPoint struct {
@@ -30,36 +38,55 @@ type cdfPoint struct {
 }
 
 type aggregatedMetrics struct {
-	TotalRequests int                `json:"total_requests"`
-	Throughput    float64            `json:"throughput_ops"`
-	AvgLatencyMs  float64            `json:"avg_latency_ms"`
-	DurationSec   float64            `json:"duration_sec"`
-	ClientStats   []clientSummary    `json:"clients"`
-	PercentilesMs map[string]float64 `json:"percentiles_ms"`
-	CDF           []cdfPoint         `json:"cdf"`
-	ErrorCount    int                `json:"error_count"`
-	ClientCount   int                `json:"client_count"`
-	PayloadBytes  int                `json:"payload_bytes"`
-	DelayMs       float64            `json:"delay_ms"`
-	Timestamp     time.Time          `json:"timestamp"`
+	TotalRequests  int                      `json:"total_requests"`
+	Throughput     float64                  `json:"throughput_ops"`
+	AvgLatencyMs   float64                  `json:"avg_latency_ms"`
+	DurationSec    float64                  `json:"duration_sec"`
+	ClientStats    []clientSummary          `json:"clients"`
+	PercentilesMs  map[string]float64       `json:"percentiles_ms"`
+	CDF            []cdfPoint               `json:"cdf"`
+	ErrorCount     int                      `json:"error_count"`
+	ClientCount    int                      `json:"client_count"`
+	PayloadBytes   int                      `json:"payload_bytes"`
+	Compressible   bool                     `json:"compressible,omitempty"`
+	DelayMs        float64                  `json:"delay_ms"`
+	Timestamp      time.Time                `json:"timestamp"`
+	Workload       string                   `json:"workload,omitempty"`
+	Linearizable   string                   `json:"linearizable,omitempty"`
+	Workers        int                      `json:"workers,omitempty"`
+	Failures       map[string]int           `json:"failures,omitempty"`
+	Redirects      int                      `json:"redirects"`
+	Targets        map[string]targetSummary `json:"targets,omitempty"`
+	Unavailability []window                 `json:"unavailability,omitempty"`
+	UnavailableMs  float64                  `json:"unavailable_ms"`
 }
 
 type clientSummary struct {
-	ID            int     `json:"id"`
-	Requests      int     `json:"requests"`
-	ThroughputOps float64 `json:"throughput_ops"`
-	AvgLatencyMs  float64 `json:"avg_latency_ms"`
-	Errors        int     `json:"errors"`
+	ID            int            `json:"id"`
+	Requests      int            `json:"requests"`
+	ThroughputOps float64        `json:"throughput_ops"`
+	AvgLatencyMs  float64        `json:"avg_latency_ms"`
+	Errors        int            `json:"errors"`
+	Failures      map[string]int `json:"failures,omitempty"`
+	Redirects     int            `json:"redirects"`
 }
 
 type runConfig struct {
-	Targets     []string
-	Duration    time.Duration
-	ClientCount int
-	PayloadSize int
-	Delay       time.Duration
-	OutputJSON  string
-	OutputCSV   string
+	Targets       []string
+	Duration      time.Duration
+	ClientCount   int
+	PayloadSize   int
+	Compressible  bool
+	Delay         time.Duration
+	OutputJSON    string
+	OutputCSV     string
+	Workload      string
+	Keys          int
+	ReadRatio     float64
+	Consistency   kvclient.Consistency
+	OutputHistory string
+	Check         bool
+	Origin        time.Time
 }
 
 func main() {
@@ -68,26 +95,80 @@ func main() {
 		clientFlag   = flag.Int("clients", 1, "número de processos cliente")
 		durationFlag = flag.Duration("duration", 30*time.Second, "tempo de execução de cada cliente")
 		payloadFlag  = flag.Int("payload-bytes", 32, "tamanho do payload aleatório em bytes")
+		compressFlag = flag.Bool("compressible", false, "usa payloads JSON repetitivos, compressíveis, em vez de bytes aleatórios")
 		delayFlag    = flag.Duration("delay", 0, "intervalo opcional entre requisições de um mesmo cliente")
 		outJSONFlag  = flag.String("out-json", "", "arquivo para escrever métricas agregadas em JSON")
 		outCSVFlag   = flag.String("out-latencies", "", "arquivo CSV para amostras de latência")
+		workloadFlag = flag.String("workload", workloadOp, "tipo de carga: op (append em /op) ou kv (get/put em /kv)")
+		keysFlag     = flag.Int("keys", 8, "número de chaves distintas na carga kv")
+		readFlag     = flag.Float64("read-ratio", 0.5, "fração de leituras na carga kv")
+		consistFlag  = flag.String("consistency", "linearizable", "consistência das leituras da carga kv: linearizable, leader ou stale")
+		historyFlag  = flag.String("out-history", "", "arquivo para gravar o histórico de operações da carga kv")
+		checkFlag    = flag.Bool("check", false, "verifica a linearizabilidade do histórico da carga kv")
+		modeFlag     = flag.String("mode", modeStandalone, "modo de execução: standalone, coordinator ou worker")
+		listenFlag   = flag.String("listen", "127.0.0.1:7070", "endereço http do worker (modo worker)")
+		workersFlag  = flag.String("workers", "", "lista de workers host:porta separados por vírgula (modo coordinator)")
+		spawnFlag    = flag.Int("spawn", 0, "número de workers locais iniciados pelo coordenador (modo coordinator)")
+		startFlag    = flag.Duration("start-delay", 2*time.Second, "antecedência do início sincronizado dos workers (modo coordinator)")
 	)
 	flag.Parse()
 	rand.Seed(time.Now().UnixNano())
+	if *modeFlag == modeWorker {
+		if err := runWorker(*listenFlag); err != nil {
+			log.Fatalf("worker terminou com erro: %v", err)
+		}
+		return
+	}
+	if *modeFlag != modeStandalone && *modeFlag != modeCoordinator {
+		log.Fatalf("modo desconhecido %q", *modeFlag)
+	}
 	cfg := runConfig{
-		Targets:     splitAndTrim(*targetsFlag),
-		Duration:    *durationFlag,
-		ClientCount: *clientFlag,
-		PayloadSize: *payloadFlag,
-		Delay:       *delayFlag,
-		OutputJSON:  *outJSONFlag,
-		OutputCSV:   *outCSVFlag,
+		Targets:       splitAndTrim(*targetsFlag),
+		Duration:      *durationFlag,
+		ClientCount:   *clientFlag,
+		PayloadSize:   *payloadFlag,
+		Compressible:  *compressFlag,
+		Delay:         *delayFlag,
+		OutputJSON:    *outJSONFlag,
+		OutputCSV:     *outCSVFlag,
+		Workload:      *workloadFlag,
+		Keys:          *keysFlag,
+		ReadRatio:     *readFlag,
+		OutputHistory: *historyFlag,
+		Check:         *checkFlag,
 	}
 	if len(cfg.Targets) == 0 {
 		log.Fatalf("necessário informar pelo menos um endpoint em --targets")
 	}
-	metrics := executeLoad(cfg)
+	if cfg.Workload != workloadOp && cfg.Workload != workloadKV {
+		log.Fatalf("carga desconhecida %q", cfg.Workload)
+	}
+	if cfg.Workload == workloadKV && cfg.Keys <= 0 {
+		log.Fatalf("--keys deve ser positivo")
+	}
+	consistency, err := kvclient.ParseConsistency(*consistFlag)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	cfg.Consistency = consistency
+	var (
+		metrics aggregatedMetrics
+		history []linearizability.Operation
+	)
+	if *modeFlag == modeCoordinator {
+		metrics, history, err = runCoordinator(cfg, splitAndTrim(*workersFlag), *spawnFlag, *startFlag)
+		if err != nil {
+			log.Fatalf("erro na execução distribuída: %v", err)
+		}
+	} else {
+		metrics, history = executeLoad(cfg)
+	}
 	printSummary(metrics)
+	if cfg.OutputHistory != "" {
+		if err := writeHistory(cfg.OutputHistory, history); err != nil {
+			log.Printf("erro ao escrever histórico: %v", err)
+		}
+	}
 	if cfg.OutputJSON != "" {
 		if err := writeJSON(cfg.OutputJSON, metrics); err != nil {
 			log.Printf("erro ao escrever json: %v", err)
@@ -98,37 +179,76 @@ func main() {
 			log.Printf("erro ao escrever csv: %v", err)
 		}
 	}
+	if metrics.Linearizable == linearizability.Illegal.String() {
+		os.Exit(1)
+	}
 }
 
-func executeLoad(cfg runConfig) aggregatedMetrics {
+func executeLoad(cfg runConfig) (aggregatedMetrics, []linearizability.Operation) {
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.Duration)
 	defer cancel()
-	results := make([]clientResult, cfg.ClientCount)
-	wg := sync.WaitGroup{}
-	wg.Add(cfg.ClientCount)
-	for i := 0; i < cfg.ClientCount; i++ {
-		go func(idx int) {
-			defer wg.Done()
-			results[idx] = runClient(ctx, idx, cfg)
-		}(i)
-	}
-	wg.Wait()
+	cfg.Origin = time.Now()
+	results := runClients(ctx, cfg, 0, cfg.ClientCount)
 	metrics := aggregate(results, cfg.Duration)
 	metrics.ClientCount = cfg.ClientCount
 	metrics.PayloadBytes = cfg.PayloadSize
+	metrics.Compressible = cfg.Compressible
 	metrics.DelayMs = float64(cfg.Delay.Microseconds()) / 1000.0
 	metrics.Timestamp = time.Now().UTC()
-	return metrics
+	metrics.Workload = cfg.Workload
+	history := mergeHistories(results)
+	if cfg.Check {
+		res := linearizability.Check(history, linearizability.Options{})
+		metrics.Linearizable = res.Outcome.String()
+		log.Print(res.Report())
+	}
+	return metrics, history
 }
 
-func runClient(ctx context.Context, id int, cfg runConfig) clientResult {
-	client := &http.Client{
-		Timeout: 5 * time.Second,
+func runClients(ctx context.Context, cfg runConfig, first, n int) []clientResult {
+	transport := &http.Transport{
+		Proxy:               http.ProxyFromEnvironment,
+		MaxIdleConnsPerHost: max(n, 2),
+		IdleConnTimeout:     90 * time.Second,
 	}
-	targetIdx := id % len(cfg.Targets)
-	target := cfg.Targets[targetIdx]
+	defer transport.CloseIdleConnections()
+	clients := make([]*kvclient.Client, n)
+	trackers := make([]*tracker, n)
+	for i := range clients {
+		trackers[i] = newTracker(ctx, cfg.Origin)
+		client, err := kvclient.New(kvclient.Config{
+			Endpoints:      cfg.Targets,
+			Transport:      transport,
+			OnAttempt:      trackers[i].onAttempt,
+			OnLeaderChange: trackers[i].onLeaderChange,
+		})
+		if err != nil {
+			log.Fatalf("erro ao criar cliente: %v", err)
+		}
+		clients[i] = client
+	}
+	results := make([]clientResult, n)
+	wg := sync.WaitGroup{}
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func(idx int) {
+			defer wg.Done()
+			defer trackers[idx].finish(&results[idx])
+			if cfg.Workload == workloadKV {
+				results[idx] = runKVClient(ctx, first+idx, cfg, clients[idx])
+				return
+			}
+			results[idx] = runClient(ctx, cfg, clients[idx])
+		}(i)
+	}
+	wg.Wait()
+	return results
+}
+
+func runClient(ctx context.Context, cfg runConfig, client *kvclient.Client) clientResult {
 	res := clientResult{
 		LatencySamples: make([]time.Duration, 0, 1024),
+		Latency:        newHistogram(),
 	}
 	payload := make([]byte, cfg.PayloadSize)
 	for {
@@ -137,38 +257,20 @@ func runClient(ctx context.Context, id int, cfg runConfig) clientResult {
 			return res
 		default:
 		}
-		_, _ = rand.Read(payload)
-		begin := time.Now()
-		req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/op", strings.TrimRight(target, "/")), bytes.NewReader(payload))
-		if err != nil {
-			res.Errors++
-			continue
+		if cfg.Compressible {
+			fillCompressible(payload)
+		} else {
+			_, _ = rand.Read(payload)
 		}
-		req = req.WithContext(ctx)
-		resp, err := client.Do(req)
-		if err != nil {
-			res.Errors++
-			targetIdx = (targetIdx + 1) % len(cfg.Targets)
-			target = cfg.Targets[targetIdx]
+		begin := time.Now()
+		if err := client.Append(ctx, payload); err != nil {
+			res.fail(ctx, err)
 			continue
 		}
-		func() {
-			defer resp.Body.Close()
-			if resp.StatusCode == http.StatusConflict {
-				if leader := resp.Header.Get("X-Raft-Leader"); leader != "" {
-					target = leader
-				}
-				res.Errors++
-				return
-			}
-			if resp.StatusCode >= 300 {
-				res.Errors++
-				return
-			}
-			lat := time.Since(begin)
-			res.LatencySamples = append(res.LatencySamples, lat)
-			res.Requests++
-		}()
+		lat := time.Since(begin)
+		res.LatencySamples = append(res.LatencySamples, lat)
+		res.Latency.record(lat)
+		res.Requests++
 		if cfg.Delay > 0 {
 			select {
 			case <-ctx.Done():
@@ -179,6 +281,13 @@ func runClient(ctx context.Context, id int, cfg runConfig) clientResult {
 	}
 }
 
+func fillCompressible(payload []byte) {
+	record := fmt.Sprintf(`{"id":%d,"status":"ok","tags":["load","gen"]},`, rand.Intn(1_000_000))
+	for i := 0; i < len(payload); {
+		i += copy(payload[i:], record)
+	}
+}
+
 func aggregate(results []clientResult, runtime time.Duration) aggregatedMetrics {
 	totalReq := 0
 	totalErr := 0
@@ -195,12 +304,14 @@ func aggregate(results []clientResult, runtime time.Duration) aggregatedMetrics
 			ThroughputOps: throughput(r.Requests, runtime),
 			AvgLatencyMs:  avg,
 			Errors:        r.Errors,
+			Failures:      r.Failures,
+			Redirects:     r.Redirects,
 		}
 	}
 	avgLat := averageLatency(latencies)
 	percentiles := computePercentiles(latencies)
 	cdf := buildCDF(latencies, 100)
-	return aggregatedMetrics{
+	metrics := aggregatedMetrics{
 		TotalRequests: totalReq,
 		ErrorCount:    totalErr,
 		Throughput:    throughput(totalReq, runtime),
@@ -210,6 +321,8 @@ func aggregate(results []clientResult, runtime time.Duration) aggregatedMetrics
 		PercentilesMs: percentiles,
 		CDF:           cdf,
 	}
+	applyBreakdown(&metrics, results)
+	return metrics
 }
 
 func throughput(reqs int, runtime time.Duration) float64 {
@@ -317,6 +430,25 @@ func writeLatencies(path string, data aggregatedMetrics) error {
 func printSummary(metrics aggregatedMetrics) {
 	log.Printf("requisições totais: %d", metrics.TotalRequests)
 	log.Printf("erros: %d", metrics.ErrorCount)
+	for _, cause := range sortedKeys(metrics.Failures) {
+		log.Printf("  %s: %d", cause, metrics.Failures[cause])
+	}
+	log.Printf("redirecionamentos: %d", metrics.Redirects)
+	for _, addr := range sortedKeys(metrics.Targets) {
+		t := metrics.Targets[addr]
+		var causes []string
+		for _, cause := range sortedKeys(t.Failures) {
+			causes = append(causes, fmt.Sprintf("%s=%d", cause, t.Failures[cause]))
+		}
+		log.Printf("alvo %s: %d tentativas, %d sucessos, %d redirecionamentos, latência média %.2f ms, falhas [%s]",
+			addr, t.Attempts, t.Successes, t.Redirects, t.AvgLatencyMs, strings.Join(causes, " "))
+	}
+	if len(metrics.Unavailability) > 0 {
+		log.Printf("sem líder conhecido: %d janelas, %.0f ms no total", len(metrics.Unavailability), metrics.UnavailableMs)
+		for _, w := range metrics.Unavailability {
+			log.Printf("  %.0f ms a %.0f ms", w.StartMs, w.EndMs)
+		}
+	}
 	log.Printf("vazão média: %.2f ops/s", metrics.Throughput)
 	log.Printf("latência média: %.2f ms", metrics.AvgLatencyMs)
 	for name, value := range metrics.PercentilesMs {
@@ -324,6 +456,15 @@ func printSummary(metrics aggregatedMetrics) {
 	}
 }
 
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func splitAndTrim(s string) []string {
 	items := strings.Split(s, ",")
 	out := make([]string, 0, len(items))
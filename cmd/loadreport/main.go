@@ -0,0 +1,311 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+type cdfPoint struct {
+	LatencyMs   float64 `json:"latency_ms"`
+	Probability float64 `json:"probability"`
+}
+
+type aggregatedMetrics struct {
+	TotalRequests int                `json:"total_requests"`
+	Throughput    float64            `json:"throughput_ops"`
+	AvgLatencyMs  float64            `json:"avg_latency_ms"`
+	DurationSec   float64            `json:"duration_sec"`
+	PercentilesMs map[string]float64 `json:"percentiles_ms"`
+	CDF           []cdfPoint         `json:"cdf"`
+	ErrorCount    int                `json:"error_count"`
+	ClientCount   int                `json:"client_count"`
+	PayloadBytes  int                `json:"payload_bytes"`
+	DelayMs       float64            `json:"delay_ms"`
+	Workload      string             `json:"workload,omitempty"`
+	Failures      map[string]int     `json:"failures,omitempty"`
+	Redirects     int                `json:"redirects"`
+	UnavailableMs float64            `json:"unavailable_ms"`
+}
+
+type run struct {
+	Path    string
+	Metrics aggregatedMetrics
+}
+
+type groupKey struct {
+	Workload     string
+	ClientCount  int
+	PayloadBytes int
+	DelayMs      float64
+}
+
+func (k groupKey) String() string {
+	s := fmt.Sprintf("c=%d p=%dB", k.ClientCount, k.PayloadBytes)
+	if k.DelayMs > 0 {
+		s += fmt.Sprintf(" d=%gms", k.DelayMs)
+	}
+	if k.Workload != "" {
+		s = k.Workload + " " + s
+	}
+	return s
+}
+
+func (k groupKey) series() string {
+	s := fmt.Sprintf("payload %dB", k.PayloadBytes)
+	if k.DelayMs > 0 {
+		s += fmt.Sprintf(", delay %gms", k.DelayMs)
+	}
+	if k.Workload != "" {
+		s = k.Workload + ", " + s
+	}
+	return s
+}
+
+func compareKeys(a, b groupKey) int {
+	if c := cmp.Compare(a.Workload, b.Workload); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.PayloadBytes, b.PayloadBytes); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(a.DelayMs, b.DelayMs); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.ClientCount, b.ClientCount)
+}
+
+type metric struct {
+	Name           string
+	Unit           string
+	HigherIsBetter bool
+	Value          func(aggregatedMetrics) float64
+}
+
+var metrics = []metric{
+	{"vazão", "ops/s", true, func(m aggregatedMetrics) float64 { return m.Throughput }},
+	{"latência média", "ms", false, func(m aggregatedMetrics) float64 { return m.AvgLatencyMs }},
+	{"p50", "ms", false, func(m aggregatedMetrics) float64 { return m.PercentilesMs["p50"] }},
+	{"p95", "ms", false, func(m aggregatedMetrics) float64 { return m.PercentilesMs["p95"] }},
+	{"p99", "ms", false, func(m aggregatedMetrics) float64 { return m.PercentilesMs["p99"] }},
+}
+
+type group struct {
+	Key   groupKey
+	Runs  []run
+	Stats []summary
+	CDF   []cdfPoint
+}
+
+func main() {
+	var (
+		baseFlag      = flag.String("base", "", "conjunto de resultados de referência (diretório ou lista de arquivos separada por vírgula)")
+		headFlag      = flag.String("head", "", "conjunto de resultados a comparar com --base")
+		thresholdFlag = flag.Float64("threshold", 0.05, "piora relativa a partir da qual uma diferença é considerada regressão")
+		alphaFlag     = flag.Float64("alpha", 0.05, "nível de significância do teste t de Welch")
+		outMDFlag     = flag.String("out-md", "", "arquivo markdown para o relatório (padrão: saída padrão)")
+		outSVGFlag    = flag.String("out-svg", "", "diretório para os gráficos SVG de vazão x latência e CDF")
+	)
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "uso: %s [flags] <diretório|arquivo.json>...\n       %s [flags] --base <resultados> --head <resultados>\n", os.Args[0], os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	diffMode := *baseFlag != "" || *headFlag != ""
+	if diffMode && (*baseFlag == "" || *headFlag == "") {
+		log.Fatalf("--base e --head devem ser informados juntos")
+	}
+	if !diffMode && flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	var (
+		report     strings.Builder
+		regression bool
+	)
+	if diffMode {
+		base, err := loadGroups(splitAndTrim(*baseFlag))
+		if err != nil {
+			log.Fatalf("erro ao carregar --base: %v", err)
+		}
+		head, err := loadGroups(splitAndTrim(*headFlag))
+		if err != nil {
+			log.Fatalf("erro ao carregar --head: %v", err)
+		}
+		regression = writeDiff(&report, base, head, *thresholdFlag, *alphaFlag)
+		if *outSVGFlag != "" {
+			if err := writeCharts(*outSVGFlag, []namedGroups{{"base", base}, {"head", head}}); err != nil {
+				log.Fatalf("erro ao gerar gráficos: %v", err)
+			}
+		}
+	} else {
+		groups, err := loadGroups(flag.Args())
+		if err != nil {
+			log.Fatalf("erro ao carregar resultados: %v", err)
+		}
+		writeSummary(&report, groups)
+		if *outSVGFlag != "" {
+			if err := writeCharts(*outSVGFlag, []namedGroups{{"", groups}}); err != nil {
+				log.Fatalf("erro ao gerar gráficos: %v", err)
+			}
+		}
+	}
+	if *outMDFlag == "" {
+		fmt.Print(report.String())
+	} else if err := writeFile(*outMDFlag, []byte(report.String())); err != nil {
+		log.Fatalf("erro ao escrever relatório: %v", err)
+	}
+	if regression {
+		log.Printf("regressão de desempenho detectada")
+		os.Exit(1)
+	}
+}
+
+func loadGroups(sources []string) ([]*group, error) {
+	runs, err := loadRuns(sources)
+	if err != nil {
+		return nil, err
+	}
+	if len(runs) == 0 {
+		return nil, errors.New("nenhum resultado encontrado")
+	}
+	byKey := make(map[groupKey]*group)
+	var groups []*group
+	for _, r := range runs {
+		k := groupKey{
+			Workload:     r.Metrics.Workload,
+			ClientCount:  r.Metrics.ClientCount,
+			PayloadBytes: r.Metrics.PayloadBytes,
+			DelayMs:      r.Metrics.DelayMs,
+		}
+		g, ok := byKey[k]
+		if !ok {
+			g = &group{Key: k}
+			byKey[k] = g
+			groups = append(groups, g)
+		}
+		g.Runs = append(g.Runs, r)
+	}
+	slices.SortFunc(groups, func(a, b *group) int { return compareKeys(a.Key, b.Key) })
+	for _, g := range groups {
+		g.Stats = make([]summary, len(metrics))
+		for i, m := range metrics {
+			xs := make([]float64, 0, len(g.Runs))
+			for _, r := range g.Runs {
+				xs = append(xs, m.Value(r.Metrics))
+			}
+			g.Stats[i] = summarize(xs)
+		}
+		g.CDF = meanCDF(g.Runs)
+	}
+	return groups, nil
+}
+
+func loadRuns(sources []string) ([]run, error) {
+	var runs []run
+	for _, src := range sources {
+		info, err := os.Stat(src)
+		if err != nil {
+			return nil, err
+		}
+		paths := []string{src}
+		if info.IsDir() {
+			if paths, err = filepath.Glob(filepath.Join(src, "*.json")); err != nil {
+				return nil, err
+			}
+			slices.Sort(paths)
+		}
+		for _, path := range paths {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			var m aggregatedMetrics
+			if err := json.Unmarshal(data, &m); err != nil {
+				if info.IsDir() {
+					log.Printf("ignorando %s: %v", path, err)
+					continue
+				}
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+			runs = append(runs, run{Path: path, Metrics: m})
+		}
+	}
+	return runs, nil
+}
+
+func meanCDF(runs []run) []cdfPoint {
+	n := 0
+	for _, r := range runs {
+		if len(r.Metrics.CDF) == 0 {
+			continue
+		}
+		if n == 0 || len(r.Metrics.CDF) < n {
+			n = len(r.Metrics.CDF)
+		}
+	}
+	if n == 0 {
+		return nil
+	}
+	out := make([]cdfPoint, n)
+	count := 0
+	for _, r := range runs {
+		if len(r.Metrics.CDF) == 0 {
+			continue
+		}
+		count++
+		for i := range out {
+			src := r.Metrics.CDF[i*len(r.Metrics.CDF)/n]
+			out[i].LatencyMs += src.LatencyMs
+			out[i].Probability += src.Probability
+		}
+	}
+	for i := range out {
+		out[i].LatencyMs /= float64(count)
+		out[i].Probability /= float64(count)
+	}
+	return out
+}
+
+func splitAndTrim(s string) []string {
+	items := strings.Split(s, ",")
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		out = append(out, item)
+	}
+	return out
+}
+
+func writeFile(path string, data []byte) error {
+	if dir := filepath.Dir(path); dir != "" && dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(path, data, 0o644)
+}
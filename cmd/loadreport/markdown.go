@@ -0,0 +1,174 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+func writeSummary(w *strings.Builder, groups []*group) {
+	fmt.Fprintf(w, "# resumo dos resultados\n\n")
+	fmt.Fprintf(w, "médias entre repetições com intervalo de confiança de 95%% (t de Student).\n\n")
+	w.WriteString("| configuração | execuções |")
+	for _, m := range metrics {
+		fmt.Fprintf(w, " %s (%s) |", m.Name, m.Unit)
+	}
+	w.WriteString(" erros |\n|---|---|")
+	for range metrics {
+		w.WriteString("---|")
+	}
+	w.WriteString("---|\n")
+	for _, g := range groups {
+		fmt.Fprintf(w, "| %s | %d |", g.Key, len(g.Runs))
+		for _, s := range g.Stats {
+			fmt.Fprintf(w, " %s |", formatSummary(s))
+		}
+		errs := 0
+		for _, r := range g.Runs {
+			errs += r.Metrics.ErrorCount
+		}
+		fmt.Fprintf(w, " %d |\n", errs)
+	}
+	writeFailures(w, groups)
+}
+
+func writeFailures(w *strings.Builder, groups []*group) {
+	causes := make(map[string]bool)
+	for _, g := range groups {
+		for _, r := range g.Runs {
+			for cause := range r.Metrics.Failures {
+				causes[cause] = true
+			}
+		}
+	}
+	names := make([]string, 0, len(causes))
+	for cause := range causes {
+		names = append(names, cause)
+	}
+	slices.Sort(names)
+	fmt.Fprintf(w, "\n## erros por causa\n\n")
+	fmt.Fprintf(w, "médias por execução. redirecionamentos seguidos pelo cliente não contam como erro.\n\n")
+	w.WriteString("| configuração | redirecionamentos | sem líder (ms) |")
+	for _, cause := range names {
+		fmt.Fprintf(w, " %s |", cause)
+	}
+	w.WriteString("\n|---|---|---|")
+	for range names {
+		w.WriteString("---|")
+	}
+	w.WriteString("\n")
+	for _, g := range groups {
+		n := float64(len(g.Runs))
+		var redirects, unavailable float64
+		for _, r := range g.Runs {
+			redirects += float64(r.Metrics.Redirects)
+			unavailable += r.Metrics.UnavailableMs
+		}
+		fmt.Fprintf(w, "| %s | %.1f | %.0f |", g.Key, redirects/n, unavailable/n)
+		for _, cause := range names {
+			total := 0
+			for _, r := range g.Runs {
+				total += r.Metrics.Failures[cause]
+			}
+			fmt.Fprintf(w, " %.1f |", float64(total)/n)
+		}
+		w.WriteString("\n")
+	}
+}
+
+func writeDiff(w *strings.Builder, base, head []*group, threshold, alpha float64) bool {
+	fmt.Fprintf(w, "# comparação de resultados\n\n")
+	fmt.Fprintf(w, "regressão: piora maior que %.1f%% com p < %g no teste t de Welch (ou sem repetições suficientes para o teste).\n\n", threshold*100, alpha)
+	w.WriteString("| configuração | métrica | base | head | variação | p-valor | veredito |\n")
+	w.WriteString("|---|---|---|---|---|---|---|\n")
+	byKey := make(map[groupKey]*group, len(base))
+	for _, g := range base {
+		byKey[g.Key] = g
+	}
+	regression := false
+	var missing []string
+	for _, h := range head {
+		b, ok := byKey[h.Key]
+		if !ok {
+			missing = append(missing, h.Key.String())
+			continue
+		}
+		delete(byKey, h.Key)
+		for i, m := range metrics {
+			bs, hs := b.Stats[i], h.Stats[i]
+			verdict, change := compare(m, bs, hs, threshold, alpha)
+			if verdict == "regressão" {
+				regression = true
+				verdict = "**regressão**"
+			}
+			_, _, p := welchTTest(bs, hs)
+			fmt.Fprintf(w, "| %s | %s | %s | %s | %+.1f%% | %s | %s |\n",
+				h.Key, m.Name, formatSummary(bs), formatSummary(hs), change*100, formatP(p), verdict)
+		}
+	}
+	if len(missing) > 0 {
+		fmt.Fprintf(w, "\nconfigurações ausentes em base: %s\n", strings.Join(missing, ", "))
+	}
+	if len(byKey) > 0 {
+		var rest []string
+		for _, g := range base {
+			if _, ok := byKey[g.Key]; ok {
+				rest = append(rest, g.Key.String())
+			}
+		}
+		fmt.Fprintf(w, "\nconfigurações ausentes em head: %s\n", strings.Join(rest, ", "))
+	}
+	return regression
+}
+
+func compare(m metric, base, head summary, threshold, alpha float64) (verdict string, change float64) {
+	if base.Mean == 0 {
+		return "sem referência", 0
+	}
+	change = (head.Mean - base.Mean) / base.Mean
+	worse := change
+	if m.HigherIsBetter {
+		worse = -change
+	}
+	_, _, p := welchTTest(base, head)
+	significant := math.IsNaN(p) || p < alpha
+	switch {
+	case worse > threshold && significant:
+		return "regressão", change
+	case worse < -threshold && significant:
+		return "melhora", change
+	}
+	return "igual", change
+}
+
+func formatSummary(s summary) string {
+	if s.N < 2 {
+		return fmt.Sprintf("%.2f", s.Mean)
+	}
+	return fmt.Sprintf("%.2f ± %.2f", s.Mean, s.CI95)
+}
+
+func formatP(p float64) string {
+	if math.IsNaN(p) {
+		return "n/d"
+	}
+	if p < 0.001 {
+		return "<0.001"
+	}
+	return fmt.Sprintf("%.3f", p)
+}
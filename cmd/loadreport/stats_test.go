@@ -0,0 +1,104 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegIncBeta(t *testing.T) {
+	for _, tt := range []struct {
+		a, b, x float64
+		want    float64
+	}{
+		{2, 3, 0, 0},
+		{2, 3, 1, 1},
+		// I_x(a, 1) = x^a and I_x(1, b) = 1 - (1-x)^b.
+		{3, 1, 0.7, 0.343},
+		{1, 4, 0.2, 1 - 0.4096},
+		// Symmetric around 1/2.
+		{7.5, 7.5, 0.5, 0.5},
+		// I_x(2, 3) = 6x²(1-x)² + 4x³(1-x) + x⁴.
+		{2, 3, 0.4, 0.5248},
+		{2, 3, 0.9, 0.9963},
+	} {
+		require.InDelta(t, tt.want, regIncBeta(tt.a, tt.b, tt.x), 1e-12, "I_%v(%v, %v)", tt.x, tt.a, tt.b)
+	}
+}
+
+func TestStudentT(t *testing.T) {
+	// Two-sided p-values, which have closed forms for one and two degrees of
+	// freedom.
+	for _, tv := range []float64{0.5, 1, 2, 10} {
+		require.InDelta(t, 1-2/math.Pi*math.Atan(tv), studentTTwoSided(tv, 1), 1e-12)
+		require.InDelta(t, 1-tv/math.Sqrt(2+tv*tv), studentTTwoSided(tv, 2), 1e-12)
+	}
+	require.Zero(t, studentTTwoSided(math.Inf(1), 5))
+
+	// Quantiles from the usual tables.
+	for _, tt := range []struct {
+		p, df float64
+		want  float64
+	}{
+		{0.975, 1, 12.7062},
+		{0.975, 2, 4.3027},
+		{0.975, 5, 2.5706},
+		{0.975, 10, 2.2281},
+		{0.975, 30, 2.0423},
+		{0.95, 10, 1.8125},
+		{0.995, 20, 2.8453},
+		{0.5, 7, 0},
+		{0.025, 5, -2.5706},
+	} {
+		require.InDelta(t, tt.want, studentTQuantile(tt.p, tt.df), 1e-4, "p=%v df=%v", tt.p, tt.df)
+	}
+}
+
+func TestWelchTTest(t *testing.T) {
+	// The first example of the Wikipedia article on Welch's t-test.
+	a := summarize([]float64{27.5, 21.0, 19.0, 23.6, 17.0, 17.9, 16.9, 20.1, 21.9, 22.6, 23.1, 19.6, 19.0, 21.7, 21.4})
+	b := summarize([]float64{27.1, 22.0, 20.8, 23.4, 23.4, 23.5, 25.8, 22.0, 24.8, 20.2, 21.9, 22.1, 22.9, 20.5, 24.4})
+	tv, df, p := welchTTest(a, b)
+	require.InDelta(t, 2.4554, tv, 1e-4)
+	require.InDelta(t, 24.9885, df, 1e-4)
+	require.InDelta(t, 0.02138, p, 1e-5)
+
+	// With equal sizes and variances, df = 2(n-1), and for n = 2 the p-value
+	// has a closed form.
+	tv, df, p = welchTTest(summary{N: 2, StdDev: 1}, summary{N: 2, Mean: 2, StdDev: 1})
+	require.InDelta(t, 2, tv, 1e-12)
+	require.InDelta(t, 2, df, 1e-12)
+	require.InDelta(t, 1-2/math.Sqrt(6), p, 1e-12)
+
+	// Degenerate inputs.
+	_, _, p = welchTTest(summary{N: 1}, b)
+	require.True(t, math.IsNaN(p))
+	_, _, p = welchTTest(summary{N: 3, Mean: 1}, summary{N: 3, Mean: 1})
+	require.Equal(t, 1.0, p)
+	_, _, p = welchTTest(summary{N: 3, Mean: 1}, summary{N: 3, Mean: 2})
+	require.Zero(t, p)
+}
+
+func TestSummarize(t *testing.T) {
+	s := summarize([]float64{2, 4, 4, 4, 5, 5, 7, 9})
+	require.Equal(t, 8, s.N)
+	require.InDelta(t, 5, s.Mean, 1e-12)
+	require.InDelta(t, math.Sqrt(32.0/7), s.StdDev, 1e-12)
+	// t(0.975, 7) = 2.3646.
+	require.InDelta(t, 2.3646*s.StdDev/math.Sqrt(8), s.CI95, 1e-4)
+}
@@ -0,0 +1,211 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+type namedGroups struct {
+	Name   string
+	Groups []*group
+}
+
+type plotPoint struct {
+	X, Y       float64
+	ErrX, ErrY float64
+	Label      string
+}
+
+type plotSeries struct {
+	Name    string
+	Points  []plotPoint
+	Dashed  bool
+	Markers bool
+}
+
+var palette = []string{"#1f77b4", "#ff7f0e", "#2ca02c", "#d62728", "#9467bd", "#8c564b", "#e377c2", "#7f7f7f", "#bcbd22", "#17becf"}
+
+const (
+	plotWidth   = 800
+	plotHeight  = 500
+	marginLeft  = 70
+	marginRight = 220
+	marginTop   = 40
+	marginBot   = 55
+)
+
+func writeCharts(dir string, sets []namedGroups) error {
+	var tput, cdf []plotSeries
+	for _, set := range sets {
+		prefix := ""
+		if set.Name != "" {
+			prefix = set.Name + ": "
+		}
+		dashed := set.Name == "head"
+		bySeries := make(map[string]*plotSeries)
+		var order []string
+		for _, g := range set.Groups {
+			name := g.Key.series()
+			s, ok := bySeries[name]
+			if !ok {
+				s = &plotSeries{Name: prefix + name, Dashed: dashed, Markers: true}
+				bySeries[name] = s
+				order = append(order, name)
+			}
+			s.Points = append(s.Points, plotPoint{
+				X:     g.Stats[0].Mean,
+				Y:     g.Stats[1].Mean,
+				ErrX:  g.Stats[0].CI95,
+				ErrY:  g.Stats[1].CI95,
+				Label: fmt.Sprintf("c=%d", g.Key.ClientCount),
+			})
+			if len(g.CDF) > 0 {
+				c := plotSeries{Name: prefix + g.Key.String(), Dashed: dashed}
+				for _, p := range g.CDF {
+					c.Points = append(c.Points, plotPoint{X: p.LatencyMs, Y: p.Probability})
+				}
+				cdf = append(cdf, c)
+			}
+		}
+		for _, name := range order {
+			tput = append(tput, *bySeries[name])
+		}
+	}
+	charts := []struct {
+		file, svg string
+	}{
+		{"vazao_vs_latencia.svg", renderPlot("vazão × latência", "vazão (ops/s)", "latência média (ms)", tput)},
+		{"cdf.svg", renderPlot("CDF da latência", "latência (ms)", "probabilidade", cdf)},
+	}
+	for _, c := range charts {
+		if err := writeFile(filepath.Join(dir, c.file), []byte(c.svg)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func renderPlot(title, xlabel, ylabel string, series []plotSeries) string {
+	minX, maxX := math.Inf(1), math.Inf(-1)
+	minY, maxY := math.Inf(1), math.Inf(-1)
+	for _, s := range series {
+		for _, p := range s.Points {
+			minX, maxX = math.Min(minX, p.X-p.ErrX), math.Max(maxX, p.X+p.ErrX)
+			minY, maxY = math.Min(minY, p.Y-p.ErrY), math.Max(maxY, p.Y+p.ErrY)
+		}
+	}
+	if math.IsInf(minX, 1) {
+		minX, maxX, minY, maxY = 0, 1, 0, 1
+	}
+	xticks, x0, x1 := niceTicks(math.Min(minX, 0), maxX)
+	yticks, y0, y1 := niceTicks(math.Min(minY, 0), maxY)
+	iw := float64(plotWidth - marginLeft - marginRight)
+	ih := float64(plotHeight - marginTop - marginBot)
+	px := func(x float64) float64 { return marginLeft + (x-x0)/(x1-x0)*iw }
+	py := func(y float64) float64 { return marginTop + ih - (y-y0)/(y1-y0)*ih }
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" font-family="sans-serif" font-size="12">`+"\n", plotWidth, plotHeight)
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="white"/>`+"\n")
+	fmt.Fprintf(&b, `<text x="%d" y="24" font-size="16" text-anchor="middle">%s</text>`+"\n", marginLeft+int(iw)/2, html.EscapeString(title))
+	for _, t := range xticks {
+		fmt.Fprintf(&b, `<line x1="%.1f" y1="%d" x2="%.1f" y2="%.1f" stroke="#ddd"/>`+"\n", px(t), marginTop, px(t), marginTop+ih)
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="middle">%s</text>`+"\n", px(t), marginTop+ih+18, formatTick(t))
+	}
+	for _, t := range yticks {
+		fmt.Fprintf(&b, `<line x1="%d" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#ddd"/>`+"\n", marginLeft, py(t), marginLeft+iw, py(t))
+		fmt.Fprintf(&b, `<text x="%d" y="%.1f" text-anchor="end" dominant-baseline="middle">%s</text>`+"\n", marginLeft-6, py(t), formatTick(t))
+	}
+	fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%.1f" height="%.1f" fill="none" stroke="black"/>`+"\n", marginLeft, marginTop, iw, ih)
+	fmt.Fprintf(&b, `<text x="%.1f" y="%d" text-anchor="middle">%s</text>`+"\n", marginLeft+iw/2, plotHeight-12, html.EscapeString(xlabel))
+	fmt.Fprintf(&b, `<text transform="translate(16 %.1f) rotate(-90)" text-anchor="middle">%s</text>`+"\n", marginTop+ih/2, html.EscapeString(ylabel))
+
+	for i, s := range series {
+		color := palette[i%len(palette)]
+		dash := ""
+		if s.Dashed {
+			dash = ` stroke-dasharray="6 4"`
+		}
+		pts := slices.Clone(s.Points)
+		slices.SortStableFunc(pts, func(a, b plotPoint) int {
+			switch {
+			case a.X < b.X:
+				return -1
+			case a.X > b.X:
+				return 1
+			}
+			return 0
+		})
+		coords := make([]string, 0, len(pts))
+		for _, p := range pts {
+			coords = append(coords, fmt.Sprintf("%.1f,%.1f", px(p.X), py(p.Y)))
+		}
+		fmt.Fprintf(&b, `<polyline fill="none" stroke="%s" stroke-width="1.5"%s points="%s"/>`+"\n", color, dash, strings.Join(coords, " "))
+		if s.Markers {
+			for _, p := range pts {
+				if p.ErrX > 0 {
+					fmt.Fprintf(&b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s"/>`+"\n", px(p.X-p.ErrX), py(p.Y), px(p.X+p.ErrX), py(p.Y), color)
+				}
+				if p.ErrY > 0 {
+					fmt.Fprintf(&b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s"/>`+"\n", px(p.X), py(p.Y-p.ErrY), px(p.X), py(p.Y+p.ErrY), color)
+				}
+				fmt.Fprintf(&b, `<circle cx="%.1f" cy="%.1f" r="3.5" fill="%s"/>`+"\n", px(p.X), py(p.Y), color)
+				if p.Label != "" {
+					fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" font-size="10">%s</text>`+"\n", px(p.X)+5, py(p.Y)-5, html.EscapeString(p.Label))
+				}
+			}
+		}
+		ly := marginTop + 10 + i*18
+		lx := plotWidth - marginRight + 15
+		fmt.Fprintf(&b, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s" stroke-width="2"%s/>`+"\n", lx, ly, lx+24, ly, color, dash)
+		fmt.Fprintf(&b, `<text x="%d" y="%d" dominant-baseline="middle">%s</text>`+"\n", lx+30, ly, html.EscapeString(s.Name))
+	}
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+func niceTicks(lo, hi float64) (ticks []float64, min, max float64) {
+	if hi <= lo {
+		hi = lo + 1
+	}
+	raw := (hi - lo) / 5
+	mag := math.Pow(10, math.Floor(math.Log10(raw)))
+	step := mag
+	for _, m := range []float64{1, 2, 2.5, 5, 10} {
+		if m*mag >= raw {
+			step = m * mag
+			break
+		}
+	}
+	min = math.Floor(lo/step) * step
+	max = math.Ceil(hi/step) * step
+	for t := min; t <= max+step/2; t += step {
+		ticks = append(ticks, t)
+	}
+	return ticks, min, max
+}
+
+func formatTick(v float64) string {
+	if math.Abs(v) < 1e-9 {
+		return "0"
+	}
+	return strings.TrimRight(strings.TrimRight(fmt.Sprintf("%.3f", v), "0"), ".")
+}
@@ -0,0 +1,148 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"go.etcd.io/raft/v3/kvclient"
+)
+
+func main() {
+	var (
+		endpointsFlag   = flag.String("endpoints", "http://127.0.0.1:9001", "lista de endpoints HTTP separados por vírgula")
+		consistencyFlag = flag.String("consistency", "linearizable", "consistência das leituras: linearizable, leader ou stale")
+		timeoutFlag     = flag.Duration("timeout", 10*time.Second, "tempo máximo do comando, incluindo novas tentativas")
+		retriesFlag     = flag.Int("retries", 10, "número máximo de novas tentativas")
+	)
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), `uso: %s [flags] <comando> [argumentos]
+
+comandos:
+  status              mostra o estado de cada réplica
+  leader              mostra o endereço do líder
+  get <chave>         lê o valor de uma chave
+  put <chave> [valor] grava um valor (lido da entrada padrão se omitido)
+  append [payload]    acrescenta um payload ao log via /op
+
+`, os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	var endpoints []string
+	for _, ep := range strings.Split(*endpointsFlag, ",") {
+		if ep = strings.TrimSpace(ep); ep != "" {
+			endpoints = append(endpoints, ep)
+		}
+	}
+	retries := *retriesFlag
+	if retries == 0 {
+		retries = -1
+	}
+	client, err := kvclient.New(kvclient.Config{
+		Endpoints:  endpoints,
+		MaxRetries: retries,
+	})
+	if err != nil {
+		log.Fatalf("erro ao criar cliente: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), *timeoutFlag)
+	defer cancel()
+	args := flag.Args()
+	switch args[0] {
+	case "status":
+		failed := false
+		for _, ep := range endpoints {
+			st, err := client.Status(ctx, ep)
+			if err != nil {
+				fmt.Printf("%s\terro: %v\n", ep, err)
+				failed = true
+				continue
+			}
+			data, _ := json.Marshal(st)
+			fmt.Printf("%s\t%s\n", ep, data)
+		}
+		if failed {
+			os.Exit(1)
+		}
+	case "leader":
+		leader, err := client.Leader(ctx)
+		if err != nil {
+			log.Fatalf("erro ao descobrir o líder: %v", err)
+		}
+		fmt.Println(leader)
+	case "get":
+		if len(args) != 2 {
+			log.Fatalf("uso: get <chave>")
+		}
+		consistency, err := kvclient.ParseConsistency(*consistencyFlag)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		value, found, err := client.Get(ctx, args[1], consistency)
+		if err != nil {
+			log.Fatalf("erro na leitura: %v", err)
+		}
+		if !found {
+			log.Printf("chave %q não encontrada", args[1])
+			os.Exit(1)
+		}
+		os.Stdout.Write(value)
+		fmt.Println()
+	case "put":
+		if len(args) != 2 && len(args) != 3 {
+			log.Fatalf("uso: put <chave> [valor]")
+		}
+		value, err := argOrStdin(args[2:])
+		if err != nil {
+			log.Fatalf("erro ao ler valor: %v", err)
+		}
+		if err := client.Put(ctx, args[1], value); err != nil {
+			log.Fatalf("erro na escrita: %v", err)
+		}
+	case "append":
+		if len(args) > 2 {
+			log.Fatalf("uso: append [payload]")
+		}
+		payload, err := argOrStdin(args[1:])
+		if err != nil {
+			log.Fatalf("erro ao ler payload: %v", err)
+		}
+		if err := client.Append(ctx, payload); err != nil {
+			log.Fatalf("erro no append: %v", err)
+		}
+	default:
+		log.Fatalf("comando desconhecido %q", args[0])
+	}
+}
+
+func argOrStdin(args []string) ([]byte, error) {
+	if len(args) > 0 {
+		return []byte(args[0]), nil
+	}
+	return io.ReadAll(os.Stdin)
+}
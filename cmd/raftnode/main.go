@@ -14,6 +14,7 @@ import (
 	"net/url"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -22,6 +23,7 @@ import (
 
 	"github.com/google/uuid"
 	"go.etcd.io/raft/v3"
+	"go.etcd.io/raft/v3/compression"
 	"go.etcd.io/raft/v3/raftpb"
 )
 
@@ -30,33 +32,95 @@ type peerInfo struct {
 	Addr string
 }
 
+const (
+	opAppend = ""
+	opGet    = "get"
+	opPut    = "put"
+)
+
+const (
+	consistencyLinearizable = "linearizable"
+	consistencyLeader       = "leader"
+	consistencyStale        = "stale"
+)
+
 type proposal struct {
 	ID      string `json:"id"`
+	Op      string `json:"op,omitempty"`
+	Key     string `json:"key,omitempty"`
 	Payload []byte `json:"payload"`
+	Session string `json:"session,omitempty"`
+	Seq     uint64 `json:"seq,omitempty"`
 }
 
 type applyResult struct {
 	ID    string
+	Value []byte
+	Found bool
 	Error error
 }
 
+// errStaleSequence rejects a write whose session already applied a later
+// sequence number. It is answered with 412, so clients don't retry it.
+var errStaleSequence = errors.New("sequência obsoleta")
+
+type sessionState struct {
+	seq    uint64
+	result applyResult
+}
+
 type kvStore struct {
-	mu      sync.RWMutex
-	entries [][]byte
+	mu       sync.RWMutex
+	entries  [][]byte
+	data     map[string][]byte
+	sessions map[string]sessionState
 }
 
 func newKVStore() *kvStore {
 	return &kvStore{
-		entries: make([][]byte, 0, 1024),
+		entries:  make([][]byte, 0, 1024),
+		data:     make(map[string][]byte),
+		sessions: make(map[string]sessionState),
 	}
 }
 
-func (s *kvStore) apply(data []byte) {
+func (s *kvStore) apply(p proposal) applyResult {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	buf := make([]byte, len(data))
-	copy(buf, data)
-	s.entries = append(s.entries, buf)
+	if p.Session != "" {
+		if st, ok := s.sessions[p.Session]; ok && p.Seq <= st.seq {
+			res := st.result
+			res.ID = p.ID
+			if p.Seq < st.seq {
+				res.Error = fmt.Errorf("%w: sequência %d na sessão %s", errStaleSequence, p.Seq, p.Session)
+			}
+			return res
+		}
+	}
+	res := applyResult{ID: p.ID}
+	switch p.Op {
+	case opGet:
+		res.Value, res.Found = s.data[p.Key]
+	case opPut:
+		buf := make([]byte, len(p.Payload))
+		copy(buf, p.Payload)
+		s.data[p.Key] = buf
+	default:
+		buf := make([]byte, len(p.Payload))
+		copy(buf, p.Payload)
+		s.entries = append(s.entries, buf)
+	}
+	if p.Session != "" {
+		s.sessions[p.Session] = sessionState{seq: p.Seq, result: res}
+	}
+	return res
+}
+
+func (s *kvStore) get(key string) ([]byte, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	value, ok := s.data[key]
+	return value, ok
 }
 
 func (s *kvStore) count() int {
@@ -66,12 +130,15 @@ func (s *kvStore) count() int {
 }
 
 type httpTransport struct {
-	id       uint64
-	client   *http.Client
-	peerAddr map[uint64]string
+	id         uint64
+	client     *http.Client
+	peerAddr   map[uint64]string
+	compressor *compression.Compressor
+	rawBytes   atomic.Uint64
+	wireBytes  atomic.Uint64
 }
 
-func newHTTPTransport(id uint64, peers map[uint64]string) *httpTransport {
+func newHTTPTransport(id uint64, peers map[uint64]string, compressor *compression.Compressor) *httpTransport {
 	rt := &http.Transport{
 		MaxIdleConnsPerHost: 32,
 	}
@@ -81,7 +148,8 @@ func newHTTPTransport(id uint64, peers map[uint64]string) *httpTransport {
 			Transport: rt,
 			Timeout:   5 * time.Second,
 		},
-		peerAddr: peers,
+		peerAddr:   peers,
+		compressor: compressor,
 	}
 }
 
@@ -95,11 +163,16 @@ func (t *httpTransport) send(msgs []raftpb.Message) {
 			log.Printf("destino %d desconhecido, descartando mensagem %s", m.To, m.Type.String())
 			continue
 		}
+		t.rawBytes.Add(uint64(m.Size()))
+		if t.compressor != nil {
+			m = t.compressor.Compress(m)
+		}
 		data, err := m.Marshal()
 		if err != nil {
 			log.Printf("falha ao serializar mensagem para %d: %v", m.To, err)
 			continue
 		}
+		t.wireBytes.Add(uint64(len(data)))
 		url := fmt.Sprintf("%s/raft", strings.TrimRight(addr, "/"))
 		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
 		if err != nil {
@@ -133,13 +206,23 @@ type server struct {
 	leaderID   atomic.Uint64
 	peerAddr   map[uint64]string
 	httpServer *http.Server
+	latencies  *raft.ProposalLatencyCollector
 }
 
 func newServer(cfg *nodeConfig) (*server, error) {
 	if cfg.id == 0 {
 		return nil, errors.New("id inválido")
 	}
+	var compressor *compression.Compressor
+	if cfg.compressMinBytes > 0 {
+		var err error
+		compressor, err = compression.New(compression.Config{MinSize: cfg.compressMinBytes})
+		if err != nil {
+			return nil, err
+		}
+	}
 	storage := raft.NewMemoryStorage()
+	latencies := raft.NewProposalLatencyCollector(nil)
 	rcfg := &raft.Config{
 		ID:                        cfg.id,
 		ElectionTick:              cfg.electionTick,
@@ -150,6 +233,7 @@ func newServer(cfg *nodeConfig) (*server, error) {
 		CheckQuorum:               true,
 		PreVote:                   true,
 		DisableProposalForwarding: false,
+		ProposalTracer:            latencies,
 	}
 	node := raft.StartNode(rcfg, cfg.initialPeers)
 	s := &server{
@@ -159,23 +243,25 @@ func newServer(cfg *nodeConfig) (*server, error) {
 		storage:    storage,
 		stopc:      make(chan struct{}),
 		proposeC:   make(chan []byte, 1024),
-		transport:  newHTTPTransport(cfg.id, cfg.peerAddr),
+		transport:  newHTTPTransport(cfg.id, cfg.peerAddr, compressor),
 		store:      newKVStore(),
 		pending:    make(map[string]chan applyResult),
 		peerAddr:   cfg.peerAddr,
+		latencies:  latencies,
 	}
 	return s, nil
 }
 
 type nodeConfig struct {
-	id              uint64
-	httpAddr        string
-	peerAddr        map[uint64]string
-	initialPeers    []raft.Peer
-	electionTick    int
-	heartbeatTick   int
-	maxSizePerMsg   uint64
-	maxInflightMsgs int
+	id               uint64
+	httpAddr         string
+	peerAddr         map[uint64]string
+	initialPeers     []raft.Peer
+	electionTick     int
+	heartbeatTick    int
+	maxSizePerMsg    uint64
+	maxInflightMsgs  int
+	compressMinBytes int
 }
 
 func (s *server) run(ctx context.Context) error {
@@ -189,6 +275,7 @@ func (s *server) run(ctx context.Context) error {
 	})
 	mux.HandleFunc("/status", s.handleStatus)
 	mux.HandleFunc("/op", s.handleOperation)
+	mux.HandleFunc("/kv/", s.handleKV)
 	mux.HandleFunc("/metrics", s.handleMetrics)
 	httpSrv := &http.Server{
 		Addr:    s.listenAddr,
@@ -286,7 +373,7 @@ func (s *server) handleCommittedEntry(data []byte) {
 		log.Printf("entrada inválida: %v", err)
 		return
 	}
-	s.store.apply(p.Payload)
+	res := s.store.apply(p)
 	s.pendingMu.Lock()
 	ch, ok := s.pending[p.ID]
 	if ok {
@@ -295,7 +382,7 @@ func (s *server) handleCommittedEntry(data []byte) {
 	s.pendingMu.Unlock()
 	if ok {
 		select {
-		case ch <- applyResult{ID: p.ID}:
+		case ch <- res:
 		default:
 		}
 	}
@@ -313,6 +400,10 @@ func (s *server) handleRaft(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "mensagem inválida", http.StatusBadRequest)
 		return
 	}
+	if err := compression.Decompress(&msg, 0); err != nil {
+		http.Error(w, fmt.Sprintf("falha ao descomprimir: %v", err), http.StatusBadRequest)
+		return
+	}
 	if err := s.raftNode.Step(r.Context(), msg); err != nil {
 		http.Error(w, fmt.Sprintf("erro ao step: %v", err), http.StatusInternalServerError)
 		return
@@ -324,10 +415,12 @@ func (s *server) handleStatus(w http.ResponseWriter, r *http.Request) {
 	status := s.raftNode.Status()
 	s.leaderID.Store(status.Lead)
 	resp := map[string]any{
-		"id":        s.id,
-		"leader_id": status.Lead,
-		"term":      status.Term,
-		"commit":    status.Commit,
+		"id":          s.id,
+		"addr":        s.peerAddr[s.id],
+		"leader_id":   status.Lead,
+		"leader_addr": s.peerAddr[status.Lead],
+		"term":        status.Term,
+		"commit":      status.Commit,
 	}
 	_ = json.NewEncoder(w).Encode(resp)
 }
@@ -337,12 +430,7 @@ func (s *server) handleOperation(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "método não suportado", http.StatusMethodNotAllowed)
 		return
 	}
-	leader := s.leaderID.Load()
-	if leader != 0 && leader != s.id {
-		if addr := s.peerAddr[leader]; addr != "" {
-			w.Header().Set("X-Raft-Leader", addr)
-		}
-		http.Error(w, "não sou líder", http.StatusConflict)
+	if s.redirectToLeader(w) {
 		return
 	}
 	body, err := io.ReadAll(r.Body)
@@ -350,16 +438,120 @@ func (s *server) handleOperation(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "falha ao ler payload", http.StatusBadRequest)
 		return
 	}
-	id := uuid.NewString()
-	payload := proposal{ID: id, Payload: body}
-	data, err := json.Marshal(payload)
+	p, err := withSession(r, proposal{Op: opAppend, Payload: body})
 	if err != nil {
-		http.Error(w, "erro ao serializar proposta", http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if _, ok := s.proposeAndWait(w, r, p); !ok {
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ok"))
+}
+
+func (s *server) handleKV(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(r.URL.Path, "/kv/")
+	if key == "" {
+		http.Error(w, "chave ausente", http.StatusBadRequest)
+		return
+	}
+	var p proposal
+	switch r.Method {
+	case http.MethodGet:
+		switch r.URL.Query().Get("consistency") {
+		case "", consistencyLinearizable:
+			p = proposal{Op: opGet, Key: key}
+		case consistencyLeader:
+			if s.leaderID.Load() != s.id {
+				if !s.redirectToLeader(w) {
+					http.Error(w, "líder desconhecido", http.StatusConflict)
+				}
+				return
+			}
+			s.writeLocalRead(w, key)
+			return
+		case consistencyStale:
+			s.writeLocalRead(w, key)
+			return
+		default:
+			http.Error(w, "consistência desconhecida", http.StatusBadRequest)
+			return
+		}
+	case http.MethodPut, http.MethodPost:
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "falha ao ler payload", http.StatusBadRequest)
+			return
+		}
+		if p, err = withSession(r, proposal{Op: opPut, Key: key, Payload: body}); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, "método não suportado", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.redirectToLeader(w) {
 		return
 	}
+	res, ok := s.proposeAndWait(w, r, p)
+	if !ok {
+		return
+	}
+	if p.Op == opGet && !res.Found {
+		http.Error(w, "chave não encontrada", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(res.Value)
+}
+
+func (s *server) writeLocalRead(w http.ResponseWriter, key string) {
+	value, ok := s.store.get(key)
+	if !ok {
+		http.Error(w, "chave não encontrada", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(value)
+}
+
+func withSession(r *http.Request, p proposal) (proposal, error) {
+	p.Session = r.Header.Get("X-Session-ID")
+	if p.Session == "" {
+		return p, nil
+	}
+	seq, err := strconv.ParseUint(r.Header.Get("X-Sequence"), 10, 64)
+	if err != nil || seq == 0 {
+		return p, fmt.Errorf("sequência inválida %q", r.Header.Get("X-Sequence"))
+	}
+	p.Seq = seq
+	return p, nil
+}
+
+func (s *server) redirectToLeader(w http.ResponseWriter) bool {
+	leader := s.leaderID.Load()
+	if leader == 0 || leader == s.id {
+		return false
+	}
+	if addr := s.peerAddr[leader]; addr != "" {
+		w.Header().Set("X-Raft-Leader", addr)
+	}
+	http.Error(w, "não sou líder", http.StatusConflict)
+	return true
+}
+
+func (s *server) proposeAndWait(w http.ResponseWriter, r *http.Request, p proposal) (applyResult, bool) {
+	p.ID = uuid.NewString()
+	data, err := json.Marshal(p)
+	if err != nil {
+		http.Error(w, "erro ao serializar proposta", http.StatusInternalServerError)
+		return applyResult{}, false
+	}
 	respCh := make(chan applyResult, 1)
 	s.pendingMu.Lock()
-	s.pending[id] = respCh
+	s.pending[p.ID] = respCh
 	s.pendingMu.Unlock()
 	select {
 	case s.proposeC <- data:
@@ -373,16 +565,20 @@ func (s *server) handleOperation(w http.ResponseWriter, r *http.Request) {
 	select {
 	case res := <-respCh:
 		if res.Error != nil {
-			http.Error(w, res.Error.Error(), http.StatusInternalServerError)
-			return
+			code := http.StatusInternalServerError
+			if errors.Is(res.Error, errStaleSequence) {
+				code = http.StatusPreconditionFailed
+			}
+			http.Error(w, res.Error.Error(), code)
+			return applyResult{}, false
 		}
-		w.WriteHeader(http.StatusOK)
-		w.Write([]byte("ok"))
+		return res, true
 	case <-ctx.Done():
 		s.pendingMu.Lock()
-		delete(s.pending, id)
+		delete(s.pending, p.ID)
 		s.pendingMu.Unlock()
 		http.Error(w, "timeout aguardando commit", http.StatusGatewayTimeout)
+		return applyResult{}, false
 	}
 }
 
@@ -391,14 +587,37 @@ func (s *server) handleMetrics(w http.ResponseWriter, r *http.Request) {
 	pending := len(s.pending)
 	s.pendingMu.Unlock()
 	resp := map[string]any{
-		"id":          s.id,
-		"leader_id":   s.leaderID.Load(),
-		"entries":     s.store.count(),
-		"pending_ops": pending,
+		"id":               s.id,
+		"leader_id":        s.leaderID.Load(),
+		"entries":          s.store.count(),
+		"pending_ops":      pending,
+		"raft_bytes":       s.transport.rawBytes.Load(),
+		"wire_bytes":       s.transport.wireBytes.Load(),
+		"proposal_latency": proposalLatencies(s.latencies.Histograms()),
 	}
 	_ = json.NewEncoder(w).Encode(resp)
 }
 
+func proposalLatencies(hs map[raft.ProposalStage]raft.LatencyHistogram) map[string]any {
+	res := make(map[string]any, len(hs))
+	for stage, h := range hs {
+		buckets := make([]map[string]any, 0, len(h.Counts))
+		for i, count := range h.Counts {
+			le := "+Inf"
+			if i < len(h.Buckets) {
+				le = h.Buckets[i].String()
+			}
+			buckets = append(buckets, map[string]any{"le": le, "count": count})
+		}
+		res[stage.String()] = map[string]any{
+			"count":   h.Count,
+			"mean_ms": float64(h.Mean()) / float64(time.Millisecond),
+			"buckets": buckets,
+		}
+	}
+	return res
+}
+
 func (s *server) stop(ctx context.Context) error {
 	close(s.stopc)
 	s.raftNode.Stop()
@@ -460,9 +679,10 @@ func normalizeAddr(raw string) (listen string, advertise string, err error) {
 
 func main() {
 	var (
-		idFlag    = flag.Uint("id", 1, "identificador único da réplica")
-		addrFlag  = flag.String("addr", "http://127.0.0.1:9001", "endereço http local (formato http://host:porta ou host:porta)")
-		peersFlag = flag.String("peers", "", "lista de peers id=url separados por vírgula")
+		idFlag       = flag.Uint("id", 1, "identificador único da réplica")
+		addrFlag     = flag.String("addr", "http://127.0.0.1:9001", "endereço http local (formato http://host:porta ou host:porta)")
+		peersFlag    = flag.String("peers", "", "lista de peers id=url separados por vírgula")
+		compressFlag = flag.Int("compress-min-bytes", 0, "comprime os dados de entradas a partir deste tamanho ao enviá-las aos peers (0 desativa)")
 	)
 	flag.Parse()
 	listenAddr, advertiseAddr, err := normalizeAddr(*addrFlag)
@@ -485,14 +705,15 @@ func main() {
 		peersList = append(peersList, raft.Peer{ID: uint64(*idFlag)})
 	}
 	cfg := &nodeConfig{
-		id:              uint64(*idFlag),
-		httpAddr:        listenAddr,
-		peerAddr:        peerAddr,
-		initialPeers:    peersList,
-		electionTick:    10,
-		heartbeatTick:   1,
-		maxSizePerMsg:   1 << 20,
-		maxInflightMsgs: 256,
+		id:               uint64(*idFlag),
+		httpAddr:         listenAddr,
+		peerAddr:         peerAddr,
+		initialPeers:     peersList,
+		electionTick:     10,
+		heartbeatTick:    1,
+		maxSizePerMsg:    1 << 20,
+		maxInflightMsgs:  256,
+		compressMinBytes: *compressFlag,
 	}
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
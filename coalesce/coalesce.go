@@ -0,0 +1,139 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package coalesce merges the heartbeats of the raft groups hosted by a
+// process into one message per destination.
+//
+// A process hosting many raft groups sends a MsgHeartbeat per group and per
+// follower on every heartbeat tick, and gets as many MsgHeartbeatResp back,
+// although the groups are spread over a few nodes. Coalescing is applied by
+// the transport: the sender passes the outgoing messages of each group
+// through a Coalescer, which holds their heartbeats and heartbeat responses
+// back, and sends the CoalescedHeartbeats returned by Flush once per tick to
+// each node. The receiver passes them through Split, and steps the messages
+// it returns into their groups, whose responses are coalesced in turn.
+//
+// The heartbeats of a group are delayed until the next Flush, so the
+// transport should flush right after ticking and handling the Ready of all
+// the groups. Raft sends the heartbeats of a group at its own pace, which the
+// Coalescer doesn't change: only the number of messages on the wire drops.
+package coalesce
+
+import (
+	"sync"
+
+	pb "go.etcd.io/raft/v3/raftpb"
+)
+
+// Coalescer holds back the heartbeats and heartbeat responses sent by the
+// raft groups of a process, to send them at once to each destination node. It
+// is safe for concurrent use.
+type Coalescer struct {
+	nodeOf func(group, id uint64) uint64
+
+	mu      sync.Mutex
+	pending map[uint64][]pb.GroupHeartbeat
+}
+
+// New returns a Coalescer. nodeOf returns the node hosting the raft peer id
+// of the given group, which its heartbeats are coalesced for. If nil, the
+// raft peer IDs are used as node IDs, i.e. a node has the same ID in all the
+// groups.
+func New(nodeOf func(group, id uint64) uint64) *Coalescer {
+	if nodeOf == nil {
+		nodeOf = func(_, id uint64) uint64 { return id }
+	}
+	return &Coalescer{nodeOf: nodeOf, pending: map[uint64][]pb.GroupHeartbeat{}}
+}
+
+// Intercept holds back the heartbeats and heartbeat responses among the
+// messages sent by the given group, and returns the other messages, to be
+// sent as usual. It filters msgs in place.
+func (c *Coalescer) Intercept(group uint64, msgs []pb.Message) []pb.Message {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	rest := msgs[:0]
+	for _, m := range msgs {
+		if m.Type != pb.MsgHeartbeat && m.Type != pb.MsgHeartbeatResp {
+			rest = append(rest, m)
+			continue
+		}
+		node := c.nodeOf(group, m.To)
+		c.pending[node] = append(c.pending[node], heartbeatOf(group, m))
+	}
+	return rest
+}
+
+// Flush returns the heartbeats held back since the last call, coalesced by
+// destination node.
+func (c *Coalescer) Flush() map[uint64]pb.CoalescedHeartbeats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.pending) == 0 {
+		return nil
+	}
+	out := make(map[uint64]pb.CoalescedHeartbeats, len(c.pending))
+	for node, hbs := range c.pending {
+		out[node] = pb.CoalescedHeartbeats{Heartbeats: hbs}
+	}
+	c.pending = map[uint64][]pb.GroupHeartbeat{}
+	return out
+}
+
+// GroupMessage is a message of a raft group.
+type GroupMessage struct {
+	GroupID uint64
+	Message pb.Message
+}
+
+// Split returns the heartbeats and heartbeat responses of the groups carried
+// by coalesced heartbeats, to be stepped into their groups.
+func Split(c pb.CoalescedHeartbeats) []GroupMessage {
+	msgs := make([]GroupMessage, len(c.Heartbeats))
+	for i, hb := range c.Heartbeats {
+		msgs[i] = GroupMessage{GroupID: hb.GroupID, Message: messageOf(hb)}
+	}
+	return msgs
+}
+
+func heartbeatOf(group uint64, m pb.Message) pb.GroupHeartbeat {
+	return pb.GroupHeartbeat{
+		GroupID:       group,
+		Type:          m.Type,
+		To:            m.To,
+		From:          m.From,
+		Term:          m.Term,
+		Index:         m.Index,
+		Commit:        m.Commit,
+		Context:       m.Context,
+		SafeReadIndex: m.SafeReadIndex,
+		SafeReadTime:  m.SafeReadTime,
+		Quiesce:       m.Quiesce,
+	}
+}
+
+func messageOf(hb pb.GroupHeartbeat) pb.Message {
+	return pb.Message{
+		Type:          hb.Type,
+		To:            hb.To,
+		From:          hb.From,
+		Term:          hb.Term,
+		Index:         hb.Index,
+		Commit:        hb.Commit,
+		Context:       hb.Context,
+		SafeReadIndex: hb.SafeReadIndex,
+		SafeReadTime:  hb.SafeReadTime,
+		Quiesce:       hb.Quiesce,
+	}
+}
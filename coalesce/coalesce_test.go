@@ -0,0 +1,169 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coalesce
+
+import (
+	"io"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.etcd.io/raft/v3"
+	pb "go.etcd.io/raft/v3/raftpb"
+)
+
+func TestRoundTrip(t *testing.T) {
+	msgs := []pb.Message{
+		{Type: pb.MsgHeartbeat, From: 1, To: 2, Term: 3, Index: 4, Commit: 5, Context: []byte("ctx"),
+			SafeReadIndex: 6, SafeReadTime: 7, Quiesce: true},
+		{Type: pb.MsgApp, From: 1, To: 2, Term: 3, Entries: []pb.Entry{{Term: 3, Index: 6}}},
+		{Type: pb.MsgHeartbeat, From: 1, To: 3, Term: 3, Commit: 5},
+		{Type: pb.MsgHeartbeatResp, From: 1, To: 2, Term: 3, Index: 4, Context: []byte("ctx")},
+	}
+	want := append([]pb.Message(nil), msgs...)
+
+	c := New(nil)
+	rest := c.Intercept(10, msgs)
+	require.Equal(t, want[1:2], rest)
+	require.Empty(t, c.Intercept(11, []pb.Message{want[0]}))
+
+	out := c.Flush()
+	require.Len(t, out, 2)
+	require.Nil(t, c.Flush())
+
+	// The coalesced heartbeats survive the wire.
+	hbs := out[2]
+	data, err := hbs.Marshal()
+	require.NoError(t, err)
+	var got pb.CoalescedHeartbeats
+	require.NoError(t, got.Unmarshal(data))
+	require.Equal(t, []GroupMessage{
+		{GroupID: 10, Message: want[0]},
+		{GroupID: 10, Message: want[3]},
+		{GroupID: 11, Message: want[0]},
+	}, Split(got))
+	require.Equal(t, []GroupMessage{{GroupID: 10, Message: want[2]}}, Split(out[3]))
+}
+
+func TestNodeOf(t *testing.T) {
+	// Node n hosts the peer 10*group+n of each group.
+	c := New(func(group, id uint64) uint64 { return id - 10*group })
+	c.Intercept(1, []pb.Message{{Type: pb.MsgHeartbeat, From: 11, To: 12}})
+	c.Intercept(2, []pb.Message{{Type: pb.MsgHeartbeat, From: 21, To: 22}})
+	out := c.Flush()
+	require.Len(t, out, 1)
+	require.Len(t, out[2].Heartbeats, 2)
+}
+
+// TestGroups runs raft groups over a transport coalescing their heartbeats,
+// and checks that the leaders keep their leadership with a single heartbeat
+// message, and response, between each pair of nodes.
+func TestGroups(t *testing.T) {
+	const groups = 10
+	nodes := []uint64{1, 2, 3}
+	type replica struct {
+		rn *raft.RawNode
+		s  *raft.MemoryStorage
+	}
+	replicas := map[uint64]map[uint64]replica{} // by node and group
+	coalescers := map[uint64]*Coalescer{}
+	for _, n := range nodes {
+		replicas[n] = map[uint64]replica{}
+		coalescers[n] = New(nil)
+		for g := uint64(1); g <= groups; g++ {
+			s := raft.NewMemoryStorage()
+			require.NoError(t, s.ApplySnapshot(pb.Snapshot{Metadata: pb.SnapshotMetadata{
+				Index: 1, Term: 1, ConfState: pb.ConfState{Voters: nodes},
+			}}))
+			rn, err := raft.NewRawNode(&raft.Config{
+				ID: n, ElectionTick: 10, HeartbeatTick: 1, Storage: s,
+				MaxSizePerMsg: 1 << 20, MaxInflightMsgs: 256, CheckQuorum: true,
+				Logger: &raft.DefaultLogger{Logger: log.New(io.Discard, "", 0)},
+			})
+			require.NoError(t, err)
+			replicas[n][g] = replica{rn: rn, s: s}
+		}
+	}
+
+	// stabilize handles the Readys of all the replicas until they have none,
+	// delivering the messages other than heartbeats right away.
+	stabilize := func() {
+		for busy := true; busy; {
+			busy = false
+			for _, n := range nodes {
+				for g, r := range replicas[n] {
+					if !r.rn.HasReady() {
+						continue
+					}
+					busy = true
+					rd := r.rn.Ready()
+					if !raft.IsEmptyHardState(rd.HardState) {
+						require.NoError(t, r.s.SetHardState(rd.HardState))
+					}
+					require.NoError(t, r.s.Append(rd.Entries))
+					for _, m := range coalescers[n].Intercept(g, rd.Messages) {
+						require.NoError(t, replicas[m.To][g].rn.Step(m))
+					}
+					r.rn.Advance(rd)
+				}
+			}
+		}
+	}
+	// flush delivers the coalesced heartbeats, and returns the number of
+	// messages sent.
+	flush := func() int {
+		sent := 0
+		for _, n := range nodes {
+			for to, hbs := range coalescers[n].Flush() {
+				sent++
+				for _, gm := range Split(hbs) {
+					require.NoError(t, replicas[to][gm.GroupID].rn.Step(gm.Message))
+				}
+			}
+		}
+		return sent
+	}
+
+	for g := uint64(1); g <= groups; g++ {
+		require.NoError(t, replicas[1][g].rn.Campaign())
+	}
+	stabilize()
+	flush()
+	stabilize()
+	for g := uint64(1); g <= groups; g++ {
+		require.Equal(t, raft.StateLeader, replicas[1][g].rn.Status().RaftState)
+	}
+
+	for i := 0; i < 50; i++ {
+		for _, n := range nodes {
+			for _, r := range replicas[n] {
+				r.rn.Tick()
+			}
+		}
+		stabilize()
+		// One heartbeat message from the leader to each follower.
+		require.Equal(t, 2, flush())
+		stabilize()
+		// One response from each follower.
+		require.Equal(t, 2, flush())
+		stabilize()
+	}
+	for g := uint64(1); g <= groups; g++ {
+		st := replicas[1][g].rn.Status()
+		require.Equal(t, raft.StateLeader, st.RaftState)
+		require.Equal(t, uint64(1), st.Term)
+	}
+}
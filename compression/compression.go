@@ -0,0 +1,173 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package compression compresses the data of the entries carried by raft
+// messages on the wire.
+//
+// Compression is applied by the transport: the sender passes outgoing
+// messages through a Compressor before marshaling them, and the receiver
+// passes incoming messages through Decompress after unmarshaling them, before
+// stepping them into raft. Raft, its log and its size limits (such as
+// Config.MaxSizePerMsg and Config.MaxInflightBytes) only ever see the
+// uncompressed data, so the limits keep being measured in uncompressed bytes
+// while fewer bytes cross the network. Entry checksums, if enabled, cover the
+// uncompressed data and thus also verify the decompression.
+//
+// Each compressed entry is marked with the codec used in the EntryCodecs
+// field of the message, and entries that don't shrink are sent as they are.
+// A receiver using Decompress thus accepts messages from senders that do and
+// don't compress, which allows enabling compression one peer at a time: first
+// make all peers decompress, then make them compress. Raft refuses to step
+// messages carrying compressed entries (with raft.ErrStepCompressed), so a
+// peer that was wrongly sent such a message doesn't store the compressed data.
+package compression
+
+import (
+	"bytes"
+	"compress/flate"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	pb "go.etcd.io/raft/v3/raftpb"
+)
+
+// Config configures a Compressor.
+type Config struct {
+	// MinSize is the size from which the data of an entry is compressed.
+	// Smaller data rarely shrinks enough to be worth it. Defaults to 256
+	// bytes.
+	MinSize int
+	// Level is the compression level, from flate.BestSpeed to
+	// flate.BestCompression. Defaults to flate.BestSpeed.
+	Level int
+}
+
+const defaultMinSize = 256
+
+// Compressor compresses the data of the entries of outgoing messages. It is
+// safe for concurrent use.
+type Compressor struct {
+	cfg     Config
+	writers sync.Pool
+}
+
+// New returns a Compressor with the given configuration.
+func New(cfg Config) (*Compressor, error) {
+	if cfg.MinSize <= 0 {
+		cfg.MinSize = defaultMinSize
+	}
+	if cfg.Level == 0 {
+		cfg.Level = flate.BestSpeed
+	}
+	if cfg.Level < flate.BestSpeed || cfg.Level > flate.BestCompression {
+		return nil, fmt.Errorf("invalid compression level %d", cfg.Level)
+	}
+	return &Compressor{cfg: cfg}, nil
+}
+
+// Compress returns the message with the data of its entries compressed where
+// it shrinks. The message is not modified, since its entries are usually
+// shared with the raft log.
+func (c *Compressor) Compress(m pb.Message) pb.Message {
+	if len(m.EntryCodecs) != 0 {
+		// Already compressed.
+		return m
+	}
+	var ents []pb.Entry
+	var codecs []pb.EntryCodec
+	for i, e := range m.Entries {
+		if len(e.Data) < c.cfg.MinSize {
+			continue
+		}
+		data, ok := c.compress(e.Data)
+		if !ok {
+			continue
+		}
+		if ents == nil {
+			ents = append([]pb.Entry(nil), m.Entries...)
+			codecs = make([]pb.EntryCodec, len(m.Entries))
+		}
+		ents[i].Data, codecs[i] = data, pb.CodecFlate
+	}
+	if ents != nil {
+		m.Entries, m.EntryCodecs = ents, codecs
+	}
+	return m
+}
+
+// compress compresses data, and returns false if it doesn't shrink.
+func (c *Compressor) compress(data []byte) ([]byte, bool) {
+	var buf bytes.Buffer
+	buf.Grow(len(data) / 2)
+	w, _ := c.writers.Get().(*flate.Writer)
+	if w == nil {
+		// The level was validated by New.
+		w, _ = flate.NewWriter(&buf, c.cfg.Level)
+	} else {
+		w.Reset(&buf)
+	}
+	defer c.writers.Put(w)
+	if _, err := w.Write(data); err != nil {
+		return nil, false
+	}
+	if err := w.Close(); err != nil {
+		return nil, false
+	}
+	if buf.Len() >= len(data) {
+		return nil, false
+	}
+	return buf.Bytes(), true
+}
+
+// ErrTooLarge is returned by Decompress when the data of an entry exceeds the
+// given limit once decompressed.
+var ErrTooLarge = errors.New("compression: decompressed entry too large")
+
+// Decompress restores the data of the compressed entries of the message in
+// place. maxSize bounds the size of the decompressed data of each entry, which
+// protects against corrupted or malicious data; zero means no limit.
+func Decompress(m *pb.Message, maxSize int) error {
+	if len(m.EntryCodecs) == 0 {
+		return nil
+	}
+	if len(m.EntryCodecs) != len(m.Entries) {
+		return fmt.Errorf("compression: %d codecs for %d entries", len(m.EntryCodecs), len(m.Entries))
+	}
+	for i, codec := range m.EntryCodecs {
+		e := &m.Entries[i]
+		switch codec {
+		case pb.CodecNone:
+			continue
+		case pb.CodecFlate:
+		default:
+			return fmt.Errorf("compression: entry %d/%d has unknown codec %s", e.Term, e.Index, codec)
+		}
+		var r io.Reader = flate.NewReader(bytes.NewReader(e.Data))
+		if maxSize > 0 {
+			r = io.LimitReader(r, int64(maxSize)+1)
+		}
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return fmt.Errorf("compression: entry %d/%d: %w", e.Term, e.Index, err)
+		}
+		if maxSize > 0 && len(data) > maxSize {
+			return ErrTooLarge
+		}
+		e.Data, m.EntryCodecs[i] = data, pb.CodecNone
+	}
+	m.EntryCodecs = nil
+	return nil
+}
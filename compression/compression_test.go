@@ -0,0 +1,92 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package compression
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	pb "go.etcd.io/raft/v3/raftpb"
+)
+
+func TestRoundTrip(t *testing.T) {
+	json := bytes.Repeat([]byte(`{"key":"value","count":42},`), 100)
+	random := make([]byte, 1000)
+	_, _ = rand.Read(random)
+	ents := []pb.Entry{
+		{Term: 1, Index: 1, Data: json},
+		{Term: 1, Index: 2, Data: []byte("small")},
+		{Term: 1, Index: 3, Data: random},
+		{Term: 1, Index: 4},
+	}
+	m := pb.Message{Type: pb.MsgApp, Entries: ents}
+
+	c, err := New(Config{})
+	require.NoError(t, err)
+	cm := c.Compress(m)
+	// The entries of the original message are left alone.
+	require.Empty(t, m.EntryCodecs)
+	require.Equal(t, json, m.Entries[0].Data)
+	// Only the compressible entry large enough is compressed.
+	require.Equal(t, []pb.EntryCodec{pb.CodecFlate, pb.CodecNone, pb.CodecNone, pb.CodecNone}, cm.EntryCodecs)
+	require.Less(t, len(cm.Entries[0].Data), len(json)/10)
+	// Compressing again is a no-op.
+	require.Equal(t, cm, c.Compress(cm))
+
+	// The compressed message survives the wire.
+	data, err := cm.Marshal()
+	require.NoError(t, err)
+	var got pb.Message
+	require.NoError(t, got.Unmarshal(data))
+	require.NoError(t, Decompress(&got, 0))
+	require.Equal(t, m, got)
+
+	// Messages without compressed entries are left alone.
+	require.NoError(t, Decompress(&m, 0))
+	require.Equal(t, ents, m.Entries)
+}
+
+func TestDecompressErrors(t *testing.T) {
+	c, err := New(Config{MinSize: 1})
+	require.NoError(t, err)
+	data := bytes.Repeat([]byte("a"), 1000)
+	m := c.Compress(pb.Message{Entries: []pb.Entry{{Data: data}}})
+	require.Equal(t, []pb.EntryCodec{pb.CodecFlate}, m.EntryCodecs)
+
+	tooLarge := m
+	tooLarge.Entries = append([]pb.Entry(nil), m.Entries...)
+	tooLarge.EntryCodecs = append([]pb.EntryCodec(nil), m.EntryCodecs...)
+	require.ErrorIs(t, Decompress(&tooLarge, 999), ErrTooLarge)
+	require.NoError(t, Decompress(&tooLarge, 1000))
+
+	corrupt := m
+	corrupt.Entries = []pb.Entry{m.Entries[0]}
+	corrupt.Entries[0].Data = corrupt.Entries[0].Data[:2]
+	require.Error(t, Decompress(&corrupt, 0))
+
+	unknown := pb.Message{Entries: []pb.Entry{{Data: data}}, EntryCodecs: []pb.EntryCodec{7}}
+	require.Error(t, Decompress(&unknown, 0))
+
+	mismatch := pb.Message{Entries: []pb.Entry{{Data: data}}, EntryCodecs: []pb.EntryCodec{pb.CodecNone, pb.CodecFlate}}
+	require.Error(t, Decompress(&mismatch, 0))
+}
+
+func TestInvalidLevel(t *testing.T) {
+	_, err := New(Config{Level: 10})
+	require.Error(t, err)
+}
@@ -17,6 +17,7 @@ package confchange
 import (
 	"errors"
 	"fmt"
+	"maps"
 	"strings"
 
 	"go.etcd.io/raft/v3/quorum"
@@ -49,6 +50,11 @@ type Changer struct {
 //
 // [1]: https://github.com/ongardie/dissertation/blob/master/online-trim.pdf
 func (c Changer) EnterJoint(autoLeave bool, ccs ...pb.ConfChangeSingle) (tracker.Config, tracker.ProgressMap, error) {
+	return c.enterJoint(true /* validate */, autoLeave, ccs...)
+}
+
+// enterJoint implements EnterJoint. See simple for the validate flag.
+func (c Changer) enterJoint(validate bool, autoLeave bool, ccs ...pb.ConfChangeSingle) (tracker.Config, tracker.ProgressMap, error) {
 	cfg, trk, err := c.checkAndCopy()
 	if err != nil {
 		return c.err(err)
@@ -69,12 +75,21 @@ func (c Changer) EnterJoint(autoLeave bool, ccs ...pb.ConfChangeSingle) (tracker
 	for id := range incoming(cfg.Voters) {
 		outgoing(cfg.Voters)[id] = struct{}{}
 	}
+	cfg.Weights[1] = maps.Clone(cfg.Weights[0])
 
 	if err := c.apply(&cfg, trk, ccs...); err != nil {
 		return c.err(err)
 	}
+	if validate {
+		if err := c.checkQuorum(cfg); err != nil {
+			return c.err(err)
+		}
+		if err := c.checkZones(cfg); err != nil {
+			return c.err(err)
+		}
+	}
 	cfg.AutoLeave = autoLeave
-	return checkAndReturn(cfg, trk)
+	return checkAndReturn(cfg, trk, c.Tracker.Quorum)
 }
 
 // LeaveJoint transitions out of a joint configuration. It is an error to call
@@ -112,12 +127,15 @@ func (c Changer) LeaveJoint() (tracker.Config, tracker.ProgressMap, error) {
 
 		if !isVoter && !isLearner {
 			delete(trk, id)
+			nilAwareDelete(&cfg.Witnesses, id)
+			forgetNode(&cfg, id)
 		}
 	}
 	*outgoingPtr(&cfg.Voters) = nil
+	cfg.Weights[1] = nil
 	cfg.AutoLeave = false
 
-	return checkAndReturn(cfg, trk)
+	return checkAndReturn(cfg, trk, c.Tracker.Quorum)
 }
 
 // Simple carries out a series of configuration changes that (in aggregate)
@@ -125,7 +143,28 @@ func (c Changer) LeaveJoint() (tracker.Config, tracker.ProgressMap, error) {
 // will return an error if that is not the case, if the resulting quorum is
 // zero, or if the configuration is in a joint state (i.e. if there is an
 // outgoing configuration).
+//
+// The voters of a weighted config (and the weights themselves) can't be
+// changed this way, since a single voter carrying enough weight can make the
+// old and new quorums disjoint. Such changes require joint consensus.
+//
+// With flexible quorums (see tracker.ProgressTracker.Quorum), Simple and
+// EnterJoint refuse changes after which the thresholds no longer suit the
+// voters of the incoming config. Configs that never suited them, such as
+// those still being built up one voter at a time, use majorities instead.
+//
+// Once nodes are placed in zones, Simple and EnterJoint refuse changes after
+// which the voters of the incoming config don't tolerate the loss of any one
+// zone (see quorum.MajorityConfig.CheckZoneLoss). With LenientZoneChecks,
+// they only refuse changes after which voters that tolerated it no longer do.
 func (c Changer) Simple(ccs ...pb.ConfChangeSingle) (tracker.Config, tracker.ProgressMap, error) {
+	return c.simple(true /* validate */, ccs...)
+}
+
+// simple implements Simple. The checks for weighted configs and zone layouts
+// can be disabled when the resulting config is known to be valid, as is the
+// case in Restore.
+func (c Changer) simple(validate bool, ccs ...pb.ConfChangeSingle) (tracker.Config, tracker.ProgressMap, error) {
 	cfg, trk, err := c.checkAndCopy()
 	if err != nil {
 		return c.err(err)
@@ -137,11 +176,27 @@ func (c Changer) Simple(ccs ...pb.ConfChangeSingle) (tracker.Config, tracker.Pro
 	if err := c.apply(&cfg, trk, ccs...); err != nil {
 		return c.err(err)
 	}
-	if n := symdiff(incoming(c.Tracker.Voters), incoming(cfg.Voters)); n > 1 {
+	n := symdiff(incoming(c.Tracker.Voters), incoming(cfg.Voters))
+	if n > 1 {
 		return tracker.Config{}, nil, errors.New("more than one voter changed without entering joint config")
 	}
+	// The first voter of an empty config can be weighted, as there are no
+	// previous quorums to intersect with.
+	weighted := len(c.Tracker.Weights[0]) > 0 || len(cfg.Weights[0]) > 0
+	changed := n > 0 || !maps.Equal(c.Tracker.Weights[0], cfg.Weights[0])
+	if validate && weighted && changed && len(incoming(c.Tracker.Voters)) > 0 {
+		return tracker.Config{}, nil, errors.New("weighted voters can't be changed without entering joint config")
+	}
+	if validate {
+		if err := c.checkQuorum(cfg); err != nil {
+			return c.err(err)
+		}
+		if err := c.checkZones(cfg); err != nil {
+			return c.err(err)
+		}
+	}
 
-	return checkAndReturn(cfg, trk)
+	return checkAndReturn(cfg, trk, c.Tracker.Quorum)
 }
 
 // apply a change to the configuration. By convention, changes to voters are
@@ -155,17 +210,35 @@ func (c Changer) apply(cfg *tracker.Config, trk tracker.ProgressMap, ccs ...pb.C
 			// here to ignore these.
 			continue
 		}
+		var err error
 		switch cc.Type {
 		case pb.ConfChangeAddNode:
-			c.makeVoter(cfg, trk, cc.NodeID)
+			err = c.makeVoter(cfg, trk, cc.NodeID, cc.Weight)
 		case pb.ConfChangeAddLearnerNode:
-			c.makeLearner(cfg, trk, cc.NodeID)
+			err = c.makeLearner(cfg, trk, cc.NodeID)
+		case pb.ConfChangeAddWitness:
+			err = c.makeWitness(cfg, trk, cc.NodeID, cc.Weight)
 		case pb.ConfChangeRemoveNode:
 			c.remove(cfg, trk, cc.NodeID)
 		case pb.ConfChangeUpdateNode:
 		default:
 			return fmt.Errorf("unexpected conf type %d", cc.Type)
 		}
+		if err != nil {
+			return err
+		}
+		if cc.Type == pb.ConfChangeAddNode || cc.Type == pb.ConfChangeAddLearnerNode {
+			setPriority(cfg, cc.NodeID, cc.Priority)
+		}
+		if cc.Zone != "" {
+			if _, ok := trk[cc.NodeID]; !ok {
+				return fmt.Errorf("can't set the zone of removed node %d", cc.NodeID)
+			}
+			if cfg.Zones == nil {
+				cfg.Zones = quorum.Zones{}
+			}
+			cfg.Zones[cc.NodeID] = cc.Zone
+		}
 	}
 	if len(incoming(cfg.Voters)) == 0 {
 		return errors.New("removed all voters")
@@ -173,19 +246,120 @@ func (c Changer) apply(cfg *tracker.Config, trk tracker.ProgressMap, ccs ...pb.C
 	return nil
 }
 
-// makeVoter adds or promotes the given ID to be a voter in the incoming
-// majority config.
-func (c Changer) makeVoter(cfg *tracker.Config, trk tracker.ProgressMap, id uint64) {
+// makeVoter adds or promotes the given ID to be a voter with the given weight
+// in the incoming majority config. A weight of zero stands for one.
+func (c Changer) makeVoter(cfg *tracker.Config, trk tracker.ProgressMap, id uint64, weight uint64) error {
 	pr := trk[id]
 	if pr == nil {
 		c.initProgress(cfg, trk, id, false /* isLearner */)
-		return
+		setWeight(cfg, id, weight)
+		return nil
+	}
+	if pr.IsWitness {
+		return fmt.Errorf("%d is a witness and can't be turned into a voter storing the log", id)
 	}
 
 	pr.IsLearner = false
 	nilAwareDelete(&cfg.Learners, id)
 	nilAwareDelete(&cfg.LearnersNext, id)
 	incoming(cfg.Voters)[id] = struct{}{}
+	setWeight(cfg, id, weight)
+	return nil
+}
+
+// setWeight sets the weight of the given voter in the incoming majority
+// config. Weights of zero and one are not stored.
+func setWeight(cfg *tracker.Config, id uint64, weight uint64) {
+	if weight <= 1 {
+		if delete(cfg.Weights[0], id); len(cfg.Weights[0]) == 0 {
+			cfg.Weights[0] = nil
+		}
+		return
+	}
+	if cfg.Weights[0] == nil {
+		cfg.Weights[0] = quorum.Weights{}
+	}
+	cfg.Weights[0][id] = weight
+}
+
+// forgetNode forgets the zone and priority of the given node, which is no
+// longer part of the config, nil'ing the maps if they become empty.
+func forgetNode(cfg *tracker.Config, id uint64) {
+	if delete(cfg.Zones, id); len(cfg.Zones) == 0 {
+		cfg.Zones = nil
+	}
+	setPriority(cfg, id, 0)
+}
+
+// setPriority sets the election priority of the given node. Priorities of
+// zero are not stored.
+func setPriority(cfg *tracker.Config, id uint64, priority uint64) {
+	if priority == 0 {
+		if delete(cfg.Priorities, id); len(cfg.Priorities) == 0 {
+			cfg.Priorities = nil
+		}
+		return
+	}
+	if cfg.Priorities == nil {
+		cfg.Priorities = map[uint64]uint64{}
+	}
+	cfg.Priorities[id] = priority
+}
+
+// checkQuorum returns an error if the flexible quorum thresholds suit the
+// incoming majority config of the tracker, but not that of cfg.
+func (c Changer) checkQuorum(cfg tracker.Config) error {
+	q := c.Tracker.Quorum
+	if q.Validate(incoming(c.Tracker.Voters).TotalWeight(c.Tracker.Weights[0])) != nil {
+		return nil
+	}
+	if err := q.Validate(incoming(cfg.Voters).TotalWeight(cfg.Weights[0])); err != nil {
+		return fmt.Errorf("config wouldn't suit the quorum thresholds: %w", err)
+	}
+	return nil
+}
+
+// checkZones returns an error if nodes of cfg are placed in zones, but its
+// incoming majority config doesn't tolerate the loss of a zone. With
+// LenientZoneChecks, configs following one that didn't tolerate it either
+// (such as those still being built up one voter at a time) are not held to
+// it.
+func (c Changer) checkZones(cfg tracker.Config) error {
+	if len(cfg.Zones) == 0 {
+		return nil
+	}
+	q, k := c.Tracker.Quorum, c.Tracker.MinCommitZones
+	if c.Tracker.LenientZoneChecks &&
+		incoming(c.Tracker.Voters).CheckZoneLoss(q, c.Tracker.Weights[0], c.Tracker.Zones, c.Tracker.Witnesses, k) != nil {
+		return nil
+	}
+	if err := incoming(cfg.Voters).CheckZoneLoss(q, cfg.Weights[0], cfg.Zones, cfg.Witnesses, k); err != nil {
+		return fmt.Errorf("config wouldn't tolerate the loss of a zone: %w", err)
+	}
+	return nil
+}
+
+// makeWitness adds the given ID as a witness voter in the incoming majority
+// config. Peers that store the log can't be turned into witnesses (and vice
+// versa) since their logs are not interchangeable; they have to be removed and
+// added back under the new role instead.
+func (c Changer) makeWitness(cfg *tracker.Config, trk tracker.ProgressMap, id uint64, weight uint64) error {
+	pr := trk[id]
+	if pr == nil {
+		c.initProgress(cfg, trk, id, false /* isLearner */)
+		trk[id].IsWitness = true
+		nilAwareAdd(&cfg.Witnesses, id)
+		setWeight(cfg, id, weight)
+		return nil
+	}
+	if !pr.IsWitness {
+		return fmt.Errorf("%d stores the log and can't be turned into a witness", id)
+	}
+	// The witness may only be a voter in the outgoing config, in which case
+	// this adds it back to the incoming one.
+	incoming(cfg.Voters)[id] = struct{}{}
+	setWeight(cfg, id, weight)
+	return nil
 }
 
 // makeLearner makes the given ID a learner or stages it to be a learner once
@@ -201,14 +375,17 @@ func (c Changer) makeVoter(cfg *tracker.Config, trk tracker.ProgressMap, id uint
 // simultaneously. Instead, we add the learner to LearnersNext, so that it will
 // be added to Learners the moment the outgoing config is removed by
 // LeaveJoint().
-func (c Changer) makeLearner(cfg *tracker.Config, trk tracker.ProgressMap, id uint64) {
+func (c Changer) makeLearner(cfg *tracker.Config, trk tracker.ProgressMap, id uint64) error {
 	pr := trk[id]
 	if pr == nil {
 		c.initProgress(cfg, trk, id, true /* isLearner */)
-		return
+		return nil
 	}
 	if pr.IsLearner {
-		return
+		return nil
+	}
+	if pr.IsWitness {
+		return fmt.Errorf("%d is a witness and can't be turned into a learner", id)
 	}
 	// Remove any existing voter in the incoming config...
 	c.remove(cfg, trk, id)
@@ -225,6 +402,7 @@ func (c Changer) makeLearner(cfg *tracker.Config, trk tracker.ProgressMap, id ui
 		pr.IsLearner = true
 		nilAwareAdd(&cfg.Learners, id)
 	}
+	return nil
 }
 
 // remove this peer as a voter or learner from the incoming config.
@@ -234,12 +412,15 @@ func (c Changer) remove(cfg *tracker.Config, trk tracker.ProgressMap, id uint64)
 	}
 
 	delete(incoming(cfg.Voters), id)
+	setWeight(cfg, id, 0)
 	nilAwareDelete(&cfg.Learners, id)
 	nilAwareDelete(&cfg.LearnersNext, id)
 
 	// If the peer is still a voter in the outgoing config, keep the Progress.
 	if _, onRight := outgoing(cfg.Voters)[id]; !onRight {
 		delete(trk, id)
+		nilAwareDelete(&cfg.Witnesses, id)
+		forgetNode(cfg, id)
 	}
 }
 
@@ -271,9 +452,9 @@ func (c Changer) initProgress(cfg *tracker.Config, trk tracker.ProgressMap, id u
 }
 
 // checkInvariants makes sure that the config and progress are compatible with
-// each other. This is used to check both what the Changer is initialized with,
-// as well as what it returns.
-func checkInvariants(cfg tracker.Config, trk tracker.ProgressMap) error {
+// each other and with the quorum thresholds. This is used to check both what
+// the Changer is initialized with, as well as what it returns.
+func checkInvariants(cfg tracker.Config, trk tracker.ProgressMap, q quorum.FlexibleQuorum) error {
 	// NB: intentionally allow the empty config. In production we'll never see a
 	// non-empty config (we prevent it from being created) but we will need to
 	// be able to *create* an initial config, for example during bootstrap (or
@@ -284,6 +465,7 @@ func checkInvariants(cfg tracker.Config, trk tracker.ProgressMap) error {
 		cfg.Voters.IDs(),
 		cfg.Learners,
 		cfg.LearnersNext,
+		cfg.Witnesses,
 	} {
 		for id := range ids {
 			if _, ok := trk[id]; !ok {
@@ -315,11 +497,77 @@ func checkInvariants(cfg tracker.Config, trk tracker.ProgressMap) error {
 		}
 	}
 
+	// Witnesses are voters, and the progress of a peer is marked as witness
+	// if and only if the peer is a witness.
+	voters := cfg.Voters.IDs()
+	for id := range cfg.Witnesses {
+		if _, ok := voters[id]; !ok {
+			return fmt.Errorf("%d is in Witnesses, but not in Voters", id)
+		}
+		if !trk[id].IsWitness {
+			return fmt.Errorf("%d is in Witnesses, but is not marked as witness", id)
+		}
+	}
+	for id, pr := range trk {
+		if _, ok := cfg.Witnesses[id]; pr.IsWitness && !ok {
+			return fmt.Errorf("%d is marked as witness, but is not in Witnesses", id)
+		}
+	}
+	// The weights of either half only cover the voters of that half.
+	for i, weights := range cfg.Weights {
+		for id, weight := range weights {
+			if _, ok := cfg.Voters[i][id]; !ok {
+				return fmt.Errorf("%d has a weight in Weights[%d], but is not in Voters[%d]", id, i, i)
+			}
+			if weight <= 1 {
+				return fmt.Errorf("%d has weight %d in Weights[%d], expected more than one", id, weight, i)
+			}
+		}
+	}
+
+	// Zones and priorities are only tracked for nodes in the config.
+	for id, zone := range cfg.Zones {
+		if _, ok := trk[id]; !ok {
+			return fmt.Errorf("%d is in Zones, but has no progress", id)
+		}
+		if zone == "" {
+			return fmt.Errorf("%d has an empty zone", id)
+		}
+	}
+	for id, priority := range cfg.Priorities {
+		if _, ok := trk[id]; !ok {
+			return fmt.Errorf("%d is in Priorities, but has no progress", id)
+		}
+		if priority == 0 {
+			return fmt.Errorf("%d has priority zero in Priorities", id)
+		}
+	}
+
+	// The witnesses of either half of the joint config must not form a
+	// replication quorum on their own, or entries could be committed without
+	// being stored by any peer other than the leader.
+	for i, voters := range cfg.Voters {
+		var n int
+		var weight uint64
+		for id := range voters {
+			if _, ok := cfg.Witnesses[id]; ok {
+				n++
+				weight += cfg.Weights[i].Weight(id)
+			}
+		}
+		if n > 0 && weight >= q.ReplicationThreshold(voters.TotalWeight(cfg.Weights[i])) {
+			return fmt.Errorf("the %d witnesses in Voters[%d]=%s form a quorum", n, i, voters)
+		}
+	}
+
 	if !joint(cfg) {
 		// We enforce that empty maps are nil instead of zero.
 		if outgoing(cfg.Voters) != nil {
 			return fmt.Errorf("cfg.Voters[1] must be nil when not joint")
 		}
+		if cfg.Weights[1] != nil {
+			return fmt.Errorf("cfg.Weights[1] must be nil when not joint")
+		}
 		if cfg.LearnersNext != nil {
 			return fmt.Errorf("cfg.LearnersNext must be nil when not joint")
 		}
@@ -343,13 +591,13 @@ func (c Changer) checkAndCopy() (tracker.Config, tracker.ProgressMap, error) {
 		ppr := *pr
 		trk[id] = &ppr
 	}
-	return checkAndReturn(cfg, trk)
+	return checkAndReturn(cfg, trk, c.Tracker.Quorum)
 }
 
 // checkAndReturn calls checkInvariants on the input and returns either the
 // resulting error or the input.
-func checkAndReturn(cfg tracker.Config, trk tracker.ProgressMap) (tracker.Config, tracker.ProgressMap, error) {
-	if err := checkInvariants(cfg, trk); err != nil {
+func checkAndReturn(cfg tracker.Config, trk tracker.ProgressMap, q quorum.FlexibleQuorum) (tracker.Config, tracker.ProgressMap, error) {
+	if err := checkInvariants(cfg, trk, q); err != nil {
 		return tracker.Config{}, tracker.ProgressMap{}, err
 	}
 	return cfg, trk, nil
@@ -17,8 +17,6 @@ package confchange
 import (
 	"errors"
 	"fmt"
-	"strconv"
-	"strings"
 	"testing"
 
 	"github.com/cockroachdb/datadriven"
@@ -43,51 +41,43 @@ func TestConfChangeDataDriven(t *testing.T) {
 		// syntax:
 		// - vn: make n a voter,
 		// - ln: make n a learner,
+		// - wn: make n a witness,
 		// - rn: remove n, and
 		// - un: update n.
+		// Voters and witnesses take an optional weight, e.g. v1:3, and added
+		// nodes an optional zone, e.g. v1:3@a or l2@b. Any command can set the
+		// tracker's MinCommitZones via the min-commit-zones argument, its
+		// LenientZoneChecks via lenient-zone-checks, and its flexible quorum
+		// via the election-quorum and replication-quorum arguments.
 		datadriven.RunTest(t, path, func(t *testing.T, d *datadriven.TestData) string {
 			defer func() {
 				c.LastIndex++
 			}()
-			var ccs []pb.ConfChangeSingle
-			toks := strings.Split(strings.TrimSpace(d.Input), " ")
-			if toks[0] == "" {
-				toks = nil
+			ccs, err := pb.ConfChangesFromString(d.Input)
+			if err != nil {
+				return err.Error()
 			}
-			for _, tok := range toks {
-				if len(tok) < 2 {
-					return fmt.Sprintf("unknown token %s", tok)
-				}
-				var cc pb.ConfChangeSingle
-				switch tok[0] {
-				case 'v':
-					cc.Type = pb.ConfChangeAddNode
-				case 'l':
-					cc.Type = pb.ConfChangeAddLearnerNode
-				case 'r':
-					cc.Type = pb.ConfChangeRemoveNode
-				case 'u':
-					cc.Type = pb.ConfChangeUpdateNode
-				default:
-					return fmt.Sprintf("unknown input: %s", tok)
-				}
-				id, err := strconv.ParseUint(tok[1:], 10, 64)
-				if err != nil {
-					return err.Error()
-				}
-				cc.NodeID = id
-				ccs = append(ccs, cc)
+			if d.HasArg("min-commit-zones") {
+				d.ScanArgs(t, "min-commit-zones", &c.Tracker.MinCommitZones)
+			}
+			if d.HasArg("lenient-zone-checks") {
+				d.ScanArgs(t, "lenient-zone-checks", &c.Tracker.LenientZoneChecks)
+			}
+			if d.HasArg("election-quorum") {
+				d.ScanArgs(t, "election-quorum", &c.Tracker.Quorum.Election)
+			}
+			if d.HasArg("replication-quorum") {
+				d.ScanArgs(t, "replication-quorum", &c.Tracker.Quorum.Replication)
 			}
 
 			var cfg tracker.Config
 			var trk tracker.ProgressMap
-			var err error
 			switch d.Cmd {
 			case "simple":
 				cfg, trk, err = c.Simple(ccs...)
 			case "enter-joint":
 				var autoLeave bool
-				if len(d.CmdArgs) > 0 {
+				if d.HasArg("autoleave") {
 					d.ScanArgs(t, "autoleave", &autoLeave)
 				}
 				cfg, trk, err = c.EnterJoint(autoLeave, ccs...)
@@ -153,8 +153,10 @@ func (confChanges) Generate(rand *rand.Rand, _ int) reflect.Value {
 		// voterless configs altogether in this test.
 		return 1 + uint64(num())
 	}
+	// Witnesses are left out: turning a voter into a witness (or back) is
+	// rejected, so random sequences would frequently fail to apply.
 	typ := func() pb.ConfChangeType {
-		return pb.ConfChangeType(rand.Intn(len(pb.ConfChangeType_name)))
+		return pb.ConfChangeType(rand.Intn(int(pb.ConfChangeAddWitness)))
 	}
 	return reflect.ValueOf(genCC(num, id, typ))
 }
@@ -52,16 +52,53 @@ func toConfChangeSingle(cs pb.ConfState) (out []pb.ConfChangeSingle, in []pb.Con
 	//
 	// as desired.
 
-	for _, id := range cs.VotersOutgoing {
-		// If there are outgoing voters, first add them one by one so that the
-		// (non-joint) config has them all.
-		out = append(out, pb.ConfChangeSingle{
-			Type:   pb.ConfChangeAddNode,
-			NodeID: id,
-		})
-
+	// Witnesses are added after the voters storing the log, so that the
+	// witnesses never form a quorum in the intermediate configs.
+	witnesses := make(map[uint64]bool, len(cs.Witnesses))
+	for _, id := range cs.Witnesses {
+		witnesses[id] = true
+	}
+	weightsOf := func(sl []pb.VoterWeight) map[uint64]uint64 {
+		m := make(map[uint64]uint64, len(sl))
+		for _, w := range sl {
+			m[w.NodeID] = w.Weight
+		}
+		return m
+	}
+	zones := make(map[uint64]string, len(cs.Zones))
+	for _, z := range cs.Zones {
+		zones[z.NodeID] = z.Zone
+	}
+	priorities := make(map[uint64]uint64, len(cs.Priorities))
+	for _, p := range cs.Priorities {
+		priorities[p.NodeID] = p.Priority
+	}
+	addVoters := func(ccs []pb.ConfChangeSingle, ids []uint64, weights map[uint64]uint64) []pb.ConfChangeSingle {
+		for _, witness := range []bool{false, true} {
+			for _, id := range ids {
+				if witnesses[id] != witness {
+					continue
+				}
+				typ := pb.ConfChangeAddNode
+				if witness {
+					typ = pb.ConfChangeAddWitness
+				}
+				ccs = append(ccs, pb.ConfChangeSingle{
+					Type:     typ,
+					NodeID:   id,
+					Weight:   weights[id],
+					Zone:     zones[id],
+					Priority: priorities[id],
+				})
+			}
+		}
+		return ccs
 	}
 
+	// If there are outgoing voters, first add them one by one so that the
+	// (non-joint) config has them all.
+	out = addVoters(out, cs.VotersOutgoing, weightsOf(cs.WeightsOutgoing))
+
 	// We're done constructing the outgoing slice, now on to the incoming one
 	// (which will apply on top of the config created by the outgoing slice).
 
@@ -73,24 +110,23 @@ func toConfChangeSingle(cs pb.ConfState) (out []pb.ConfChangeSingle, in []pb.Con
 		})
 	}
 	// Then we'll add the incoming voters and learners.
-	for _, id := range cs.Voters {
-		in = append(in, pb.ConfChangeSingle{
-			Type:   pb.ConfChangeAddNode,
-			NodeID: id,
-		})
-	}
+	in = addVoters(in, cs.Voters, weightsOf(cs.Weights))
 	for _, id := range cs.Learners {
 		in = append(in, pb.ConfChangeSingle{
-			Type:   pb.ConfChangeAddLearnerNode,
-			NodeID: id,
+			Type:     pb.ConfChangeAddLearnerNode,
+			NodeID:   id,
+			Zone:     zones[id],
+			Priority: priorities[id],
 		})
 	}
 	// Same for LearnersNext; these are nodes we want to be learners but which
 	// are currently voters in the outgoing config.
 	for _, id := range cs.LearnersNext {
 		in = append(in, pb.ConfChangeSingle{
-			Type:   pb.ConfChangeAddLearnerNode,
-			NodeID: id,
+			Type:     pb.ConfChangeAddLearnerNode,
+			NodeID:   id,
+			Zone:     zones[id],
+			Priority: priorities[id],
 		})
 	}
 	return out, in
@@ -110,7 +146,9 @@ func chain(chg Changer, ops ...func(Changer) (tracker.Config, tracker.ProgressMa
 
 // Restore takes a Changer (which must represent an empty configuration), and
 // runs a sequence of changes enacting the configuration described in the
-// ConfState.
+// ConfState. Weighted voters are added one at a time as well, which Simple
+// would refuse, and the intermediate configs may not tolerate the loss of a
+// zone; this is fine since none of them is ever used to make decisions.
 //
 // TODO(tbg) it's silly that this takes a Changer. Unravel this by making sure
 // the Changer only needs a ProgressMap (not a whole Tracker) at which point
@@ -126,7 +164,7 @@ func Restore(chg Changer, cs pb.ConfState) (tracker.Config, tracker.ProgressMap,
 		for _, cc := range incoming {
 			cc := cc // loop-local copy
 			ops = append(ops, func(chg Changer) (tracker.Config, tracker.ProgressMap, error) {
-				return chg.Simple(cc)
+				return chg.simple(false /* validate */, cc)
 			})
 		}
 	} else {
@@ -138,7 +176,7 @@ func Restore(chg Changer, cs pb.ConfState) (tracker.Config, tracker.ProgressMap,
 		for _, cc := range outgoing {
 			cc := cc // loop-local copy
 			ops = append(ops, func(chg Changer) (tracker.Config, tracker.ProgressMap, error) {
-				return chg.Simple(cc)
+				return chg.simple(false /* validate */, cc)
 			})
 		}
 		// Now enter the joint state, which rotates the above additions into the
@@ -147,7 +185,7 @@ func Restore(chg Changer, cs pb.ConfState) (tracker.Config, tracker.ProgressMap,
 		// would be removing 2,3,4 and then adding in 1,2,3 while transitioning
 		// into a joint state.
 		ops = append(ops, func(chg Changer) (tracker.Config, tracker.ProgressMap, error) {
-			return chg.EnterJoint(cs.AutoLeave, incoming...)
+			return chg.enterJoint(false /* validate */, cs.AutoLeave, incoming...)
 		})
 	}
 
@@ -15,6 +15,7 @@
 package confchange
 
 import (
+	"cmp"
 	"math/rand"
 	"reflect"
 	"slices"
@@ -23,6 +24,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"go.etcd.io/raft/v3/quorum"
 	pb "go.etcd.io/raft/v3/raftpb"
 	"go.etcd.io/raft/v3/tracker"
 )
@@ -73,12 +75,78 @@ func (rndConfChange) Generate(rand *rand.Rand, _ int) reflect.Value {
 	}
 	// Only outgoing voters that are not also incoming voters can be in
 	// LearnersNext (they represent demotions).
+	var nLearnersNext int
 	if nRemovedVoters > 0 {
-		if nLearnersNext := rand.Intn(nRemovedVoters + 1); nLearnersNext > 0 {
+		if nLearnersNext = rand.Intn(nRemovedVoters + 1); nLearnersNext > 0 {
 			cs.LearnersNext = ids[:nLearnersNext]
 		}
 	}
 
+	// Give some of the voters in either half a weight.
+	weigh := func(voters []uint64) []pb.VoterWeight {
+		var weights []pb.VoterWeight
+		for _, id := range voters {
+			if rand.Intn(3) == 0 {
+				weights = append(weights, pb.VoterWeight{NodeID: id, Weight: 2 + uint64(rand.Intn(3))})
+			}
+		}
+		return weights
+	}
+	cs.Weights = weigh(cs.Voters)
+	cs.WeightsOutgoing = weigh(cs.VotersOutgoing)
+
+	// Turn some of the voters into witnesses, as long as the witnesses don't
+	// form a quorum in either half. Voters that are about to become learners
+	// can't be witnesses.
+	candidates := append(slices.Clone(cs.Voters), ids[nLearnersNext:nRemovedVoters]...)
+	rand.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+	for _, id := range candidates {
+		if rand.Intn(2) == 0 {
+			continue
+		}
+		witnesses := append(slices.Clone(cs.Witnesses), id)
+		ok := true
+		for i, voters := range [][]uint64{cs.Voters, cs.VotersOutgoing} {
+			weights := quorum.Weights{}
+			for _, w := range [][]pb.VoterWeight{cs.Weights, cs.WeightsOutgoing}[i] {
+				weights[w.NodeID] = w.Weight
+			}
+			var witnessWeight, total uint64
+			for _, v := range voters {
+				if slices.Contains(witnesses, v) {
+					witnessWeight += weights.Weight(v)
+				}
+				total += weights.Weight(v)
+			}
+			ok = ok && 2*witnessWeight <= total
+		}
+		if ok {
+			cs.Witnesses = witnesses
+		}
+	}
+
+	// Place some of the nodes in zones.
+	for _, id := range slices.Concat(cs.Voters, cs.Learners, cs.VotersOutgoing) {
+		if slices.ContainsFunc(cs.Zones, func(z pb.NodeZone) bool { return z.NodeID == id }) {
+			continue
+		}
+		if rand.Intn(2) == 0 {
+			cs.Zones = append(cs.Zones, pb.NodeZone{NodeID: id, Zone: string(rune('a' + rand.Intn(3)))})
+		}
+	}
+
+	// Give some of the nodes that store the log a priority.
+	for _, id := range slices.Concat(cs.Voters, cs.Learners, cs.VotersOutgoing) {
+		if slices.Contains(cs.Witnesses, id) || slices.ContainsFunc(cs.Priorities, func(p pb.NodePriority) bool { return p.NodeID == id }) {
+			continue
+		}
+		if rand.Intn(3) == 0 {
+			cs.Priorities = append(cs.Priorities, pb.NodePriority{NodeID: id, Priority: 1 + uint64(rand.Intn(3))})
+		}
+	}
+
 	cs.AutoLeave = len(cs.VotersOutgoing) > 0 && rand.Intn(2) == 1
 	return reflect.ValueOf(rndConfChange(cs))
 }
@@ -103,9 +171,21 @@ func TestRestore(t *testing.T) {
 			cs.Learners,
 			cs.VotersOutgoing,
 			cs.LearnersNext,
+			cs.Witnesses,
 		} {
 			slices.Sort(sl)
 		}
+		for _, sl := range [][]pb.VoterWeight{cs.Weights, cs.WeightsOutgoing} {
+			slices.SortFunc(sl, func(a, b pb.VoterWeight) int {
+				return cmp.Compare(a.NodeID, b.NodeID)
+			})
+		}
+		slices.SortFunc(cs.Zones, func(a, b pb.NodeZone) int {
+			return cmp.Compare(a.NodeID, b.NodeID)
+		})
+		slices.SortFunc(cs.Priorities, func(a, b pb.NodePriority) int {
+			return cmp.Compare(a.NodeID, b.NodeID)
+		})
 
 		cs2 := chg.Tracker.ConfState()
 		// NB: cs.Equivalent does the same "sorting" dance internally, but let's
@@ -126,6 +206,16 @@ func TestRestore(t *testing.T) {
 		{Voters: ids(1, 2, 3)},
 		{Voters: ids(1, 2, 3), Learners: ids(4, 5, 6)},
 		{Voters: ids(1, 2, 3), Learners: ids(5), VotersOutgoing: ids(1, 2, 4, 6), LearnersNext: ids(4)},
+		{Voters: ids(1, 2, 3), Witnesses: ids(1)},
+		{Voters: ids(3, 2, 1, 5, 4), Witnesses: ids(2, 3)},
+		{Voters: ids(1, 2, 3), VotersOutgoing: ids(1, 2, 4), Witnesses: ids(3, 4)},
+		{Voters: ids(1, 2, 3), Weights: []pb.VoterWeight{{NodeID: 1, Weight: 3}, {NodeID: 2, Weight: 2}}},
+		{Voters: ids(1, 2, 3), Weights: []pb.VoterWeight{{NodeID: 1, Weight: 3}}, Witnesses: ids(2, 3)},
+		{Voters: ids(1, 2, 3), VotersOutgoing: ids(1, 2, 3), WeightsOutgoing: []pb.VoterWeight{{NodeID: 3, Weight: 5}}},
+		{Voters: ids(1, 2, 3), Learners: ids(4), Zones: []pb.NodeZone{{NodeID: 1, Zone: "a"}, {NodeID: 2, Zone: "b"}, {NodeID: 4, Zone: "a"}}},
+		{Voters: ids(1, 2), VotersOutgoing: ids(1, 3), LearnersNext: ids(3), Zones: []pb.NodeZone{{NodeID: 2, Zone: "a"}, {NodeID: 3, Zone: "b"}}},
+		{Voters: ids(1, 2, 3), Learners: ids(4), Priorities: []pb.NodePriority{{NodeID: 1, Priority: 2}, {NodeID: 4, Priority: 1}}},
+		{Voters: ids(1, 2), VotersOutgoing: ids(1, 3), LearnersNext: ids(3), Priorities: []pb.NodePriority{{NodeID: 3, Priority: 5}}},
 	} {
 		if !f(cs) {
 			t.FailNow() // f() already logged a nice t.Error()
@@ -136,3 +226,29 @@ func TestRestore(t *testing.T) {
 		return f(pb.ConfState(cs))
 	}, &cfg))
 }
+
+// TestRestoreWitnessesFlexibleQuorum checks that Restore measures the
+// witnesses against the replication threshold of the tracker's quorum.
+func TestRestoreWitnessesFlexibleQuorum(t *testing.T) {
+	cs := pb.ConfState{Voters: []uint64{1, 2, 3, 4, 5}, Witnesses: []uint64{4, 5}}
+	for _, tt := range []struct {
+		q  quorum.FlexibleQuorum
+		ok bool
+	}{
+		{quorum.FlexibleQuorum{}, true},
+		{quorum.FlexibleQuorum{Election: 3, Replication: 4}, true},
+		// The witnesses form a replication quorum.
+		{quorum.FlexibleQuorum{Election: 4, Replication: 2}, false},
+	} {
+		t.Run(tt.q.String(), func(t *testing.T) {
+			tr := tracker.MakeProgressTracker(20, 0)
+			tr.Quorum = tt.q
+			_, _, err := Restore(Changer{Tracker: tr, LastIndex: 10}, cs)
+			if tt.ok {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
@@ -83,7 +83,8 @@ entries from the same batch are being persisted). To reduce the I/O latency, an
 optimization can be applied to make leader write to disk in parallel with its
 followers (as explained at section 10.2.1 in Raft thesis). If any Message has type
 MsgSnap, call Node.ReportSnapshot() after it has been sent (these messages may be
-large).
+large). Snapshots too large for a single message can be streamed in chunks with
+the snapstream package instead.
 
 Note: Marshalling messages is not thread-safe; it is important that you
 make sure that no new entries are persisted while marshalling.
@@ -105,7 +106,11 @@ in the order they were returned by Ready.
 Second, all persisted log entries must be made available via an
 implementation of the Storage interface. The provided MemoryStorage
 type can be used for this (if you repopulate its state upon a
-restart), or you can supply your own disk-backed implementation.
+restart), or you can supply your own disk-backed implementation. The
+raftstore package provides a file-backed one, and the storagetest package a
+conformance test suite for implementations. Wrapping the storage with the
+storagewrap package records the latency and size of the reads raft makes into
+it, and can inject delays and errors into them.
 
 Third, when you receive a message from another node, pass it to Node.Step:
 
@@ -257,6 +262,20 @@ application to the local state machine (apply). Those will look something like:
 	  }
 	}
 
+# Asynchronous storage reads
+
+The leader reads the entries it sends to followers from Storage, on the
+goroutine driving the node, once they are no longer in memory. A follower
+catching up after falling behind thus makes the leader block on reading old
+entries, delaying proposals and the other followers. With
+Config.AsyncStorageReads set, the leader reads these entries through
+AsyncStorage.FetchEntries, which may start reading them in the background and
+return ErrEntriesPending. The application then reports the entries with
+RawNode.ReportEntries or Node.ReportEntries, and the leader sends them to the
+followers waiting for them. A failed read is reported the same way, and is
+retried the next time the leader tries to send the entries, for example upon
+a heartbeat response.
+
 # Implementation notes
 
 This implementation is up to date with the final Raft thesis
@@ -282,6 +301,215 @@ cannot be removed any more since the cluster cannot make progress.
 For this reason it is highly recommended to use three or more nodes in
 every cluster.
 
+# Witnesses
+
+A witness (added with ConfChangeAddWitness) is a voter that does not store
+the log. It votes in elections and acknowledges appends like any other voter,
+but the leader strips the payloads of normal entries and the data of
+snapshots sent to it, so a witness only persists the entry metadata,
+configuration changes and its HardState. Committed entries reach a witness
+with empty data, and the application must not apply them to its state
+machine.
+
+A witness can never become leader or be the target of a leadership transfer,
+and it can't be turned into a full voter or a learner (nor the other way
+around); remove it and add it back instead. To guarantee that every quorum
+contains a voter storing the log, the witnesses may not form a quorum on their
+own in either half of a configuration; with flexible quorums (see below), this
+applies to the replication quorum. Moreover, an entry is only committed once
+it is also acknowledged by two voters storing the log (or a majority of them,
+if there are fewer than three), so that it survives the loss of the leader.
+For example, with three voters storing the log and two witnesses, the leader,
+another voter storing the log and a witness commit an entry, while the leader
+and both witnesses don't.
+
+# Weighted voting
+
+Voters can carry a voting weight, set by the Weight field of the
+ConfChangeSingle adding them (ConfChangeAddNode or ConfChangeAddWitness) and
+recorded in the ConfState. Voters without a weight count once. Committing an
+entry and winning an election then require acknowledgements or votes from
+voters carrying more than half of the total weight, in both halves of a joint
+configuration.
+
+In a weighted configuration, a single voter change can leave the old and new
+quorums without a common member. Adding, removing or reweighting voters of a
+weighted configuration therefore has to use joint consensus (i.e. a
+ConfChangeV2 with more than one change, or with an explicit or implicit
+transition); the leader drops simple changes involving weighted voters.
+
+# Flexible quorums
+
+Config.ElectionQuorum and Config.ReplicationQuorum decouple the quorum needed
+to win an election from the one needed to commit an entry, in the style of
+Flexible Paxos. Both are measured in voting weight (i.e. in voters for an
+unweighted configuration). For example, a group of five voters can commit
+entries acknowledged by two voters if elections require four votes. The
+thresholds are valid for a configuration of total weight N if
+ElectionQuorum+ReplicationQuorum > N and 2*ElectionQuorum > N. A node refuses
+to start with initial voters for which they are not valid, and the leader
+refuses configuration changes after which they are no longer valid. Only a
+configuration still being built up one voter at a time uses majorities until
+it grows large enough. The replication quorum is also used by CheckQuorum and
+ReadOnlySafe.
+
+The thresholds are not replicated, so all members of a group must be
+configured identically. Note that a smaller replication quorum makes the group
+less available for elections: with the example above, losing two voters
+leaves no node able to become leader.
+
+# Zones
+
+Nodes can be placed in zones (failure domains such as availability zones or
+racks) by the Zone field of the ConfChangeSingle adding them; the zones are
+recorded in the ConfState. With Config.MinCommitZones set to k, an entry is
+only committed once the voters acknowledging it span k zones, and a candidate
+only wins an election if the voters granting their votes do. Witnesses vote
+like other voters, but their acknowledgements don't count towards the zones
+since they don't store the entries. With k of at least two, the loss of a
+single zone can't lose a committed entry.
+
+A configuration tolerates the loss of a zone if the voters outside of any one
+zone can still win elections and commit entries, and those storing the log
+span k zones. Once nodes are placed in zones, configuration changes after
+which the incoming voters don't tolerate the loss of a zone are refused. A
+group has to be bootstrapped with a tolerant configuration then, or be taken
+there with Config.LenientZoneChecks, which only refuses changes after which a
+tolerant configuration no longer is, such as when growing a group a voter at
+a time or rolling out zones to an existing group.
+
+# Priorities
+
+Voters and learners can be given an election priority by the Priority field
+of the ConfChangeSingle adding them; the priorities are recorded in the
+ConfState. Nodes without a priority have priority zero, the lowest.
+
+A voter waits an extra election timeout before campaigning for each distinct
+priority among the voters that is higher than its own, so that leadership
+tends to land on the voters with the highest priority. In addition, a leader
+transfers leadership to a voter with the highest priority (if that is higher
+than its own) as soon as it learns that the voter's log is up to date. This
+allows placing the leader near its clients, while leadership still fails
+over to other voters when the preferred ones are unavailable.
+
+# Leader leases
+
+With Config.LeaseDuration set, the leader holds a time-based lease that lets
+it serve linearizable reads from its local state without a round trip to a
+quorum. The leader numbers its rounds of heartbeats (in the Index field of
+MsgHeartbeat). A follower acknowledging a round promises not to campaign or
+vote for any candidate other than the leader for LeaseDuration, measured on
+its own clock. Once the acknowledgements of a round form a quorum (the same
+one that confirms a ReadIndex request), the leader holds the lease until
+LeaseDuration minus Config.MaxClockOffset has passed since it sent the round,
+measured on its clock. If the clocks of any two peers are at most
+MaxClockOffset apart, no other leader can be elected before the lease
+expires. A restarted peer honors the promises it may have made before
+restarting by not voting for anyone for LeaseDuration.
+
+The leader publishes the lease expiry in Status.LeaseExpiry, once it has
+committed an entry in its term. A read arriving before that time can be
+served locally after applying all entries up to the commit index.
+
+A leadership transfer needs the transferee to win an election despite the
+promises, so its votes are granted regardless of them. The leader hands off
+the lease by revoking it for the rest of its term as soon as the transfer
+starts, i.e. before the transferee can campaign.
+
+# Follower reads
+
+With Config.FollowerReads set, any peer can serve reads with bounded
+staleness, without a round trip to the leader. Like with leader leases, the
+leader numbers its rounds of heartbeats. Once a quorum acknowledged a round,
+no other leader was elected before the round was sent, so the commit index of
+the leader at that time covered every entry committed by then. The leader
+records this commit index and the time of the round as the safe read index,
+and piggybacks it on its subsequent heartbeats and appends (in the
+SafeReadIndex and SafeReadTime fields of the messages).
+
+A peer that has applied the safe read index can serve a read reflecting all
+writes that completed before the time of the round. Measured on its own clock,
+the read is stale by at most the time since then plus Config.MaxClockOffset.
+RawNode.SafeReadIndex and Node.SafeReadIndex return the safe read index if
+this staleness is within a given bound. Reads of a peer that lost contact with
+the leader grow staler, but remain consistent with a past state of the group.
+
+# Entry checksums
+
+With Config.EntryChecksums set, the leader stores a CRC32C checksum of the
+term, index, type and data of each entry it appends in the Checksum field of
+the entry. Every peer verifies the checksums of the entries it receives, and
+drops appends containing a corrupted entry, which the leader then retries. It
+verifies them again before handing entries out in Ready.CommittedEntries, and
+panics if one of them was corrupted in storage, rather than applying it.
+Entries without a checksum are not verified.
+
+# Hash checks
+
+Checksums don't catch state machines that diverge due to bugs or corruption
+outside of the log. With Config.HashCheckInterval set, the leader regularly
+proposes an empty EntryHashCheck entry as a marker. The application applying
+it hashes its state machine as of the marker and reports the hash via
+RawNode.ReportHash or Node.ReportHash. The leader proposes its own hash in a
+second EntryHashCheck entry, whose data is a HashCheck, and every replica
+compares its hash to the one of the leader once both are known, calling
+Config.OnHashMismatch on a mismatch. Applications must not apply either kind
+of EntryHashCheck entry to their state machine.
+
+# Compression
+
+The compression package compresses the data of the entries in messages on the
+wire, leaving raft and its size limits to deal with uncompressed data only.
+Compressed entries are marked with their codec in Message.EntryCodecs, so that
+peers decompressing incoming messages accept them from peers that do and
+don't compress. Raft refuses to step messages carrying compressed entries
+with ErrStepCompressed.
+
+# Structured logging
+
+When Config.Logger is a StructuredLogger, such as the SlogLogger writing to a
+log/slog Logger, raft attaches the node ID, term and state of the node to
+every line, along with Config.GroupID when set, so that the lines of the many
+groups of a process can be told apart. Some lines carry more attributes, such
+as the peer involved.
+
+# Proposal tracing
+
+Config.ProposalTracer receives an event for each stage an entry appended by
+the leader goes through, keyed by the index of the entry: queued in
+Node.Propose, appended to the unstable log, persisted, committed and applied.
+Unlike the state machine tracing of the with_tla build tag, it doesn't need a
+special build. ProposalLatencyCollector is an in-memory tracer computing the
+latency of each stage in histograms, and other tracers, e.g. emitting spans,
+can be built by applications.
+
+# Metrics
+
+Config.Metrics is notified by the node of events worth counting, which Status
+can't tell as it only reflects the current state: elections started, won and
+lost, pre-vote rejections and term changes, messages sent and received by
+type, proposals dropped by reason, snapshots sent, and pauses of the appends
+to followers by flow control. Applications implement Metrics on top of their
+metrics backend. NoopMetrics, the default, ignores all events.
+
+# Quiescence
+
+With Config.Quiesce, an idle group stops exchanging heartbeats, which saves
+the traffic of hosting many groups that see few writes. Once everything the
+leader appended is applied and acknowledged by all its peers, it sends a last
+heartbeat marked with Quiesce, and all the nodes stop ticking. Any message from
+a peer or proposal wakes a node up, as does Node.Wake (or RawNode.Wake), which
+the application calls when it suspects the leader of a quiet group to be down.
+BasicStatus.Quiesced tells whether a node is quiesced.
+
+# Heartbeat coalescing
+
+A process hosting many groups sends a heartbeat per group and per follower on
+every heartbeat tick. The coalesce package lets the transport hold back the
+heartbeats and heartbeat responses of all the groups, and send them as one
+raftpb.CoalescedHeartbeats per destination node, which the receiver splits
+back into the messages of each group.
+
 # MessageType
 
 Package raft sends and receives message in Protocol Buffer format (defined
@@ -365,6 +593,12 @@ stale log entries:
 	indicates that the snapshot succeeded and the leader sets follower's
 	progress to probe and resumes its log replication.
 
+	'MsgSnapChunk' and 'MsgSnapChunkResp' stream the data of a snapshot in
+	chunks and acknowledge them. They are exchanged by the snapstream package
+	on behalf of 'MsgSnap', and are never stepped into raft: the receiving end
+	turns the completed stream back into a 'MsgSnap', and the sending end
+	reports the outcome via ReportSnapshot.
+
 	'MsgHeartbeat' sends heartbeat from leader. When 'MsgHeartbeat' is passed
 	to candidate and message's term is higher than candidate's, the candidate
 	reverts back to follower and updates its committed index from the one in
@@ -384,6 +618,15 @@ stale log entries:
 	indicating 'MsgApp' is lost. When follower's progress state is replicate,
 	the leader sets it back to probe.
 
+	'MsgStateHash' is a message from the application to its local node,
+	reporting the hash of its state machine at a hash check marker. It is
+	created by RawNode.ReportHash and Node.ReportHash.
+
+	'MsgStorageEntries' is a message from the application to its local node,
+	carrying the entries read in the background after AsyncStorage.FetchEntries
+	returned ErrEntriesPending. It is created by RawNode.ReportEntries and
+	Node.ReportEntries.
+
 	'MsgStorageAppend' is a message from a node to its local append storage
 	thread to write entries, hard state, and/or a snapshot to stable storage.
 	The message will carry one or more responses, one of which will be a
@@ -0,0 +1,151 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package raft
+
+import pb "go.etcd.io/raft/v3/raftpb"
+
+// EntryCacheStats contains statistics about the entry cache (see
+// Config.EntryCacheSize).
+type EntryCacheStats struct {
+	// Hits and Misses count the reads of stable entries served by the cache
+	// and by Storage, respectively.
+	Hits, Misses uint64
+	// Entries and Bytes are the number and total size of the cached entries.
+	Entries int
+	Bytes   uint64
+}
+
+// entryCache holds the most recent stable entries of the log, up to a total
+// size, so that they can be read again without going through Storage. The
+// entries are consecutive, and match the log: entries are added as they
+// become stable, and dropped when the log is truncated or replaced by a
+// snapshot. A nil entryCache caches nothing.
+type entryCache struct {
+	maxSize entryEncodingSize
+	ents    []pb.Entry
+	size    entryEncodingSize
+
+	hits, misses uint64
+}
+
+func newEntryCache(maxSize uint64) *entryCache {
+	if maxSize == 0 {
+		return nil
+	}
+	return &entryCache{maxSize: entryEncodingSize(maxSize)}
+}
+
+func (c *entryCache) first() uint64 { return c.ents[0].Index }
+
+func (c *entryCache) last() uint64 { return c.ents[len(c.ents)-1].Index }
+
+// add adds the given consecutive entries, which just became stable, evicting
+// the oldest entries beyond the size limit. Entries at and after the first
+// given one are replaced, and the cache is cleared if there is a gap.
+func (c *entryCache) add(ents []pb.Entry) {
+	if c == nil || len(ents) == 0 {
+		return
+	}
+	if len(c.ents) > 0 {
+		if i := ents[0].Index; i > c.last()+1 || i < c.first() {
+			c.clear()
+		} else {
+			c.truncateFrom(i)
+		}
+	}
+	c.ents = append(c.ents, ents...)
+	c.size += entsSize(ents)
+	c.evict()
+}
+
+// evict drops the oldest entries until the cache fits the size limit.
+func (c *entryCache) evict() {
+	var n int
+	for ; n < len(c.ents) && c.size > c.maxSize; n++ {
+		c.size -= entryEncodingSize(c.ents[n].Size())
+	}
+	if n == 0 {
+		return
+	}
+	c.ents = c.ents[n:]
+	// Release the array holding the evicted entries if most of it is unused,
+	// like unstable.shrinkEntriesArray.
+	if len(c.ents) < cap(c.ents)/2 {
+		c.ents = append([]pb.Entry(nil), c.ents...)
+	}
+}
+
+// truncateFrom drops the entries at and after index i.
+func (c *entryCache) truncateFrom(i uint64) {
+	if c == nil || len(c.ents) == 0 || i > c.last() {
+		return
+	}
+	if i <= c.first() {
+		c.clear()
+		return
+	}
+	n := i - c.first()
+	c.size -= entsSize(c.ents[n:])
+	// NB: cap the slice so that the next appends don't overwrite the truncated
+	// entries, which slices returned earlier may still refer to.
+	c.ents = c.ents[:n:n]
+}
+
+func (c *entryCache) clear() {
+	if c == nil {
+		return
+	}
+	c.ents, c.size = nil, 0
+}
+
+// slice returns the entries in [lo, hi) with the size limit if the cache can
+// serve them, i.e. if it contains lo and either hi-1 or enough entries to
+// reach the size limit, like Storage.Entries would.
+func (c *entryCache) slice(lo, hi uint64, maxSize entryEncodingSize) ([]pb.Entry, bool) {
+	if c == nil {
+		return nil, false
+	}
+	if len(c.ents) == 0 || lo < c.first() || lo > c.last() {
+		c.misses++
+		return nil, false
+	}
+	ents := c.ents[lo-c.first() : min(hi, c.last()+1)-c.first()]
+	limited := limitSize(ents, maxSize)
+	// If the cached entries end before hi-1 without reaching the size limit,
+	// Storage might return more entries.
+	if len(limited) == len(ents) && hi-1 > c.last() && entsSize(ents) < maxSize {
+		c.misses++
+		return nil, false
+	}
+	c.hits++
+	// NB: use the full slice expression to protect the cache from appends to
+	// the returned slice.
+	return limited[:len(limited):len(limited)], true
+}
+
+// term returns the term of the entry at index i, if cached.
+func (c *entryCache) term(i uint64) (uint64, bool) {
+	if c == nil || len(c.ents) == 0 || i < c.first() || i > c.last() {
+		return 0, false
+	}
+	return c.ents[i-c.first()].Term, true
+}
+
+func (c *entryCache) stats() EntryCacheStats {
+	if c == nil {
+		return EntryCacheStats{}
+	}
+	return EntryCacheStats{Hits: c.hits, Misses: c.misses, Entries: len(c.ents), Bytes: uint64(c.size)}
+}
@@ -0,0 +1,132 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package raft
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	pb "go.etcd.io/raft/v3/raftpb"
+)
+
+func TestEntryCache(t *testing.T) {
+	ents := index(1).terms(1, 1, 1, 2, 2)
+	entSize := entsSize(ents[:1])
+	c := newEntryCache(uint64(3 * entSize))
+	c.add(ents[:2])
+	c.add(ents[2:])
+	// Only the last 3 entries fit.
+	require.Equal(t, ents[2:], c.ents)
+	require.Equal(t, 3*entSize, c.size)
+
+	for _, tt := range []struct {
+		lo, hi  uint64
+		maxSize entryEncodingSize
+		want    []pb.Entry
+		ok      bool
+	}{
+		{3, 6, noLimit, ents[2:], true},
+		{4, 5, noLimit, ents[3:4], true},
+		{3, 6, entSize, ents[2:3], true},
+		// Entries before the cache.
+		{2, 6, noLimit, nil, false},
+		// Entries after the cache, in full or in part.
+		{6, 7, noLimit, nil, false},
+		{5, 7, noLimit, nil, false},
+		// The size limit is reached within the cache.
+		{5, 7, 0, ents[4:], true},
+	} {
+		got, ok := c.slice(tt.lo, tt.hi, tt.maxSize)
+		require.Equal(t, tt.ok, ok, "[%d,%d)", tt.lo, tt.hi)
+		require.Equal(t, tt.want, got, "[%d,%d)", tt.lo, tt.hi)
+	}
+	require.Equal(t, EntryCacheStats{Hits: 4, Misses: 3, Entries: 3, Bytes: uint64(3 * entSize)}, c.stats())
+
+	term, ok := c.term(4)
+	require.True(t, ok)
+	require.Equal(t, uint64(2), term)
+	_, ok = c.term(2)
+	require.False(t, ok)
+
+	// Overwriting entries replaces the following ones, without changing the
+	// slices returned before.
+	got, ok := c.slice(3, 6, noLimit)
+	require.True(t, ok)
+	c.add(index(4).terms(3))
+	require.Equal(t, index(3).terms(1, 3), c.ents)
+	require.Equal(t, ents[2:], got)
+
+	// A gap clears the cache.
+	c.add(index(7).terms(3))
+	require.Equal(t, index(7).terms(3), c.ents)
+
+	c.truncateFrom(7)
+	require.Empty(t, c.ents)
+	require.Zero(t, c.size)
+
+	// A nil cache is disabled.
+	var nilCache *entryCache
+	nilCache.add(ents)
+	_, ok = nilCache.slice(1, 2, noLimit)
+	require.False(t, ok)
+	require.Equal(t, EntryCacheStats{}, nilCache.stats())
+}
+
+// TestEntryCacheSpareStorage checks that the leader reads the entries it sends
+// to a follower that is slightly behind from the entry cache rather than from
+// Storage, and that the cache follows truncations of the log.
+func TestEntryCacheSpareStorage(t *testing.T) {
+	s := newTestMemoryStorage(withPeers(1, 2))
+	cfg := newTestConfig(1, 10, 1, s)
+	cfg.EntryCacheSize = 1 << 20
+	r := newRaft(cfg)
+	r.becomeCandidate()
+	r.becomeLeader()
+	for i := 0; i < 3; i++ {
+		require.NoError(t, r.Step(pb.Message{From: 1, To: 1, Type: pb.MsgProp, Entries: []pb.Entry{{Data: []byte("foo")}}}))
+	}
+	// Persist the entries, which moves them to the cache.
+	ents := r.raftLog.nextUnstableEnts()
+	require.Len(t, ents, 4)
+	require.NoError(t, s.Append(ents))
+	r.raftLog.stableTo(pbEntryID(&ents[len(ents)-1]))
+	require.Equal(t, 4, r.raftLog.cache.stats().Entries)
+	r.readMessages()
+
+	// The follower rejects an append and needs all the entries again.
+	entries := s.callStats.entries
+	require.NoError(t, r.Step(pb.Message{From: 2, To: 1, Term: r.Term, Type: pb.MsgAppResp, Index: 0, Reject: true}))
+	msgs := r.readMessages()
+	require.Len(t, msgs, 1)
+	require.Equal(t, ents, msgs[0].Entries)
+	require.Equal(t, entries, s.callStats.entries, "storage was read")
+	st := getStatus(r).EntryCache
+	require.Equal(t, uint64(1), st.Hits)
+	require.Zero(t, st.Misses)
+
+	// A new leader overwrites the uncommitted entries.
+	r.becomeFollower(r.Term+1, 2)
+	_, ok := r.raftLog.maybeAppend(logSlice{
+		term:    r.Term,
+		prev:    entryID{term: 1, index: 1},
+		entries: index(2).terms(r.Term),
+	}, 0)
+	require.True(t, ok)
+	require.Equal(t, 1, r.raftLog.cache.stats().Entries)
+	term, err := r.raftLog.term(2)
+	require.NoError(t, err)
+	require.Equal(t, r.Term, term)
+}
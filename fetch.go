@@ -0,0 +1,119 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package raft
+
+import (
+	pb "go.etcd.io/raft/v3/raftpb"
+	"go.etcd.io/raft/v3/tracker"
+)
+
+// entryFetcher reads the entries the leader sends to followers through an
+// AsyncStorage, without blocking on the reads done in the background.
+type entryFetcher struct {
+	storage AsyncStorage
+	// pending holds the first index of the reads in progress.
+	pending map[uint64]bool
+	// fetched holds the consecutive entries of the last reported read, until
+	// they are replaced by those of the next one or the leadership changes.
+	fetched []pb.Entry
+}
+
+func newEntryFetcher(s AsyncStorage) *entryFetcher {
+	return &entryFetcher{storage: s, pending: map[uint64]bool{}}
+}
+
+// read returns the entries in [lo, hi) with the size limit, like
+// Storage.Entries, from the fetched entries if they contain lo. Otherwise it
+// reads them with FetchEntries, and returns ErrEntriesPending if they are
+// being read in the background.
+func (f *entryFetcher) read(lo, hi, maxSize uint64) ([]pb.Entry, error) {
+	if n := uint64(len(f.fetched)); n > 0 && f.fetched[0].Index <= lo && lo < f.fetched[0].Index+n {
+		first := f.fetched[0].Index
+		ents := f.fetched[lo-first : min(hi-first, n)]
+		ents = limitSize(ents, entryEncodingSize(maxSize))
+		// NB: use the full slice expression to protect the fetched entries
+		// from appends to the returned slice.
+		return ents[:len(ents):len(ents)], nil
+	}
+	if f.pending[lo] {
+		return nil, ErrEntriesPending
+	}
+	ents, err := f.storage.FetchEntries(lo, hi, maxSize)
+	if err == ErrEntriesPending {
+		f.pending[lo] = true
+	}
+	return ents, err
+}
+
+// reset drops the fetched entries, which may not match the log after a change
+// of leadership. The reads in progress are still awaited.
+func (f *entryFetcher) reset() {
+	f.fetched = nil
+}
+
+// entriesToSend returns the entries to send to a follower in an append
+// starting at index i. With AsyncStorageReads, it returns ErrEntriesPending if
+// they are being read in the background.
+func (r *raft) entriesToSend(i uint64) ([]pb.Entry, error) {
+	l := r.raftLog
+	if r.fetcher == nil || i > l.lastIndex() {
+		return l.entries(i, r.maxMsgSize)
+	}
+	pending := r.fetcher.pending[i]
+	ents, err := l.sliceWith(i, l.lastIndex()+1, r.maxMsgSize, r.fetcher.read)
+	if err == ErrEntriesPending && !pending {
+		r.logger.Debugf("%x reading entries from %d in the background", r.id, i)
+	}
+	return ents, err
+}
+
+// storageEntriesMsg returns the MsgStorageEntries reporting the entries read
+// from lo, or the failure of the read.
+func storageEntriesMsg(lo uint64, ents []pb.Entry, err error) pb.Message {
+	return pb.Message{Type: pb.MsgStorageEntries, Index: lo, Entries: ents, Reject: err != nil}
+}
+
+// handleStorageEntries handles the entries read in the background, as
+// reported by the application, and sends them to the followers waiting for
+// them.
+func (r *raft) handleStorageEntries(m pb.Message) {
+	f := r.fetcher
+	if f == nil || !f.pending[m.Index] {
+		r.logger.Debugf("%x ignoring unexpected entries from %d", r.id, m.Index)
+		return
+	}
+	delete(f.pending, m.Index)
+	if m.Reject || len(m.Entries) == 0 {
+		r.logger.Debugf("%x failed to read entries from %d", r.id, m.Index)
+		return
+	}
+	// The log may have changed while the entries were read, in which case they
+	// are dropped. The terms of a log prefix match if the last ones match.
+	last := m.Entries[len(m.Entries)-1]
+	if m.Entries[0].Index != m.Index || last.Index != m.Index+uint64(len(m.Entries))-1 ||
+		!r.raftLog.matchTerm(entryID{term: last.Term, index: last.Index}) {
+		r.logger.Debugf("%x dropping stale entries [%d, %d]", r.id, m.Index, last.Index)
+		return
+	}
+	f.fetched = m.Entries
+	if r.state != StateLeader {
+		return
+	}
+	r.trk.Visit(func(id uint64, pr *tracker.Progress) {
+		if id != r.id && pr.Next == m.Index {
+			r.maybeSendAppend(id, false /* sendIfEmpty */)
+		}
+	})
+}
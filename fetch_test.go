@@ -0,0 +1,109 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package raft
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	pb "go.etcd.io/raft/v3/raftpb"
+)
+
+// asyncMemoryStorage is a MemoryStorage whose FetchEntries always reads the
+// entries in the background, recording the first index of the reads.
+type asyncMemoryStorage struct {
+	*MemoryStorage
+	reads []uint64
+}
+
+func (s *asyncMemoryStorage) FetchEntries(lo, _, _ uint64) ([]pb.Entry, error) {
+	s.reads = append(s.reads, lo)
+	return nil, ErrEntriesPending
+}
+
+func TestAsyncStorageReadsValidation(t *testing.T) {
+	cfg := newTestConfig(1, 10, 1, newTestMemoryStorage(withPeers(1)))
+	cfg.AsyncStorageReads = true
+	require.Error(t, cfg.validate())
+	cfg.Storage = &asyncMemoryStorage{MemoryStorage: newTestMemoryStorage(withPeers(1))}
+	require.NoError(t, cfg.validate())
+}
+
+func TestAsyncStorageReads(t *testing.T) {
+	s := &asyncMemoryStorage{MemoryStorage: newTestMemoryStorage(withPeers(1, 2))}
+	require.NoError(t, s.Append(index(1).terms(1, 1, 1, 1, 1)))
+	require.NoError(t, s.SetHardState(pb.HardState{Term: 1}))
+	cfg := newTestConfig(1, 10, 1, s)
+	cfg.AsyncStorageReads = true
+	r := newRaft(cfg)
+	r.becomeCandidate()
+	r.becomeLeader()
+	r.readMessages()
+
+	// The follower only has the first two entries, so the leader needs to send
+	// it the stable entries [3, 6) and the unstable entry 6 of its term.
+	reject := pb.Message{From: 2, To: 1, Term: 2, Type: pb.MsgAppResp, Index: 5, Reject: true, RejectHint: 2, LogTerm: 1}
+	heartbeatResp := pb.Message{From: 2, To: 1, Term: 2, Type: pb.MsgHeartbeatResp}
+	sent := func() []pb.Message {
+		var msgs []pb.Message
+		for _, m := range r.readMessages() {
+			if m.Type == pb.MsgApp {
+				msgs = append(msgs, m)
+			}
+		}
+		return msgs
+	}
+	require.NoError(t, r.Step(reject))
+	require.Equal(t, []uint64{3}, s.reads)
+	require.Empty(t, sent())
+
+	// The read is not started again while in progress.
+	require.NoError(t, r.Step(heartbeatResp))
+	require.Equal(t, []uint64{3}, s.reads)
+	require.Empty(t, sent())
+
+	// A failed read is retried at the next attempt to send the entries.
+	require.NoError(t, r.Step(storageEntriesMsg(3, nil, errors.New("failed"))))
+	require.Empty(t, sent())
+	require.NoError(t, r.Step(heartbeatResp))
+	require.Equal(t, []uint64{3, 3}, s.reads)
+
+	// Entries that don't match the log are dropped.
+	require.NoError(t, r.Step(storageEntriesMsg(3, index(3).terms(1, 1, 2), nil)))
+	require.Empty(t, sent())
+	require.NoError(t, r.Step(heartbeatResp))
+	require.Equal(t, []uint64{3, 3, 3}, s.reads)
+
+	// Entries for a read not in progress are ignored.
+	require.NoError(t, r.Step(storageEntriesMsg(4, index(4).terms(1, 1), nil)))
+	require.Empty(t, sent())
+
+	// The entries are sent once reported.
+	require.NoError(t, r.Step(storageEntriesMsg(3, index(3).terms(1, 1, 1), nil)))
+	msgs := sent()
+	require.Len(t, msgs, 1)
+	require.Equal(t, uint64(2), msgs[0].Index)
+	require.Equal(t, index(3).terms(1, 1, 1, 2), msgs[0].Entries)
+
+	// The leader reads entries again after a change of leadership.
+	r.becomeFollower(3, None)
+	r.becomeCandidate()
+	r.becomeLeader()
+	r.readMessages()
+	require.NoError(t, r.Step(pb.Message{From: 2, To: 1, Term: 4, Type: pb.MsgAppResp, Index: 6, Reject: true, RejectHint: 2, LogTerm: 1}))
+	require.Equal(t, []uint64{3, 3, 3, 3}, s.reads)
+}
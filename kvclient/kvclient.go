@@ -0,0 +1,610 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package kvclient is a leader-aware client for the HTTP key/value API served
+// by cmd/raftnode.
+//
+// The client discovers the leader through the /status endpoint of the
+// configured replicas, follows the redirects issued by followers, and retries
+// with exponential backoff while the group has no leader. Every write carries
+// a session identifier and a per-session sequence number which the replicas
+// use to discard duplicates, so that retrying a write whose outcome is unknown
+// (for example, after a commit timeout) applies it at most once.
+//
+// A Client is safe for concurrent use, but writes issued by the same Client
+// are serialized so that sequence numbers are applied in order. Use one Client
+// per logical client (session) that should make progress independently; they
+// can share connections through Config.Transport.
+package kvclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	mrand "math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// HeaderLeader is set by followers on redirects to the address of the
+	// leader.
+	HeaderLeader = "X-Raft-Leader"
+	// HeaderSession carries the session identifier of a write.
+	HeaderSession = "X-Session-ID"
+	// HeaderSequence carries the per-session sequence number of a write.
+	HeaderSequence = "X-Sequence"
+)
+
+var (
+	// ErrNoLeader is returned when no leader could be found before the retry
+	// budget was exhausted. The operation was not applied.
+	ErrNoLeader = errors.New("kvclient: no leader available")
+	// ErrUnknownOutcome is returned when the retry budget was exhausted after
+	// a write may have reached a replica. The write may or may not have been
+	// applied, but it will never be applied more than once.
+	ErrUnknownOutcome = errors.New("kvclient: outcome of the operation is unknown")
+	// ErrStaleSequence is returned when the replica refused a write because
+	// the session already applied a later sequence number, which happens if
+	// the session is shared by concurrent writers. The write was not applied,
+	// and isn't retried.
+	ErrStaleSequence = errors.New("kvclient: stale session sequence")
+)
+
+// Consistency selects the guarantees of a read.
+type Consistency int
+
+const (
+	// Linearizable reads go through the replicated log and observe every
+	// write that completed before the read was issued.
+	Linearizable Consistency = iota
+	// LeaderLocal reads are served by the leader from its local state
+	// without a round-trip through the log. They may be stale if the
+	// replica has been deposed without noticing.
+	LeaderLocal
+	// Serializable reads are served by any replica from its local state and
+	// may return arbitrarily stale data.
+	Serializable
+)
+
+func (c Consistency) String() string {
+	switch c {
+	case Linearizable:
+		return "linearizable"
+	case LeaderLocal:
+		return "leader"
+	case Serializable:
+		return "stale"
+	default:
+		return fmt.Sprintf("Consistency(%d)", int(c))
+	}
+}
+
+// ParseConsistency parses the name returned by Consistency.String.
+func ParseConsistency(s string) (Consistency, error) {
+	for _, c := range []Consistency{Linearizable, LeaderLocal, Serializable} {
+		if c.String() == s {
+			return c, nil
+		}
+	}
+	return 0, fmt.Errorf("kvclient: unknown consistency %q", s)
+}
+
+// Config configures a Client.
+type Config struct {
+	// Endpoints are the base URLs of the replicas, for example
+	// "http://10.0.0.11:9001". At least one is required.
+	Endpoints []string
+	// RequestTimeout bounds each individual attempt. Defaults to 5s.
+	RequestTimeout time.Duration
+	// MaxRetries is the number of attempts made after the first one before
+	// giving up. Redirects to a newly learned leader do not count against
+	// it. Defaults to 10; a negative value disables retries.
+	MaxRetries int
+	// BackoffBase and BackoffMax bound the exponential backoff (with full
+	// jitter) between retries. They default to 50ms and 2s.
+	BackoffBase time.Duration
+	BackoffMax  time.Duration
+	// MaxIdleConnsPerHost sizes the connection pool of the default
+	// transport. Defaults to 32. Ignored if Transport is set.
+	MaxIdleConnsPerHost int
+	// Transport, if set, is used for all requests. It allows several clients
+	// to share a connection pool.
+	Transport http.RoundTripper
+	// Session is the session identifier used for writes. A random identifier
+	// is generated if empty.
+	Session string
+	// OnAttempt, if set, is called after every attempt, including redirects
+	// and retried failures. Like OnLeaderChange, it is called synchronously
+	// and may be called concurrently if the Client is shared by several
+	// goroutines.
+	OnAttempt func(Attempt)
+	// OnLeaderChange, if set, is called whenever the leader known to the
+	// client changes. An empty leader means the client does not know of any
+	// leader until the next successful discovery or redirect.
+	OnLeaderChange func(leader string)
+}
+
+// Status is the response of a replica's /status endpoint.
+type Status struct {
+	ID         uint64 `json:"id"`
+	Addr       string `json:"addr"`
+	LeaderID   uint64 `json:"leader_id"`
+	LeaderAddr string `json:"leader_addr"`
+	Term       uint64 `json:"term"`
+	Commit     uint64 `json:"commit"`
+}
+
+// Client is a leader-aware client for the raftnode key/value API.
+type Client struct {
+	cfg     Config
+	http    *http.Client
+	session string
+
+	mu     sync.Mutex
+	leader string // best known leader, or empty
+	next   int    // round-robin position when no leader is known
+	rng    *mrand.Rand
+
+	// writeMu serializes writes so that sequence numbers are applied in
+	// order.
+	writeMu sync.Mutex
+	seq     uint64
+}
+
+// New returns a Client for the given configuration.
+func New(cfg Config) (*Client, error) {
+	if len(cfg.Endpoints) == 0 {
+		return nil, errors.New("kvclient: no endpoints")
+	}
+	eps := make([]string, 0, len(cfg.Endpoints))
+	for _, ep := range cfg.Endpoints {
+		ep = normalizeEndpoint(ep)
+		if _, err := url.Parse(ep); err != nil {
+			return nil, fmt.Errorf("kvclient: invalid endpoint %q: %w", ep, err)
+		}
+		eps = append(eps, ep)
+	}
+	cfg.Endpoints = eps
+	if cfg.RequestTimeout == 0 {
+		cfg.RequestTimeout = 5 * time.Second
+	}
+	if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = 10
+	} else if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = 0
+	}
+	if cfg.BackoffBase == 0 {
+		cfg.BackoffBase = 50 * time.Millisecond
+	}
+	if cfg.BackoffMax == 0 {
+		cfg.BackoffMax = 2 * time.Second
+	}
+	if cfg.MaxIdleConnsPerHost == 0 {
+		cfg.MaxIdleConnsPerHost = 32
+	}
+	rt := cfg.Transport
+	if rt == nil {
+		rt = &http.Transport{
+			Proxy:               http.ProxyFromEnvironment,
+			MaxIdleConns:        cfg.MaxIdleConnsPerHost * len(eps),
+			MaxIdleConnsPerHost: cfg.MaxIdleConnsPerHost,
+			IdleConnTimeout:     90 * time.Second,
+		}
+	}
+	session := cfg.Session
+	if session == "" {
+		var b [16]byte
+		if _, err := rand.Read(b[:]); err != nil {
+			return nil, err
+		}
+		session = hex.EncodeToString(b[:])
+	}
+	return &Client{
+		cfg:     cfg,
+		http:    &http.Client{Transport: rt},
+		session: session,
+		rng:     mrand.New(mrand.NewSource(time.Now().UnixNano())),
+	}, nil
+}
+
+// Session returns the session identifier used for writes.
+func (c *Client) Session() string { return c.session }
+
+// Leader returns the address of the leader, discovering it if necessary.
+func (c *Client) Leader(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	leader := c.leader
+	c.mu.Unlock()
+	if leader != "" {
+		return leader, nil
+	}
+	return c.discover(ctx)
+}
+
+// Status queries the /status endpoint of a single replica.
+func (c *Client) Status(ctx context.Context, endpoint string) (Status, error) {
+	var st Status
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.RequestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, normalizeEndpoint(endpoint)+"/status", nil)
+	if err != nil {
+		return st, err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return st, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return st, fmt.Errorf("kvclient: status of %s: %s", endpoint, resp.Status)
+	}
+	err = json.NewDecoder(resp.Body).Decode(&st)
+	return st, err
+}
+
+// Get reads the value of key. The boolean result reports whether the key
+// exists.
+func (c *Client) Get(ctx context.Context, key string, consistency Consistency) ([]byte, bool, error) {
+	if key == "" {
+		return nil, false, errors.New("kvclient: empty key")
+	}
+	path := "/kv/" + url.PathEscape(key)
+	if consistency != Linearizable {
+		path += "?consistency=" + consistency.String()
+	}
+	r, err := c.do(ctx, request{
+		method:      http.MethodGet,
+		path:        path,
+		anyReplica:  consistency == Serializable,
+		allowStatus: []int{http.StatusNotFound},
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return r.body, r.status == http.StatusOK, nil
+}
+
+// Put sets the value of key.
+func (c *Client) Put(ctx context.Context, key string, value []byte) error {
+	if key == "" {
+		return errors.New("kvclient: empty key")
+	}
+	return c.write(ctx, http.MethodPut, "/kv/"+url.PathEscape(key), value)
+}
+
+// Append appends an opaque payload to the replicated log through the /op
+// endpoint.
+func (c *Client) Append(ctx context.Context, payload []byte) error {
+	return c.write(ctx, http.MethodPost, "/op", payload)
+}
+
+func (c *Client) write(ctx context.Context, method, path string, body []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	c.seq++
+	_, err := c.do(ctx, request{
+		method: method,
+		path:   path,
+		body:   body,
+		header: http.Header{
+			HeaderSession:  {c.session},
+			HeaderSequence: {strconv.FormatUint(c.seq, 10)},
+		},
+		write: true,
+	})
+	return err
+}
+
+type request struct {
+	method      string
+	path        string
+	body        []byte
+	header      http.Header
+	write       bool
+	anyReplica  bool
+	allowStatus []int
+}
+
+type response struct {
+	status int
+	body   []byte
+}
+
+// errRetry marks failures after which the request may be retried.
+type errRetry struct {
+	err error
+	// sent is set if the request may have reached a replica.
+	sent bool
+}
+
+func (e *errRetry) Error() string { return e.err.Error() }
+func (e *errRetry) Unwrap() error { return e.err }
+
+func (c *Client) do(ctx context.Context, req request) (response, error) {
+	var (
+		lastErr   error
+		maybeRan  bool
+		retries   int
+		redirects int
+	)
+	for {
+		start := time.Now()
+		target, err := c.target(ctx, req.anyReplica)
+		if err != nil {
+			c.observe(Attempt{Start: start, Duration: time.Since(start), Outcome: Classify(err), Err: err})
+		} else {
+			var r response
+			start = time.Now()
+			r, err = c.attempt(ctx, target, req)
+			c.observe(Attempt{Target: target, Start: start, Duration: time.Since(start), Outcome: Classify(err), Err: err})
+			if err == nil {
+				return r, nil
+			}
+			var retry *errRetry
+			if !errors.As(err, &retry) {
+				return response{}, err
+			}
+			if retry.sent && req.write {
+				maybeRan = true
+			}
+			if ctx.Err() != nil {
+				// The caller gave up, which says nothing about the replica.
+				lastErr = err
+				break
+			}
+			var redirect *redirectError
+			if errors.As(err, &redirect) {
+				c.setLeader(redirect.leader, target)
+				// Follow redirects to a different replica right away, but
+				// back off if the replicas keep pointing at each other.
+				if redirect.leader != "" && redirect.leader != target && redirects < len(c.cfg.Endpoints) {
+					redirects++
+					lastErr = err
+					continue
+				}
+			} else {
+				c.forgetLeader(target)
+			}
+		}
+		lastErr = err
+		if ctx.Err() != nil || retries >= c.cfg.MaxRetries {
+			break
+		}
+		if err := c.backoff(ctx, retries); err != nil {
+			break
+		}
+		retries++
+		redirects = 0
+	}
+	if ctx.Err() != nil {
+		lastErr = ctx.Err()
+	}
+	if maybeRan {
+		return response{}, fmt.Errorf("%w: %w", ErrUnknownOutcome, lastErr)
+	}
+	if errors.Is(lastErr, ErrNoLeader) {
+		return response{}, lastErr
+	}
+	var redirect *redirectError
+	if errors.As(lastErr, &redirect) {
+		return response{}, fmt.Errorf("%w: %w", ErrNoLeader, lastErr)
+	}
+	return response{}, lastErr
+}
+
+type redirectError struct {
+	from, leader string
+}
+
+func (e *redirectError) Error() string {
+	if e.leader == "" {
+		return fmt.Sprintf("%s is not the leader and knows no leader", e.from)
+	}
+	return fmt.Sprintf("%s is not the leader, redirected to %s", e.from, e.leader)
+}
+
+func (c *Client) attempt(ctx context.Context, target string, req request) (response, error) {
+	actx, cancel := context.WithTimeout(ctx, c.cfg.RequestTimeout)
+	defer cancel()
+	var body io.Reader
+	if req.body != nil {
+		body = bytes.NewReader(req.body)
+	}
+	hreq, err := http.NewRequestWithContext(actx, req.method, target+req.path, body)
+	if err != nil {
+		return response{}, err
+	}
+	for k, v := range req.header {
+		hreq.Header[k] = v
+	}
+	resp, err := c.http.Do(hreq)
+	if err != nil {
+		return response{}, &errRetry{err: err, sent: !isDialError(err)}
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return response{}, &errRetry{err: err, sent: true}
+	}
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		return response{status: resp.StatusCode, body: data}, nil
+	case resp.StatusCode == http.StatusConflict:
+		return response{}, &errRetry{err: &redirectError{from: target, leader: normalizeEndpoint(resp.Header.Get(HeaderLeader))}}
+	case resp.StatusCode == http.StatusGatewayTimeout, resp.StatusCode == http.StatusServiceUnavailable,
+		resp.StatusCode >= http.StatusInternalServerError:
+		return response{}, &errRetry{err: &StatusError{Code: resp.StatusCode, Message: strings.TrimSpace(string(data))}, sent: true}
+	case resp.StatusCode == http.StatusPreconditionFailed && req.write:
+		return response{}, fmt.Errorf("%w: %w", ErrStaleSequence, &StatusError{Code: resp.StatusCode, Message: strings.TrimSpace(string(data))})
+	}
+	for _, s := range req.allowStatus {
+		if resp.StatusCode == s {
+			return response{status: resp.StatusCode, body: data}, nil
+		}
+	}
+	return response{}, &StatusError{Code: resp.StatusCode, Message: strings.TrimSpace(string(data))}
+}
+
+// StatusError is returned for unexpected HTTP responses.
+type StatusError struct {
+	Code    int
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("kvclient: %d %s: %s", e.Code, http.StatusText(e.Code), e.Message)
+}
+
+// target picks the replica for the next attempt.
+func (c *Client) target(ctx context.Context, anyReplica bool) (string, error) {
+	c.mu.Lock()
+	if anyReplica {
+		ep := c.cfg.Endpoints[c.next%len(c.cfg.Endpoints)]
+		c.next++
+		c.mu.Unlock()
+		return ep, nil
+	}
+	leader := c.leader
+	c.mu.Unlock()
+	if leader != "" {
+		return leader, nil
+	}
+	return c.discover(ctx)
+}
+
+// discover asks all replicas for their view of the leader and adopts the one
+// reported for the highest term.
+func (c *Client) discover(ctx context.Context) (string, error) {
+	type result struct {
+		st  Status
+		err error
+	}
+	results := make(chan result, len(c.cfg.Endpoints))
+	for _, ep := range c.cfg.Endpoints {
+		go func(ep string) {
+			st, err := c.Status(ctx, ep)
+			if err == nil && st.Addr == "" {
+				st.Addr = ep
+			}
+			results <- result{st, err}
+		}(ep)
+	}
+	var best Status
+	for range c.cfg.Endpoints {
+		r := <-results
+		if r.err != nil || r.st.LeaderID == 0 {
+			continue
+		}
+		if r.st.Term > best.Term || best.LeaderID == 0 {
+			best = r.st
+			if best.LeaderAddr == "" && best.LeaderID == best.ID {
+				best.LeaderAddr = best.Addr
+			}
+		}
+	}
+	if best.LeaderAddr == "" {
+		return "", &errRetry{err: ErrNoLeader}
+	}
+	leader := normalizeEndpoint(best.LeaderAddr)
+	c.updateLeader(func(string) string { return leader })
+	return leader, nil
+}
+
+func (c *Client) setLeader(leader, from string) {
+	if leader == from {
+		leader = ""
+	}
+	c.updateLeader(func(string) string { return leader })
+}
+
+func (c *Client) forgetLeader(target string) {
+	c.updateLeader(func(cur string) string {
+		if cur == target {
+			return ""
+		}
+		return cur
+	})
+}
+
+// updateLeader replaces the known leader with the result of f and reports
+// the change to Config.OnLeaderChange.
+func (c *Client) updateLeader(f func(cur string) string) {
+	c.mu.Lock()
+	prev := c.leader
+	c.leader = f(prev)
+	cur := c.leader
+	c.mu.Unlock()
+	if cur != prev && c.cfg.OnLeaderChange != nil {
+		c.cfg.OnLeaderChange(cur)
+	}
+}
+
+func (c *Client) observe(a Attempt) {
+	if c.cfg.OnAttempt != nil {
+		c.cfg.OnAttempt(a)
+	}
+}
+
+func (c *Client) backoff(ctx context.Context, attempt int) error {
+	d := float64(c.cfg.BackoffBase) * math.Pow(2, float64(attempt))
+	if d > float64(c.cfg.BackoffMax) {
+		d = float64(c.cfg.BackoffMax)
+	}
+	c.mu.Lock()
+	d *= c.rng.Float64()
+	c.mu.Unlock()
+	t := time.NewTimer(time.Duration(d))
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// CloseIdleConnections closes the idle connections of the client's pool.
+func (c *Client) CloseIdleConnections() {
+	c.http.CloseIdleConnections()
+}
+
+func normalizeEndpoint(ep string) string {
+	ep = strings.TrimSpace(ep)
+	if ep == "" {
+		return ""
+	}
+	if !strings.Contains(ep, "://") {
+		ep = "http://" + ep
+	}
+	return strings.TrimRight(ep, "/")
+}
+
+// isDialError reports whether err happened while establishing the
+// connection, in which case the request never reached the replica.
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
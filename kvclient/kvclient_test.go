@@ -0,0 +1,328 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kvclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type call struct {
+	method, path, query string
+	session             string
+	seq                 uint64
+	body                string
+}
+
+// fakeReplica mimics the HTTP API of a raftnode replica. The handler decides
+// how to answer each key/value request.
+type fakeReplica struct {
+	*httptest.Server
+	id uint64
+
+	mu       sync.Mutex
+	leaderID uint64
+	leader   string
+	calls    []call
+	handle   func(w http.ResponseWriter, c call)
+}
+
+func newFakeReplica(t *testing.T, id uint64) *fakeReplica {
+	f := &fakeReplica{id: id}
+	f.handle = func(w http.ResponseWriter, c call) { io.WriteString(w, "ok") }
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		if r.URL.Path == "/status" {
+			st := Status{ID: f.id, Addr: f.URL, LeaderID: f.leaderID, LeaderAddr: f.leader, Term: 1}
+			f.mu.Unlock()
+			_ = json.NewEncoder(w).Encode(st)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		seq, _ := strconv.ParseUint(r.Header.Get(HeaderSequence), 10, 64)
+		c := call{
+			method:  r.Method,
+			path:    r.URL.Path,
+			query:   r.URL.RawQuery,
+			session: r.Header.Get(HeaderSession),
+			seq:     seq,
+			body:    string(body),
+		}
+		f.calls = append(f.calls, c)
+		handle := f.handle
+		f.mu.Unlock()
+		handle(w, c)
+	}))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *fakeReplica) setLeader(l *fakeReplica) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if l == nil {
+		f.leaderID, f.leader = 0, ""
+		return
+	}
+	f.leaderID, f.leader = l.id, l.URL
+}
+
+func (f *fakeReplica) setHandler(h func(w http.ResponseWriter, c call)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.handle = h
+}
+
+func (f *fakeReplica) getCalls() []call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]call(nil), f.calls...)
+}
+
+func newTestClient(t *testing.T, replicas ...*fakeReplica) *Client {
+	var eps []string
+	for _, r := range replicas {
+		eps = append(eps, r.URL)
+	}
+	c, err := New(Config{
+		Endpoints:   eps,
+		MaxRetries:  3,
+		BackoffBase: time.Millisecond,
+		BackoffMax:  5 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	return c
+}
+
+func TestClientDiscoversLeader(t *testing.T) {
+	r1, r2, r3 := newFakeReplica(t, 1), newFakeReplica(t, 2), newFakeReplica(t, 3)
+	for _, r := range []*fakeReplica{r1, r2, r3} {
+		r.setLeader(r2)
+	}
+	c := newTestClient(t, r1, r2, r3)
+
+	leader, err := c.Leader(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, r2.URL, leader)
+
+	require.NoError(t, c.Put(context.Background(), "a", []byte("1")))
+	require.NoError(t, c.Append(context.Background(), []byte("x")))
+	assert.Empty(t, r1.getCalls())
+	assert.Empty(t, r3.getCalls())
+	calls := r2.getCalls()
+	require.Len(t, calls, 2)
+	assert.Equal(t, call{method: http.MethodPut, path: "/kv/a", session: c.Session(), seq: 1, body: "1"}, calls[0])
+	assert.Equal(t, call{method: http.MethodPost, path: "/op", session: c.Session(), seq: 2, body: "x"}, calls[1])
+}
+
+func TestClientFollowsRedirect(t *testing.T) {
+	r1, r2 := newFakeReplica(t, 1), newFakeReplica(t, 2)
+	// r1 still believes it is the leader, but has been deposed.
+	r1.setLeader(r1)
+	r1.setHandler(func(w http.ResponseWriter, c call) {
+		w.Header().Set(HeaderLeader, r2.URL)
+		http.Error(w, "not leader", http.StatusConflict)
+	})
+	c := newTestClient(t, r1)
+
+	require.NoError(t, c.Put(context.Background(), "a", []byte("1")))
+	require.NoError(t, c.Put(context.Background(), "b", []byte("2")))
+	assert.Len(t, r1.getCalls(), 1)
+	assert.Len(t, r2.getCalls(), 2)
+}
+
+func TestClientRetriesWithSameSequence(t *testing.T) {
+	r1 := newFakeReplica(t, 1)
+	r1.setLeader(r1)
+	var attempts int
+	r1.setHandler(func(w http.ResponseWriter, c call) {
+		attempts++
+		if attempts == 1 {
+			http.Error(w, "commit timeout", http.StatusGatewayTimeout)
+			return
+		}
+		io.WriteString(w, "ok")
+	})
+	c := newTestClient(t, r1)
+
+	require.NoError(t, c.Put(context.Background(), "a", []byte("1")))
+	require.NoError(t, c.Put(context.Background(), "a", []byte("2")))
+	calls := r1.getCalls()
+	require.Len(t, calls, 3)
+	assert.Equal(t, []uint64{1, 1, 2}, []uint64{calls[0].seq, calls[1].seq, calls[2].seq})
+	for _, cl := range calls {
+		assert.Equal(t, c.Session(), cl.session)
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	t.Run("no leader", func(t *testing.T) {
+		r1, r2 := newFakeReplica(t, 1), newFakeReplica(t, 2)
+		c := newTestClient(t, r1, r2)
+		err := c.Put(context.Background(), "a", []byte("1"))
+		assert.ErrorIs(t, err, ErrNoLeader)
+		assert.NotErrorIs(t, err, ErrUnknownOutcome)
+	})
+	t.Run("unknown outcome", func(t *testing.T) {
+		r1 := newFakeReplica(t, 1)
+		r1.setLeader(r1)
+		r1.setHandler(func(w http.ResponseWriter, c call) {
+			http.Error(w, "commit timeout", http.StatusGatewayTimeout)
+		})
+		c := newTestClient(t, r1)
+		err := c.Put(context.Background(), "a", []byte("1"))
+		assert.ErrorIs(t, err, ErrUnknownOutcome)
+		assert.Len(t, r1.getCalls(), 4)
+	})
+	t.Run("permanent", func(t *testing.T) {
+		r1 := newFakeReplica(t, 1)
+		r1.setLeader(r1)
+		r1.setHandler(func(w http.ResponseWriter, c call) {
+			http.Error(w, "bad request", http.StatusBadRequest)
+		})
+		c := newTestClient(t, r1)
+		err := c.Put(context.Background(), "a", []byte("1"))
+		var serr *StatusError
+		require.ErrorAs(t, err, &serr)
+		assert.Equal(t, http.StatusBadRequest, serr.Code)
+		assert.Len(t, r1.getCalls(), 1)
+	})
+	t.Run("stale sequence", func(t *testing.T) {
+		r1 := newFakeReplica(t, 1)
+		r1.setLeader(r1)
+		r1.setHandler(func(w http.ResponseWriter, c call) {
+			http.Error(w, "stale sequence", http.StatusPreconditionFailed)
+		})
+		c := newTestClient(t, r1)
+		err := c.Put(context.Background(), "a", []byte("1"))
+		assert.ErrorIs(t, err, ErrStaleSequence)
+		assert.NotErrorIs(t, err, ErrUnknownOutcome)
+		assert.Equal(t, Rejected, Classify(err))
+		assert.Len(t, r1.getCalls(), 1)
+	})
+}
+
+func TestClientGet(t *testing.T) {
+	r1, r2 := newFakeReplica(t, 1), newFakeReplica(t, 2)
+	for _, r := range []*fakeReplica{r1, r2} {
+		r.setLeader(r1)
+		r.setHandler(func(w http.ResponseWriter, c call) {
+			if c.path == "/kv/missing" {
+				http.Error(w, "not found", http.StatusNotFound)
+				return
+			}
+			io.WriteString(w, "value")
+		})
+	}
+	c := newTestClient(t, r1, r2)
+	ctx := context.Background()
+
+	v, found, err := c.Get(ctx, "a", Linearizable)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "value", string(v))
+
+	_, found, err = c.Get(ctx, "missing", LeaderLocal)
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	// Serializable reads are spread over all replicas.
+	for i := 0; i < 2; i++ {
+		_, _, err = c.Get(ctx, "a", Serializable)
+		require.NoError(t, err)
+	}
+
+	var queries []string
+	for _, cl := range r1.getCalls() {
+		assert.Empty(t, cl.session)
+		queries = append(queries, cl.query)
+	}
+	assert.Equal(t, []string{"", "consistency=leader", "consistency=stale"}, queries)
+	require.Len(t, r2.getCalls(), 1)
+	assert.Equal(t, "consistency=stale", r2.getCalls()[0].query)
+}
+
+func TestParseConsistency(t *testing.T) {
+	for _, c := range []Consistency{Linearizable, LeaderLocal, Serializable} {
+		got, err := ParseConsistency(c.String())
+		require.NoError(t, err)
+		assert.Equal(t, c, got)
+	}
+	_, err := ParseConsistency("eventual")
+	assert.Error(t, err)
+}
+
+func TestClientObservesAttempts(t *testing.T) {
+	r1, r2 := newFakeReplica(t, 1), newFakeReplica(t, 2)
+	r1.setLeader(r1)
+	r1.setHandler(func(w http.ResponseWriter, c call) {
+		w.Header().Set(HeaderLeader, r2.URL)
+		http.Error(w, "not leader", http.StatusConflict)
+	})
+	var (
+		attempts []Attempt
+		leaders  []string
+	)
+	c, err := New(Config{
+		Endpoints:      []string{r1.URL},
+		OnAttempt:      func(a Attempt) { attempts = append(attempts, a) },
+		OnLeaderChange: func(l string) { leaders = append(leaders, l) },
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, c.Put(context.Background(), "a", []byte("1")))
+	require.Len(t, attempts, 2)
+	assert.Equal(t, r1.URL, attempts[0].Target)
+	assert.Equal(t, Redirect, attempts[0].Outcome)
+	assert.Error(t, attempts[0].Err)
+	assert.Equal(t, r2.URL, attempts[1].Target)
+	assert.Equal(t, OK, attempts[1].Outcome)
+	assert.NoError(t, attempts[1].Err)
+	assert.Equal(t, []string{r1.URL, r2.URL}, leaders)
+}
+
+func TestClassify(t *testing.T) {
+	for _, tt := range []struct {
+		err  error
+		want Outcome
+	}{
+		{nil, OK},
+		{&redirectError{from: "a", leader: "b"}, Redirect},
+		{&errRetry{err: &redirectError{from: "a"}}, NoLeader},
+		{&errRetry{err: ErrNoLeader}, NoLeader},
+		{&StatusError{Code: http.StatusGatewayTimeout}, CommitTimeout},
+		{&StatusError{Code: http.StatusInternalServerError}, ServerError},
+		{&StatusError{Code: http.StatusBadRequest}, Rejected},
+		{context.Canceled, Canceled},
+		{fmt.Errorf("%w: %w", ErrUnknownOutcome, context.DeadlineExceeded), Timeout},
+		{&net.OpError{Op: "dial", Err: errors.New("connection refused")}, ConnRefused},
+		{io.ErrUnexpectedEOF, ConnError},
+	} {
+		assert.Equal(t, tt.want, Classify(tt.err), "%v", tt.err)
+	}
+}
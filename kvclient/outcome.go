@@ -0,0 +1,128 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kvclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Outcome classifies the result of a single attempt, or the cause of a
+// failed operation.
+type Outcome int
+
+const (
+	// OK means the replica answered the request.
+	OK Outcome = iota
+	// Redirect means the replica was not the leader and pointed the client
+	// at the replica it believes to be the leader.
+	Redirect
+	// NoLeader means no replica knew of a leader.
+	NoLeader
+	// CommitTimeout means the replica accepted the request but did not see
+	// it applied in time (HTTP 504).
+	CommitTimeout
+	// Timeout means the attempt did not complete within the request timeout.
+	Timeout
+	// ConnRefused means the connection to the replica could not be
+	// established.
+	ConnRefused
+	// ConnError means the connection failed after it was established.
+	ConnError
+	// ServerError means the replica failed the request with another 5xx
+	// status.
+	ServerError
+	// Rejected means the replica rejected the request with a 4xx status.
+	// Such requests are not retried.
+	Rejected
+	// Canceled means the caller's context was canceled.
+	Canceled
+)
+
+var outcomeNames = [...]string{
+	OK:            "ok",
+	Redirect:      "redirect",
+	NoLeader:      "no_leader",
+	CommitTimeout: "commit_timeout",
+	Timeout:       "timeout",
+	ConnRefused:   "conn_refused",
+	ConnError:     "conn_error",
+	ServerError:   "server_error",
+	Rejected:      "rejected",
+	Canceled:      "canceled",
+}
+
+func (o Outcome) String() string {
+	if o >= 0 && int(o) < len(outcomeNames) {
+		return outcomeNames[o]
+	}
+	return fmt.Sprintf("Outcome(%d)", int(o))
+}
+
+// Classify returns the Outcome corresponding to an error returned by the
+// Client, or reported in an Attempt.
+func Classify(err error) Outcome {
+	if err == nil {
+		return OK
+	}
+	var (
+		redirect *redirectError
+		status   *StatusError
+		opErr    *net.OpError
+		netErr   net.Error
+	)
+	switch {
+	case errors.As(err, &redirect):
+		if redirect.leader == "" {
+			return NoLeader
+		}
+		return Redirect
+	case errors.Is(err, ErrNoLeader):
+		return NoLeader
+	case errors.As(err, &status):
+		switch {
+		case status.Code == http.StatusGatewayTimeout:
+			return CommitTimeout
+		case status.Code >= http.StatusInternalServerError:
+			return ServerError
+		}
+		return Rejected
+	case errors.Is(err, context.Canceled):
+		return Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return Timeout
+	case errors.As(err, &opErr) && opErr.Op == "dial":
+		return ConnRefused
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return Timeout
+	}
+	return ConnError
+}
+
+// Attempt describes a single request sent by the Client to a replica. Leader
+// discovery that finds no leader is reported as an attempt with an empty
+// Target and the NoLeader outcome.
+type Attempt struct {
+	Target   string
+	Start    time.Time
+	Duration time.Duration
+	Outcome  Outcome
+	// Err is the error of a failed attempt.
+	Err error
+}
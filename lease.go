@@ -0,0 +1,253 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package raft
+
+import (
+	"time"
+
+	"go.etcd.io/raft/v3/quorum"
+	pb "go.etcd.io/raft/v3/raftpb"
+)
+
+// Clock is the source of time used for leader leases. Raft never compares
+// times of different peers directly, but relies on the clocks of any two
+// peers to be at most Config.MaxClockOffset apart.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// leaseRound is a round of heartbeats sent by the leader.
+type leaseRound struct {
+	seq uint64
+	at  time.Time
+	// committed is the commit index of the leader when sending the round.
+	committed uint64
+}
+
+// maxLeaseRounds bounds the number of heartbeat rounds the leader tracks
+// while waiting for acknowledgements. Older rounds are dropped first.
+const maxLeaseRounds = 128
+
+// leaderLease holds the state of the leader lease protocol (see the "Leader
+// leases" section of the package documentation). The leader numbers its
+// heartbeat rounds, and a follower acknowledging a round promises not to vote
+// for another candidate for the lease duration. Once a quorum has acknowledged
+// a round, the leader holds the lease until the duration, shortened by the
+// maximum clock offset, has passed since it sent the round.
+//
+// The numbered rounds also serve follower reads (see the "Follower reads"
+// section of the package documentation): a round acknowledged by a quorum
+// proves that the commit index of the leader when sending it covered every
+// entry committed by then.
+type leaderLease struct {
+	duration      time.Duration
+	maxOffset     time.Duration
+	followerReads bool
+	clock         Clock
+
+	// The fields below are only used by the leader and reset on every state
+	// change.
+	//
+	// seq is the number of the last heartbeat round sent in this term.
+	seq uint64
+	// rounds holds the heartbeat rounds not yet acknowledged by a quorum that
+	// could still extend the lease, in increasing order.
+	rounds []leaseRound
+	// acked holds the last heartbeat round acknowledged by each peer.
+	acked map[uint64]uint64
+	// expiry is the time until which the lease is held.
+	expiry time.Time
+	// revoked is set once a leadership transfer has started, and prevents the
+	// lease from being used or extended for the rest of the term.
+	revoked bool
+
+	// safeRead is the latest safe read index known to this peer, learned from
+	// its own heartbeat rounds as the leader or from those of a leader. It is
+	// retained across state changes.
+	safeRead SafeRead
+
+	// promisedTo is the leader this peer promised, as a follower, not to vote
+	// against until promisedUntil.
+	promisedTo    uint64
+	promisedUntil time.Time
+}
+
+func (l *leaderLease) enabled() bool { return l.duration > 0 }
+
+// numbered returns true if heartbeat rounds are numbered and tracked, which is
+// the case if leases or follower reads are enabled.
+func (l *leaderLease) numbered() bool { return l.enabled() || l.followerReads }
+
+// resetLeader drops the leader state of the lease.
+func (l *leaderLease) resetLeader() {
+	l.seq = 0
+	l.rounds = nil
+	l.acked = nil
+	l.expiry = time.Time{}
+	l.revoked = false
+}
+
+// nextRound starts a new heartbeat round at the given commit index and
+// returns its number.
+func (l *leaderLease) nextRound(committed uint64) uint64 {
+	now := l.clock.Now()
+	i := 0
+	if !l.followerReads {
+		// Rounds old enough not to extend the lease beyond now are useless.
+		for i < len(l.rounds) && !l.rounds[i].at.Add(l.duration-l.maxOffset).After(now) {
+			i++
+		}
+	}
+	i = max(i, len(l.rounds)+1-maxLeaseRounds)
+	l.rounds = l.rounds[i:]
+	l.seq++
+	l.rounds = append(l.rounds, leaseRound{seq: l.seq, at: now, committed: committed})
+	return l.seq
+}
+
+// promise records a heartbeat received from the given leader, promising not
+// to vote for any other candidate for the lease duration.
+func (l *leaderLease) promise(lead uint64) {
+	l.promisedTo = lead
+	l.promisedUntil = l.clock.Now().Add(l.duration)
+}
+
+// promisedAgainst returns true if this peer must not vote for the given
+// candidate, along with the time for which the promise remains in effect.
+func (l *leaderLease) promisedAgainst(candidate uint64) (bool, time.Duration) {
+	if !l.enabled() || candidate == l.promisedTo {
+		return false, 0
+	}
+	remaining := l.promisedUntil.Sub(l.clock.Now())
+	return remaining > 0, remaining
+}
+
+// sendLeaseRound starts a heartbeat round if leases or follower reads are
+// enabled, returning its number, or zero otherwise.
+func (r *raft) sendLeaseRound() uint64 {
+	if !r.lease.numbered() || (r.lease.revoked && !r.lease.followerReads) {
+		return 0
+	}
+	seq := r.lease.nextRound(r.raftLog.committed)
+	// The leader acknowledges its own rounds, which lets a single voter hold
+	// the lease.
+	r.maybeExtendLease(r.id, seq)
+	return seq
+}
+
+// maybeExtendLease records that the given peer acknowledged the heartbeat
+// round seq, and extends the lease and advances the safe read index if a
+// quorum now acknowledged a round that it didn't before.
+func (r *raft) maybeExtendLease(from, seq uint64) {
+	l := &r.lease
+	if !l.numbered() || seq == 0 || seq <= l.acked[from] {
+		return
+	}
+	if l.acked == nil {
+		l.acked = map[uint64]uint64{}
+	}
+	l.acked[from] = seq
+	for i := len(l.rounds) - 1; i >= 0; i-- {
+		round := l.rounds[i]
+		acks := map[uint64]bool{r.id: true}
+		for id, acked := range l.acked {
+			if acked >= round.seq {
+				acks[id] = true
+			}
+		}
+		if r.trk.AckResult(acks) != quorum.VoteWon {
+			continue
+		}
+		if expiry := round.at.Add(l.duration - l.maxOffset); l.enabled() && !l.revoked && expiry.After(l.expiry) {
+			l.expiry = expiry
+		}
+		if l.followerReads {
+			l.advanceSafeRead(SafeRead{Index: round.committed, Time: round.at})
+		}
+		l.rounds = l.rounds[i+1:]
+		return
+	}
+}
+
+// revokeLease gives up the lease for the rest of the term. It is called when
+// a leadership transfer starts, since the transferee campaigns regardless of
+// the promises made to this leader.
+func (r *raft) revokeLease() {
+	if r.lease.enabled() && !r.lease.revoked {
+		r.logger.Infof("%x [term %d] revokes its leader lease", r.id, r.Term)
+		r.lease.expiry = time.Time{}
+		r.lease.revoked = true
+	}
+}
+
+// leaseExpiry returns the time until which this peer holds the leader lease,
+// or the zero time if it doesn't hold it. The lease is only usable once the
+// leader committed an entry in its term, which makes its commit index the
+// one of the group.
+func (r *raft) leaseExpiry() time.Time {
+	if r.state != StateLeader || !r.lease.enabled() || r.lease.revoked || !r.committedEntryInCurrentTerm() {
+		return time.Time{}
+	}
+	return r.lease.expiry
+}
+
+// SafeRead is a safe read index: every entry committed before Time, on the
+// clock of the leader that established it, has an index of at most Index. A
+// peer that has applied Index can serve a read reflecting all writes that
+// completed before Time, i.e. with a staleness of at most the time since
+// then plus Config.MaxClockOffset.
+type SafeRead struct {
+	Index uint64
+	Time  time.Time
+}
+
+// advanceSafeRead replaces the safe read index with a more recent one.
+func (l *leaderLease) advanceSafeRead(sr SafeRead) {
+	if sr.Time.After(l.safeRead.Time) && sr.Index >= l.safeRead.Index {
+		l.safeRead = sr
+	}
+}
+
+// attachSafeRead piggybacks the safe read index onto the given heartbeat or
+// append message, if this peer is a leader with follower reads enabled.
+func (r *raft) attachSafeRead(m *pb.Message) {
+	if r.lease.followerReads && !r.lease.safeRead.Time.IsZero() {
+		m.SafeReadIndex = r.lease.safeRead.Index
+		m.SafeReadTime = r.lease.safeRead.Time.UnixNano()
+	}
+}
+
+// maybeAdvanceSafeRead records the safe read index piggybacked on a message
+// of the leader.
+func (r *raft) maybeAdvanceSafeRead(m pb.Message) {
+	if m.SafeReadTime != 0 {
+		r.lease.advanceSafeRead(SafeRead{Index: m.SafeReadIndex, Time: time.Unix(0, m.SafeReadTime)})
+	}
+}
+
+// safeReadIndex returns the index of the given safe read index if a read
+// served at it has a staleness of at most maxStaleness. It only uses the
+// clock and clock offset, which never change, and can thus be called from
+// any goroutine.
+func (l *leaderLease) safeReadIndex(sr SafeRead, maxStaleness time.Duration) (uint64, bool) {
+	if sr.Time.IsZero() || l.clock.Now().Sub(sr.Time)+l.maxOffset > maxStaleness {
+		return 0, false
+	}
+	return sr.Index, true
+}
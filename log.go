@@ -28,6 +28,13 @@ type raftLog struct {
 	// they will be saved into storage.
 	unstable unstable
 
+	// cache holds the most recent stable entries. It is nil, and caches
+	// nothing, if disabled (see Config.EntryCacheSize).
+	cache *entryCache
+	// trace follows the lifecycle of the entries appended by the leader. It
+	// is nil if disabled (see Config.ProposalTracer).
+	trace *proposalTrace
+
 	// committed is the highest log position that is known to be in
 	// stable storage on a quorum of nodes.
 	committed uint64
@@ -135,6 +142,8 @@ func (l *raftLog) append(ents ...pb.Entry) uint64 {
 	if after := ents[0].Index - 1; after < l.committed {
 		l.logger.Panicf("after(%d) is out of range [committed(%d)]", after, l.committed)
 	}
+	l.cache.truncateFrom(ents[0].Index)
+	l.trace.truncateFrom(ents[0].Index)
 	l.unstable.truncateAndAppend(ents)
 	return l.lastIndex()
 }
@@ -238,6 +247,9 @@ func (l *raftLog) nextCommittedEnts(allowUnstable bool) (ents []pb.Entry) {
 	if err != nil {
 		l.logger.Panicf("unexpected error when getting unapplied entries (%v)", err)
 	}
+	if e, ok := verifyEntries(ents); !ok {
+		l.logger.Panicf("committed entry %d/%d fails its checksum", e.Term, e.Index)
+	}
 	return ents
 }
 
@@ -324,6 +336,7 @@ func (l *raftLog) commitTo(tocommit uint64) {
 			l.logger.Panicf("tocommit(%d) is out of range [lastIndex(%d)]. Was the raft log corrupted, truncated, or lost?", tocommit, l.lastIndex())
 		}
 		l.committed = tocommit
+		l.trace.committed(tocommit)
 	}
 }
 
@@ -332,6 +345,7 @@ func (l *raftLog) appliedTo(i uint64, size entryEncodingSize) {
 		l.logger.Panicf("applied(%d) is out of range [prevApplied(%d), committed(%d)]", i, l.applied, l.committed)
 	}
 	l.applied = i
+	l.trace.applied(i)
 	l.applying = max(l.applying, i)
 	if l.applyingEntsSize > size {
 		l.applyingEntsSize -= size
@@ -362,7 +376,14 @@ func (l *raftLog) acceptApplying(i uint64, size entryEncodingSize, allowUnstable
 		i < l.maxAppliableIndex(allowUnstable)
 }
 
-func (l *raftLog) stableTo(id entryID) { l.unstable.stableTo(id) }
+func (l *raftLog) stableTo(id entryID) {
+	offset, ents := l.unstable.offset, l.unstable.entries
+	l.unstable.stableTo(id)
+	if l.unstable.offset > offset {
+		l.cache.add(ents[:l.unstable.offset-offset])
+		l.trace.persisted(ents[:l.unstable.offset-offset])
+	}
+}
 
 func (l *raftLog) stableSnapTo(i uint64) { l.unstable.stableSnapTo(i) }
 
@@ -399,6 +420,9 @@ func (l *raftLog) term(i uint64) (uint64, error) {
 	if i > l.lastIndex() {
 		return 0, ErrUnavailable
 	}
+	if t, ok := l.cache.term(i); ok {
+		return t, nil
+	}
 
 	t, err := l.storage.Term(i)
 	if err == nil {
@@ -465,6 +489,8 @@ func (l *raftLog) restore(s pb.Snapshot) {
 	l.logger.Infof("log [%s] starts to restore snapshot [index: %d, term: %d]", l, s.Metadata.Index, s.Metadata.Term)
 	l.committed = s.Metadata.Index
 	l.unstable.restore(s)
+	l.cache.clear()
+	l.trace.clear()
 }
 
 // scan visits all log entries in the [lo, hi) range, returning them via the
@@ -495,6 +521,14 @@ func (l *raftLog) scan(lo, hi uint64, pageSize entryEncodingSize, v func([]pb.En
 
 // slice returns a slice of log entries from lo through hi-1, inclusive.
 func (l *raftLog) slice(lo, hi uint64, maxSize entryEncodingSize) ([]pb.Entry, error) {
+	return l.sliceWith(lo, hi, maxSize, l.storage.Entries)
+}
+
+// sliceWith is like slice, but reads the stable entries with the given
+// function instead of Storage.Entries. Besides ErrCompacted, it passes
+// ErrEntriesPending through.
+func (l *raftLog) sliceWith(lo, hi uint64, maxSize entryEncodingSize,
+	read func(lo, hi, maxSize uint64) ([]pb.Entry, error)) ([]pb.Entry, error) {
 	if err := l.mustCheckOutOfBounds(lo, hi); err != nil {
 		return nil, err
 	}
@@ -509,8 +543,12 @@ func (l *raftLog) slice(lo, hi uint64, maxSize entryEncodingSize) ([]pb.Entry, e
 	}
 
 	cut := min(hi, l.unstable.offset)
-	ents, err := l.storage.Entries(lo, cut, uint64(maxSize))
-	if err == ErrCompacted {
+	ents, ok := l.cache.slice(lo, cut, maxSize)
+	var err error
+	if !ok {
+		ents, err = read(lo, cut, uint64(maxSize))
+	}
+	if err == ErrCompacted || err == ErrEntriesPending {
 		return nil, err
 	} else if err == ErrUnavailable {
 		l.logger.Panicf("entries[%d:%d) is unavailable from storage", lo, cut)
@@ -0,0 +1,254 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package raft
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+)
+
+// LevelFatal and LevelPanic are the slog levels of the lines logged by
+// Logger.Fatal and Logger.Panic and their formatting variants.
+const (
+	LevelFatal = slog.LevelError + 4
+	LevelPanic = slog.LevelError + 8
+)
+
+// StructuredLogger is a Logger which also accepts key/value attributes. When
+// Config.Logger implements it, raft attaches the ID, term and state of the
+// node and the ID of its group (see Config.GroupID) to every line it logs,
+// and some call sites add attributes describing the event, such as the peer
+// involved.
+type StructuredLogger interface {
+	Logger
+	// Enabled returns true if lines at the given level are logged. Raft
+	// neither formats nor builds the attributes of the other lines.
+	Enabled(level slog.Level) bool
+	// LogAttrs logs the message at the given level with the attributes, like
+	// slog.Logger.LogAttrs. It doesn't exit or panic for LevelFatal and
+	// LevelPanic, raft takes care of it.
+	LogAttrs(level slog.Level, msg string, attrs ...slog.Attr)
+}
+
+// logfAt formats and logs the line at the given level if enabled.
+func logfAt(l StructuredLogger, level slog.Level, format string, v []interface{}) {
+	if l.Enabled(level) {
+		l.LogAttrs(level, fmt.Sprintf(format, v...))
+	}
+}
+
+// logAt formats and logs the line at the given level if enabled.
+func logAt(l StructuredLogger, level slog.Level, v []interface{}) {
+	if l.Enabled(level) {
+		l.LogAttrs(level, fmt.Sprint(v...))
+	}
+}
+
+// logPanic logs the line at LevelPanic, and panics with it.
+func logPanic(l StructuredLogger, msg string) {
+	l.LogAttrs(LevelPanic, msg)
+	panic(msg)
+}
+
+// SlogLogger is a StructuredLogger writing to a slog.Logger.
+type SlogLogger struct {
+	logger *slog.Logger
+}
+
+var _ StructuredLogger = (*SlogLogger)(nil)
+
+// NewSlogLogger returns a SlogLogger writing to the given slog.Logger, or to
+// slog.Default() if nil.
+func NewSlogLogger(l *slog.Logger) *SlogLogger {
+	if l == nil {
+		l = slog.Default()
+	}
+	return &SlogLogger{logger: l}
+}
+
+// Enabled implements StructuredLogger.
+func (l *SlogLogger) Enabled(level slog.Level) bool {
+	return l.logger.Enabled(context.Background(), level)
+}
+
+// LogAttrs implements StructuredLogger.
+func (l *SlogLogger) LogAttrs(level slog.Level, msg string, attrs ...slog.Attr) {
+	l.logger.LogAttrs(context.Background(), level, msg, attrs...)
+}
+
+func (l *SlogLogger) Debug(v ...interface{}) { logAt(l, slog.LevelDebug, v) }
+
+func (l *SlogLogger) Debugf(format string, v ...interface{}) { logfAt(l, slog.LevelDebug, format, v) }
+
+func (l *SlogLogger) Info(v ...interface{}) { logAt(l, slog.LevelInfo, v) }
+
+func (l *SlogLogger) Infof(format string, v ...interface{}) { logfAt(l, slog.LevelInfo, format, v) }
+
+func (l *SlogLogger) Warning(v ...interface{}) { logAt(l, slog.LevelWarn, v) }
+
+func (l *SlogLogger) Warningf(format string, v ...interface{}) { logfAt(l, slog.LevelWarn, format, v) }
+
+func (l *SlogLogger) Error(v ...interface{}) { logAt(l, slog.LevelError, v) }
+
+func (l *SlogLogger) Errorf(format string, v ...interface{}) { logfAt(l, slog.LevelError, format, v) }
+
+func (l *SlogLogger) Fatal(v ...interface{}) {
+	logAt(l, LevelFatal, v)
+	os.Exit(1)
+}
+
+func (l *SlogLogger) Fatalf(format string, v ...interface{}) {
+	logfAt(l, LevelFatal, format, v)
+	os.Exit(1)
+}
+
+func (l *SlogLogger) Panic(v ...interface{}) { logPanic(l, fmt.Sprint(v...)) }
+
+func (l *SlogLogger) Panicf(format string, v ...interface{}) { logPanic(l, fmt.Sprintf(format, v...)) }
+
+// nodeLogger is the Logger used by a raft node whose Config.Logger is a
+// StructuredLogger. It attaches the attributes of the node, and the given
+// extra attributes, to every line.
+type nodeLogger struct {
+	StructuredLogger
+	r     *raft
+	group uint64
+	attrs []slog.Attr
+}
+
+// newNodeLogger returns the Logger to use for the node: a nodeLogger if l is
+// a StructuredLogger, and l otherwise. The node is set once created.
+func newNodeLogger(l Logger, group uint64) (Logger, *nodeLogger) {
+	sl, ok := l.(StructuredLogger)
+	if !ok {
+		return l, nil
+	}
+	nl := &nodeLogger{StructuredLogger: sl, group: group}
+	return nl, nl
+}
+
+// attrLogger is a Logger attaching the given peer, unless None, and
+// attributes to the lines of a node's Logger. It builds the nodeLogger which
+// carries them only for the lines which are logged. The attributes are held
+// by value, so that the lines which aren't logged don't allocate.
+type attrLogger struct {
+	l     Logger
+	peer  uint64
+	attrs [2]slog.Attr
+	n     int
+}
+
+// peerLogger returns a Logger attaching the given peer and up to two more
+// attributes to the lines of the node.
+func (r *raft) peerLogger(peer uint64, attrs ...slog.Attr) attrLogger {
+	l := attrLogger{l: r.logger, peer: peer}
+	l.n = copy(l.attrs[:], attrs)
+	return l
+}
+
+// at returns the Logger to log a line at the given level. Loggers which don't
+// support attributes, or don't log at the level, are returned as is.
+func (l attrLogger) at(level slog.Level) Logger {
+	nl, ok := l.l.(*nodeLogger)
+	if !ok || !nl.Enabled(level) {
+		return l.l
+	}
+	c := *nl
+	c.attrs = make([]slog.Attr, 0, len(nl.attrs)+1+l.n)
+	c.attrs = append(c.attrs, nl.attrs...)
+	if l.peer != None {
+		c.attrs = append(c.attrs, slog.Uint64("peer", l.peer))
+	}
+	c.attrs = append(c.attrs, l.attrs[:l.n]...)
+	return &c
+}
+
+func (l attrLogger) Debug(v ...interface{}) { l.at(slog.LevelDebug).Debug(v...) }
+
+func (l attrLogger) Debugf(format string, v ...interface{}) {
+	l.at(slog.LevelDebug).Debugf(format, v...)
+}
+
+func (l attrLogger) Info(v ...interface{}) { l.at(slog.LevelInfo).Info(v...) }
+
+func (l attrLogger) Infof(format string, v ...interface{}) { l.at(slog.LevelInfo).Infof(format, v...) }
+
+func (l attrLogger) Warning(v ...interface{}) { l.at(slog.LevelWarn).Warning(v...) }
+
+func (l attrLogger) Warningf(format string, v ...interface{}) {
+	l.at(slog.LevelWarn).Warningf(format, v...)
+}
+
+func (l attrLogger) Error(v ...interface{}) { l.at(slog.LevelError).Error(v...) }
+
+func (l attrLogger) Errorf(format string, v ...interface{}) {
+	l.at(slog.LevelError).Errorf(format, v...)
+}
+
+func (l attrLogger) Fatal(v ...interface{}) { l.at(LevelFatal).Fatal(v...) }
+
+func (l attrLogger) Fatalf(format string, v ...interface{}) { l.at(LevelFatal).Fatalf(format, v...) }
+
+func (l attrLogger) Panic(v ...interface{}) { l.at(LevelPanic).Panic(v...) }
+
+func (l attrLogger) Panicf(format string, v ...interface{}) { l.at(LevelPanic).Panicf(format, v...) }
+
+func (l *nodeLogger) LogAttrs(level slog.Level, msg string, attrs ...slog.Attr) {
+	all := make([]slog.Attr, 0, 4+len(l.attrs)+len(attrs))
+	if l.group != 0 {
+		all = append(all, slog.Uint64("group_id", l.group))
+	}
+	if r := l.r; r != nil {
+		all = append(all,
+			slog.Uint64("node_id", r.id),
+			slog.Uint64("term", r.Term),
+			slog.String("state", r.state.String()))
+	}
+	all = append(all, l.attrs...)
+	all = append(all, attrs...)
+	l.StructuredLogger.LogAttrs(level, msg, all...)
+}
+
+func (l *nodeLogger) Debug(v ...interface{}) { logAt(l, slog.LevelDebug, v) }
+
+func (l *nodeLogger) Debugf(format string, v ...interface{}) { logfAt(l, slog.LevelDebug, format, v) }
+
+func (l *nodeLogger) Info(v ...interface{}) { logAt(l, slog.LevelInfo, v) }
+
+func (l *nodeLogger) Infof(format string, v ...interface{}) { logfAt(l, slog.LevelInfo, format, v) }
+
+func (l *nodeLogger) Warning(v ...interface{}) { logAt(l, slog.LevelWarn, v) }
+
+func (l *nodeLogger) Warningf(format string, v ...interface{}) { logfAt(l, slog.LevelWarn, format, v) }
+
+func (l *nodeLogger) Error(v ...interface{}) { logAt(l, slog.LevelError, v) }
+
+func (l *nodeLogger) Errorf(format string, v ...interface{}) { logfAt(l, slog.LevelError, format, v) }
+
+func (l *nodeLogger) Fatal(v ...interface{}) {
+	logAt(l, LevelFatal, v)
+	os.Exit(1)
+}
+
+func (l *nodeLogger) Fatalf(format string, v ...interface{}) {
+	logfAt(l, LevelFatal, format, v)
+	os.Exit(1)
+}
+
+func (l *nodeLogger) Panic(v ...interface{}) { logPanic(l, fmt.Sprint(v...)) }
+
+func (l *nodeLogger) Panicf(format string, v ...interface{}) { logPanic(l, fmt.Sprintf(format, v...)) }
@@ -0,0 +1,121 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package raft
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	pb "go.etcd.io/raft/v3/raftpb"
+)
+
+// readLogLines decodes the lines written by a slog.JSONHandler.
+func readLogLines(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	var lines []map[string]interface{}
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var line map[string]interface{}
+		require.NoError(t, dec.Decode(&line))
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestSlogLogger(t *testing.T) {
+	var buf bytes.Buffer
+	handler := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	cfg := newTestConfig(1, 10, 1, newTestMemoryStorage(withPeers(1, 2, 3)))
+	cfg.Logger = NewSlogLogger(slog.New(handler))
+	cfg.GroupID = 7
+	r := newRaft(cfg)
+
+	require.NoError(t, r.Step(pb.Message{From: 1, To: 1, Type: pb.MsgHup}))
+	buf.Reset()
+	require.NoError(t, r.Step(pb.Message{From: 2, To: 1, Term: 1, Type: pb.MsgVoteResp}))
+	require.NoError(t, r.Step(pb.Message{From: 3, To: 1, Term: 1, Type: pb.MsgVoteResp}))
+
+	lines := readLogLines(t, &buf)
+	require.NotEmpty(t, lines)
+	for _, line := range lines {
+		require.EqualValues(t, 7, line["group_id"], line)
+		require.EqualValues(t, 1, line["node_id"], line)
+		require.EqualValues(t, 1, line["term"], line)
+	}
+	// The vote is logged with the voter and the state of the candidate.
+	require.Equal(t, "StateCandidate", lines[0]["state"])
+	require.EqualValues(t, 2, lines[0]["peer"])
+	require.Contains(t, lines[0]["msg"], "received MsgVoteResp from 2")
+	// The node is logged with its state at the time of each line.
+	require.Equal(t, "StateLeader", lines[len(lines)-1]["state"])
+}
+
+// countingStringer counts the times it is formatted.
+type countingStringer struct{ n *int }
+
+func (s countingStringer) String() string {
+	*s.n++
+	return "x"
+}
+
+// TestSlogLoggerLevel checks that the lines below the level of the handler
+// are neither formatted nor given attributes.
+func TestSlogLoggerLevel(t *testing.T) {
+	var buf bytes.Buffer
+	sl := NewSlogLogger(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo})))
+	l, nl := newNodeLogger(sl, 7)
+	nl.r = newTestRaft(1, 10, 1, newTestMemoryStorage(withPeers(1)))
+
+	var n int
+	nl.r.logger = l
+	pl := nl.r.peerLogger(2, slog.Uint64("index", 3))
+	for _, l := range []Logger{sl, l, pl} {
+		l.Debugf("%s", countingStringer{&n})
+		l.Debug(countingStringer{&n})
+	}
+	require.Zero(t, n)
+	require.Zero(t, buf.Len())
+	require.Same(t, l, pl.at(slog.LevelDebug))
+	require.Zero(t, testing.AllocsPerRun(10, func() {
+		nl.r.peerLogger(2, slog.Uint64("index", 3)).Debugf("debug")
+	}))
+
+	pl.Infof("%s", countingStringer{&n})
+	require.Equal(t, 1, n)
+	lines := readLogLines(t, &buf)
+	require.Len(t, lines, 1)
+	require.EqualValues(t, 2, lines[0]["peer"])
+	require.EqualValues(t, 3, lines[0]["index"])
+}
+
+func TestSlogLoggerPanic(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewSlogLogger(slog.New(slog.NewJSONHandler(&buf, nil)))
+	require.PanicsWithValue(t, "boom 1", func() { l.Panicf("boom %d", 1) })
+	lines := readLogLines(t, &buf)
+	require.Len(t, lines, 1)
+	require.Equal(t, LevelPanic.String(), lines[0]["level"])
+}
+
+func TestNodeLoggerUnstructured(t *testing.T) {
+	// Loggers without attributes are used as is.
+	l, nl := newNodeLogger(raftLogger, 1)
+	require.Nil(t, nl)
+	require.Equal(t, raftLogger, l)
+	require.Equal(t, raftLogger, attrLogger{l: l, peer: 2}.at(slog.LevelInfo))
+}
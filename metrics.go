@@ -0,0 +1,110 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package raft
+
+import (
+	"fmt"
+
+	pb "go.etcd.io/raft/v3/raftpb"
+)
+
+// ProposalDropReason is the reason a proposal was dropped with
+// ErrProposalDropped.
+type ProposalDropReason uint8
+
+const (
+	// ProposalDropNoLeader is when there is no known leader to handle the
+	// proposal.
+	ProposalDropNoLeader ProposalDropReason = iota
+	// ProposalDropForwardingDisabled is when a follower doesn't forward the
+	// proposal to the leader (see Config.DisableProposalForwarding).
+	ProposalDropForwardingDisabled
+	// ProposalDropNotMember is when the leader was removed from the
+	// configuration.
+	ProposalDropNotMember
+	// ProposalDropTransfer is when a leadership transfer is in progress.
+	ProposalDropTransfer
+	// ProposalDropUncommittedSize is when the proposal would exceed the limit
+	// on uncommitted entries (see Config.MaxUncommittedEntriesSize).
+	ProposalDropUncommittedSize
+)
+
+var proposalDropReasonNames = [...]string{
+	ProposalDropNoLeader:           "NoLeader",
+	ProposalDropForwardingDisabled: "ForwardingDisabled",
+	ProposalDropNotMember:          "NotMember",
+	ProposalDropTransfer:           "Transfer",
+	ProposalDropUncommittedSize:    "UncommittedSize",
+}
+
+func (r ProposalDropReason) String() string {
+	if int(r) < len(proposalDropReasonNames) {
+		return proposalDropReasonNames[r]
+	}
+	return fmt.Sprintf("ProposalDropReason(%d)", uint8(r))
+}
+
+// Metrics is notified of the events of a raft node, to be counted by a
+// metrics backend (see Config.Metrics). Its methods are called from within
+// the raft node, and must neither block nor call back into it.
+type Metrics interface {
+	// ElectionStarted is called when the node starts an election, or a
+	// pre-election if preVote is set.
+	ElectionStarted(preVote bool)
+	// ElectionWon is called when the node wins an election, becoming the
+	// leader, or a pre-election, starting the election.
+	ElectionWon(preVote bool)
+	// ElectionLost is called when the node loses an election or a
+	// pre-election, either because a quorum rejected it, another node won
+	// it, or it timed out.
+	ElectionLost(preVote bool)
+	// PreVoteRejected is called when the node rejects a pre-vote request.
+	PreVoteRejected()
+	// TermBumped is called when the term of the node increases.
+	TermBumped(term uint64)
+	// MessageSent is called for every message the node sends, including
+	// those to itself and to local targets.
+	MessageSent(t pb.MessageType)
+	// MessageReceived is called for every message the node receives from
+	// other peers. Local messages, such as proposals made on the node itself,
+	// ticks and storage acknowledgements, aren't counted.
+	MessageReceived(t pb.MessageType)
+	// ProposalDropped is called when a proposal is dropped.
+	ProposalDropped(reason ProposalDropReason)
+	// SnapshotSent is called when the leader sends a snapshot to a peer.
+	SnapshotSent(to uint64)
+	// FlowControlPaused is called when the leader stops sending appends to a
+	// peer until it acknowledges some, because it is probing the peer or its
+	// limit of in-flight messages is reached. It isn't called again until the
+	// appends are resumed.
+	FlowControlPaused(to uint64)
+}
+
+// NoopMetrics is a Metrics ignoring all events. It is used if Config.Metrics
+// is not set.
+type NoopMetrics struct{}
+
+var _ Metrics = NoopMetrics{}
+
+func (NoopMetrics) ElectionStarted(bool)               {}
+func (NoopMetrics) ElectionWon(bool)                   {}
+func (NoopMetrics) ElectionLost(bool)                  {}
+func (NoopMetrics) PreVoteRejected()                   {}
+func (NoopMetrics) TermBumped(uint64)                  {}
+func (NoopMetrics) MessageSent(pb.MessageType)         {}
+func (NoopMetrics) MessageReceived(pb.MessageType)     {}
+func (NoopMetrics) ProposalDropped(ProposalDropReason) {}
+func (NoopMetrics) SnapshotSent(uint64)                {}
+func (NoopMetrics) FlowControlPaused(uint64)           {}
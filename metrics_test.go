@@ -0,0 +1,128 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package raft
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	pb "go.etcd.io/raft/v3/raftpb"
+)
+
+// countingMetrics is a Metrics counting the events by name.
+type countingMetrics map[string]int
+
+func (c countingMetrics) ElectionStarted(pre bool) { c[fmt.Sprintf("ElectionStarted(pre=%t)", pre)]++ }
+func (c countingMetrics) ElectionWon(pre bool)     { c[fmt.Sprintf("ElectionWon(pre=%t)", pre)]++ }
+func (c countingMetrics) ElectionLost(pre bool)    { c[fmt.Sprintf("ElectionLost(pre=%t)", pre)]++ }
+func (c countingMetrics) PreVoteRejected()         { c["PreVoteRejected"]++ }
+func (c countingMetrics) TermBumped(uint64)        { c["TermBumped"]++ }
+func (c countingMetrics) MessageSent(t pb.MessageType) {
+	c["MessageSent("+t.String()+")"]++
+}
+func (c countingMetrics) MessageReceived(t pb.MessageType) {
+	c["MessageReceived("+t.String()+")"]++
+}
+func (c countingMetrics) ProposalDropped(r ProposalDropReason) {
+	c["ProposalDropped("+r.String()+")"]++
+}
+func (c countingMetrics) SnapshotSent(uint64)      { c["SnapshotSent"]++ }
+func (c countingMetrics) FlowControlPaused(uint64) { c["FlowControlPaused"]++ }
+
+// take returns the counts of the given events, and forgets all counts.
+func (c countingMetrics) take(events ...string) map[string]int {
+	res := make(map[string]int, len(events))
+	for _, e := range events {
+		if n := c[e]; n != 0 {
+			res[e] = n
+		}
+	}
+	clear(c)
+	return res
+}
+
+func TestMetricsElections(t *testing.T) {
+	metrics := map[uint64]countingMetrics{}
+	nt := newNetworkWithConfig(func(c *Config) {
+		preVoteConfig(c)
+		metrics[c.ID] = countingMetrics{}
+		c.Metrics = metrics[c.ID]
+	}, nil, nil, nil)
+	elections := []string{
+		"ElectionStarted(pre=true)", "ElectionWon(pre=true)", "ElectionLost(pre=true)",
+		"ElectionStarted(pre=false)", "ElectionWon(pre=false)", "ElectionLost(pre=false)",
+		"PreVoteRejected", "TermBumped",
+	}
+
+	nt.send(pb.Message{From: 1, To: 1, Type: pb.MsgHup})
+	require.Equal(t, map[string]int{
+		"ElectionStarted(pre=true)":  1,
+		"ElectionWon(pre=true)":      1,
+		"ElectionStarted(pre=false)": 1,
+		"ElectionWon(pre=false)":     1,
+		"TermBumped":                 1,
+	}, metrics[1].take(elections...))
+	require.Equal(t, map[string]int{"TermBumped": 1}, metrics[2].take(elections...))
+
+	// Node 3 falls behind, and the others reject its pre-vote.
+	nt.isolate(3)
+	nt.send(pb.Message{From: 1, To: 1, Type: pb.MsgProp, Entries: []pb.Entry{{Data: []byte("foo")}}})
+	nt.recover()
+	for _, m := range metrics {
+		clear(m)
+	}
+	nt.send(pb.Message{From: 3, To: 3, Type: pb.MsgHup})
+	require.Equal(t, map[string]int{
+		"ElectionStarted(pre=true)": 1,
+		"ElectionLost(pre=true)":    1,
+	}, metrics[3].take(elections...))
+	require.Equal(t, map[string]int{"PreVoteRejected": 1}, metrics[1].take(elections...))
+	require.Equal(t, map[string]int{"PreVoteRejected": 1}, metrics[2].take(elections...))
+}
+
+func TestMetricsMessages(t *testing.T) {
+	m := countingMetrics{}
+	cfg := newTestConfig(1, 10, 1, newTestMemoryStorage(withPeers(1, 2)))
+	cfg.Metrics = m
+	r := newRaft(cfg)
+	r.becomeCandidate()
+	r.becomeLeader()
+	clear(m)
+
+	// The follower is probed, which pauses the appends to it. The local
+	// proposal isn't a received message.
+	prop := pb.Message{From: 1, To: 1, Type: pb.MsgProp, Entries: []pb.Entry{{Data: []byte("foo")}}}
+	events := []string{"MessageReceived(MsgProp)", "MessageReceived(MsgAppResp)",
+		"MessageSent(MsgApp)", "MessageSent(MsgAppResp)", "FlowControlPaused"}
+	require.NoError(t, r.Step(prop))
+	require.Equal(t, map[string]int{
+		"MessageSent(MsgApp)":     1,
+		"MessageSent(MsgAppResp)": 1,
+		"FlowControlPaused":       1,
+	}, m.take(events...))
+
+	// The appends stay paused, which isn't reported again.
+	require.NoError(t, r.Step(prop))
+	require.Equal(t, map[string]int{"MessageSent(MsgAppResp)": 1}, m.take(events...))
+
+	// The follower's acknowledgement is received, and resumes the appends.
+	require.NoError(t, r.Step(pb.Message{From: 2, To: 1, Type: pb.MsgAppResp, Term: r.Term, Index: 2}))
+	require.Equal(t, map[string]int{
+		"MessageReceived(MsgAppResp)": 1,
+		"MessageSent(MsgApp)":         1,
+	}, m.take(events...))
+}
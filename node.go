@@ -17,6 +17,7 @@ package raft
 import (
 	"context"
 	"errors"
+	"time"
 
 	pb "go.etcd.io/raft/v3/raftpb"
 )
@@ -215,6 +216,13 @@ type Node interface {
 	// leader to be elected without the old leader knowing.
 	ForgetLeader(ctx context.Context) error
 
+	// SafeReadIndex returns the safe read index known to the node if reads
+	// served at it have a staleness of at most maxStaleness (see
+	// Config.FollowerReads). Once the application has applied the returned
+	// index, it can serve a read reflecting all writes that completed more
+	// than maxStaleness ago, without contacting the leader.
+	SafeReadIndex(maxStaleness time.Duration) (uint64, bool)
+
 	// ReadIndex request a read state. The read state will be set in the ready.
 	// Read state has a read index. Once the application advances further than the read
 	// index, any linearizable read requests issued before the read request can be
@@ -227,6 +235,16 @@ type Node interface {
 	Status() Status
 	// ReportUnreachable reports the given node is not reachable for the last send.
 	ReportUnreachable(id uint64)
+	// ReportHash reports the hash of the state machine after applying the
+	// hash check marker at the given index (see Config.HashCheckInterval).
+	ReportHash(index, hash uint64)
+	// ReportEntries reports the entries read from lo in the background after
+	// AsyncStorage.FetchEntries returned ErrEntriesPending, or the error the
+	// read failed with (see Config.AsyncStorageReads).
+	ReportEntries(lo uint64, ents []pb.Entry, err error)
+	// Wake wakes up the node if it is quiesced, making it tick again (see
+	// Config.Quiesce).
+	Wake()
 	// ReportSnapshot reports the status of the sent snapshot. The id is the raft ID of the follower
 	// who is meant to receive the snapshot, and the status is SnapshotFinish or SnapshotFailure.
 	// Calling ReportSnapshot with SnapshotFinish is a no-op. But, any failure in applying a
@@ -291,6 +309,9 @@ func RestartNode(c *Config) Node {
 type msgWithResult struct {
 	m      pb.Message
 	result chan error
+	// queuedAt is the time the proposal was handed to the node, if its
+	// lifecycle is traced (see Config.ProposalTracer).
+	queuedAt time.Time
 }
 
 // node is the canonical implementation of the Node interface
@@ -306,6 +327,10 @@ type node struct {
 	stop       chan struct{}
 	status     chan chan Status
 
+	// traceProposals is set if the lifecycle of proposals is traced (see
+	// Config.ProposalTracer), starting when they are handed to the node.
+	traceProposals bool
+
 	rn *RawNode
 }
 
@@ -324,7 +349,10 @@ func newNode(rn *RawNode) node {
 		done:   make(chan struct{}),
 		stop:   make(chan struct{}),
 		status: make(chan chan Status),
-		rn:     rn,
+
+		traceProposals: rn.raft.raftLog.trace != nil,
+
+		rn: rn,
 	}
 }
 
@@ -386,7 +414,9 @@ func (n *node) run() {
 		case pm := <-propc:
 			m := pm.m
 			m.From = r.id
+			r.raftLog.trace.queued(pm.queuedAt)
 			err := r.Step(m)
+			r.raftLog.trace.queued(time.Time{})
 			if pm.result != nil {
 				pm.result <- err
 				close(pm.result)
@@ -476,6 +506,12 @@ func (n *node) Step(ctx context.Context, m pb.Message) error {
 		// TODO: return an error?
 		return nil
 	}
+	if isSnapChunkMsg(m.Type) {
+		return ErrStepSnapChunk
+	}
+	if hasCompressedEntries(m) {
+		return ErrStepCompressed
+	}
 	return n.step(ctx, m)
 }
 
@@ -518,6 +554,9 @@ func (n *node) stepWithWaitOption(ctx context.Context, m pb.Message, wait bool)
 	}
 	ch := n.propc
 	pm := msgWithResult{m: m}
+	if n.traceProposals {
+		pm.queuedAt = time.Now()
+	}
 	if wait {
 		pm.result = make(chan error, 1)
 	}
@@ -583,6 +622,27 @@ func (n *node) ReportUnreachable(id uint64) {
 	}
 }
 
+func (n *node) ReportHash(index, hash uint64) {
+	select {
+	case n.recvc <- stateHashMsg(index, hash):
+	case <-n.done:
+	}
+}
+
+func (n *node) ReportEntries(lo uint64, ents []pb.Entry, err error) {
+	select {
+	case n.recvc <- storageEntriesMsg(lo, ents, err):
+	case <-n.done:
+	}
+}
+
+func (n *node) Wake() {
+	select {
+	case n.recvc <- pb.Message{Type: pb.MsgWake}:
+	case <-n.done:
+	}
+}
+
 func (n *node) ReportSnapshot(id uint64, status SnapshotStatus) {
 	rej := status == SnapshotFailure
 
@@ -605,6 +665,10 @@ func (n *node) ForgetLeader(ctx context.Context) error {
 	return n.step(ctx, pb.Message{Type: pb.MsgForgetLeader})
 }
 
+func (n *node) SafeReadIndex(maxStaleness time.Duration) (uint64, bool) {
+	return n.rn.raft.lease.safeReadIndex(n.Status().SafeRead, maxStaleness)
+}
+
 func (n *node) ReadIndex(ctx context.Context, rctx []byte) error {
 	return n.step(ctx, pb.Message{Type: pb.MsgReadIndex, Entries: []pb.Entry{{Data: rctx}}})
 }
@@ -56,9 +56,19 @@ func TestNodeStep(t *testing.T) {
 			recvc: make(chan raftpb.Message, 1),
 		}
 		msgt := raftpb.MessageType(i)
-		n.Step(t.Context(), raftpb.Message{Type: msgt})
-		// Proposal goes to proc chan. Others go to recvc chan.
-		if msgt == raftpb.MsgProp {
+		err := n.Step(t.Context(), raftpb.Message{Type: msgt})
+		// Proposal goes to proc chan. Snapshot chunks are refused. Others go
+		// to recvc chan.
+		if isSnapChunkMsg(msgt) {
+			if err != ErrStepSnapChunk {
+				t.Errorf("%d: step should refuse %s", msgt, msgn)
+			}
+			select {
+			case <-n.recvc:
+				t.Errorf("%d: step should ignore %s", msgt, msgn)
+			default:
+			}
+		} else if msgt == raftpb.MsgProp {
 			select {
 			case <-n.propc:
 			default:
@@ -0,0 +1,362 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package raft
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	pb "go.etcd.io/raft/v3/raftpb"
+)
+
+// ProposalStage is a step in the lifecycle of a proposal on the leader.
+type ProposalStage uint8
+
+const (
+	// ProposalQueued is when the proposal was handed to Node.Propose, before
+	// it waits for the node to pick it up.
+	ProposalQueued ProposalStage = iota
+	// ProposalAppended is when the entry was appended to the unstable log of
+	// the leader.
+	ProposalAppended
+	// ProposalPersisted is when the entry was reported durably written to the
+	// storage of the leader.
+	ProposalPersisted
+	// ProposalCommitted is when the entry was known to be committed, i.e.
+	// replicated to a quorum.
+	ProposalCommitted
+	// ProposalApplied is when the entry was reported applied.
+	ProposalApplied
+
+	numProposalStages
+)
+
+var proposalStageNames = [...]string{
+	ProposalQueued:    "Queued",
+	ProposalAppended:  "Appended",
+	ProposalPersisted: "Persisted",
+	ProposalCommitted: "Committed",
+	ProposalApplied:   "Applied",
+}
+
+func (s ProposalStage) String() string {
+	if s < numProposalStages {
+		return proposalStageNames[s]
+	}
+	return fmt.Sprintf("ProposalStage(%d)", uint8(s))
+}
+
+// ProposalEvent marks an entry reaching a stage of its lifecycle. It is a
+// span event keyed by the index of the entry: all the events of an entry
+// carry the same NodeID, Term and Index.
+type ProposalEvent struct {
+	Stage  ProposalStage
+	NodeID uint64
+	Term   uint64
+	Index  uint64
+	Time   time.Time
+}
+
+// ProposalTracer receives the lifecycle events of the entries appended by a
+// leader (see Config.ProposalTracer). Events are delivered in order for a
+// given entry, except that ProposalPersisted and ProposalCommitted may come
+// in either order. An entry which is overwritten, or lost in a snapshot, gets
+// no further event. TraceProposal is called from within the raft node, and
+// must not call back into it.
+type ProposalTracer interface {
+	TraceProposal(ProposalEvent)
+}
+
+// tracedEntry is an entry appended by the leader whose lifecycle is traced,
+// with the set of stages it has reached.
+type tracedEntry struct {
+	id     entryID
+	stages uint8
+}
+
+// proposalTrace follows the entries appended by the node as a leader until
+// they are applied, emitting their events to a ProposalTracer. A nil
+// proposalTrace traces nothing.
+type proposalTrace struct {
+	tracer ProposalTracer
+	id     uint64
+	now    func() time.Time
+
+	// queuedAt is the time the proposal being stepped was handed to
+	// Node.Propose, if known.
+	queuedAt time.Time
+	// entries holds the traced entries not applied yet, in index order. The
+	// entries overwritten in the log are dropped (see truncateFrom).
+	entries []tracedEntry
+}
+
+func newProposalTrace(t ProposalTracer, id uint64) *proposalTrace {
+	if t == nil {
+		return nil
+	}
+	return &proposalTrace{tracer: t, id: id, now: time.Now}
+}
+
+// queued sets the time the proposal about to be stepped was handed to
+// Node.Propose, or clears it if zero.
+func (p *proposalTrace) queued(at time.Time) {
+	if p == nil {
+		return
+	}
+	p.queuedAt = at
+}
+
+func (p *proposalTrace) emit(stage ProposalStage, id entryID, at time.Time) {
+	p.tracer.TraceProposal(ProposalEvent{Stage: stage, NodeID: p.id, Term: id.term, Index: id.index, Time: at})
+}
+
+// appended traces the entries the leader just appended to its log.
+func (p *proposalTrace) appended(es []pb.Entry) {
+	if p == nil || len(es) == 0 {
+		return
+	}
+	now := p.now()
+	for i := range es {
+		id := pbEntryID(&es[i])
+		if !p.queuedAt.IsZero() {
+			p.emit(ProposalQueued, id, p.queuedAt)
+		}
+		p.emit(ProposalAppended, id, now)
+		p.entries = append(p.entries, tracedEntry{id: id, stages: 1<<ProposalQueued | 1<<ProposalAppended})
+	}
+}
+
+// persisted traces the given entries, which just became stable.
+func (p *proposalTrace) persisted(es []pb.Entry) {
+	if p == nil || len(es) == 0 {
+		return
+	}
+	first, last := es[0].Index, es[len(es)-1].Index
+	p.advance(ProposalPersisted, last, func(id entryID) bool {
+		return id.index >= first && es[id.index-first].Term == id.term
+	})
+}
+
+// committed traces the entries up to the committed index.
+func (p *proposalTrace) committed(committed uint64) {
+	if p == nil || len(p.entries) == 0 || p.entries[0].id.index > committed {
+		return
+	}
+	p.advance(ProposalCommitted, committed, func(entryID) bool { return true })
+}
+
+// applied traces the entries up to the applied index, and stops tracing
+// them.
+func (p *proposalTrace) applied(applied uint64) {
+	if p == nil || len(p.entries) == 0 || p.entries[0].id.index > applied {
+		return
+	}
+	p.advance(ProposalApplied, applied, func(entryID) bool { return true })
+	i := sort.Search(len(p.entries), func(i int) bool { return p.entries[i].id.index > applied })
+	p.entries = p.entries[i:]
+}
+
+// advance emits the event of the given stage for the traced entries up to
+// index i which haven't reached it yet, and match.
+func (p *proposalTrace) advance(stage ProposalStage, i uint64, match func(entryID) bool) {
+	var now time.Time
+	for j := range p.entries {
+		e := &p.entries[j]
+		if e.id.index > i {
+			break
+		}
+		if e.stages&(1<<stage) != 0 || !match(e.id) {
+			continue
+		}
+		if now.IsZero() {
+			now = p.now()
+		}
+		e.stages |= 1 << stage
+		p.emit(stage, e.id, now)
+	}
+}
+
+// truncateFrom stops tracing the entries at and after index i.
+func (p *proposalTrace) truncateFrom(i uint64) {
+	if p == nil {
+		return
+	}
+	j := sort.Search(len(p.entries), func(j int) bool { return p.entries[j].id.index >= i })
+	p.entries = p.entries[:j]
+}
+
+// clear stops tracing all entries.
+func (p *proposalTrace) clear() {
+	if p == nil {
+		return
+	}
+	p.entries = nil
+}
+
+// DefaultProposalLatencyBuckets are the upper bounds of the histogram buckets
+// used by NewProposalLatencyCollector by default.
+var DefaultProposalLatencyBuckets = []time.Duration{
+	100 * time.Microsecond, 250 * time.Microsecond, 500 * time.Microsecond,
+	time.Millisecond, 2500 * time.Microsecond, 5 * time.Millisecond,
+	10 * time.Millisecond, 25 * time.Millisecond, 50 * time.Millisecond,
+	100 * time.Millisecond, 250 * time.Millisecond, 500 * time.Millisecond,
+	time.Second, 2500 * time.Millisecond, 5 * time.Second, 10 * time.Second,
+}
+
+// LatencyHistogram counts latencies in buckets.
+type LatencyHistogram struct {
+	// Buckets holds the upper bounds of the buckets, in increasing order.
+	Buckets []time.Duration
+	// Counts holds the number of latencies in each bucket, i.e. above the
+	// bound of the previous bucket and at most the bound of the bucket. Its
+	// last element counts the latencies above the last bound.
+	Counts []uint64
+	// Count and Sum are the number and sum of all the latencies.
+	Count uint64
+	Sum   time.Duration
+}
+
+func (h *LatencyHistogram) observe(d time.Duration) {
+	i := sort.Search(len(h.Buckets), func(i int) bool { return d <= h.Buckets[i] })
+	h.Counts[i]++
+	h.Count++
+	h.Sum += d
+}
+
+// Mean returns the mean latency, or zero if there is none.
+func (h LatencyHistogram) Mean() time.Duration {
+	if h.Count == 0 {
+		return 0
+	}
+	return h.Sum / time.Duration(h.Count)
+}
+
+// ProposalLatencyCollector is an in-memory ProposalTracer computing a latency
+// histogram per stage. The latency of ProposalAppended is the time spent
+// waiting for the node to pick the proposal up. The entry is then persisted
+// and committed in parallel, in either order, so the latencies of
+// ProposalPersisted and ProposalCommitted are both measured from
+// ProposalAppended, and the latency of ProposalApplied from the later of the
+// two. It can be shared by nodes, and read concurrently.
+//
+// The collector forgets the entries of a node once one at the same or a later
+// index is applied or appended in a later term, so that those which never get
+// applied, e.g. as they were overwritten, don't accumulate.
+type ProposalLatencyCollector struct {
+	mu         sync.Mutex
+	histograms [numProposalStages]LatencyHistogram
+	// entries holds the entries in flight of each node, in index order.
+	entries map[uint64][]collectedEntry
+}
+
+// collectedEntry holds the times at which an entry reached the stages of its
+// lifecycle, or zero.
+type collectedEntry struct {
+	term, index uint64
+	times       [numProposalStages]time.Time
+}
+
+var _ ProposalTracer = (*ProposalLatencyCollector)(nil)
+
+// NewProposalLatencyCollector returns a collector with histograms of the
+// given bucket bounds, or DefaultProposalLatencyBuckets if nil.
+func NewProposalLatencyCollector(buckets []time.Duration) *ProposalLatencyCollector {
+	if buckets == nil {
+		buckets = DefaultProposalLatencyBuckets
+	}
+	c := &ProposalLatencyCollector{entries: map[uint64][]collectedEntry{}}
+	for i := range c.histograms {
+		c.histograms[i] = LatencyHistogram{
+			Buckets: append([]time.Duration(nil), buckets...),
+			Counts:  make([]uint64, len(buckets)+1),
+		}
+	}
+	return c
+}
+
+// TraceProposal implements ProposalTracer.
+func (c *ProposalLatencyCollector) TraceProposal(ev ProposalEvent) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	es := c.entries[ev.NodeID]
+	i := sort.Search(len(es), func(i int) bool { return es[i].index >= ev.Index })
+	found := i < len(es) && es[i].index == ev.Index
+	if found && es[i].term > ev.Term {
+		// An event of an entry overwritten since.
+		return
+	}
+	switch ev.Stage {
+	case ProposalQueued, ProposalAppended:
+		if !found || es[i].term != ev.Term || ev.Stage == ProposalQueued {
+			// The entry overwrites those at and after its index, as in the
+			// log of the node.
+			es = append(es[:i], collectedEntry{term: ev.Term, index: ev.Index})
+		}
+		c.observe(&es[i], ev)
+	default:
+		if found && es[i].term == ev.Term {
+			c.observe(&es[i], ev)
+		}
+	}
+	if ev.Stage == ProposalApplied {
+		// Forget the entry, and those before it, which won't be applied.
+		es = es[sort.Search(len(es), func(i int) bool { return es[i].index > ev.Index }):]
+	}
+	if len(es) == 0 {
+		delete(c.entries, ev.NodeID)
+	} else {
+		c.entries[ev.NodeID] = es
+	}
+}
+
+// observe records the time the entry reached the stage of the event, and the
+// latency of the stage.
+func (c *ProposalLatencyCollector) observe(e *collectedEntry, ev ProposalEvent) {
+	e.times[ev.Stage] = ev.Time
+	var from time.Time
+	switch ev.Stage {
+	case ProposalAppended:
+		from = e.times[ProposalQueued]
+	case ProposalPersisted, ProposalCommitted:
+		from = e.times[ProposalAppended]
+	case ProposalApplied:
+		from = e.times[ProposalAppended]
+		for _, t := range e.times[ProposalPersisted:ProposalApplied] {
+			if t.After(from) {
+				from = t
+			}
+		}
+	}
+	if !from.IsZero() {
+		c.histograms[ev.Stage].observe(max(ev.Time.Sub(from), 0))
+	}
+}
+
+// Histograms returns a copy of the latency histograms of the stages. There
+// is none for ProposalQueued, which starts the lifecycle.
+func (c *ProposalLatencyCollector) Histograms() map[ProposalStage]LatencyHistogram {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	hs := make(map[ProposalStage]LatencyHistogram, numProposalStages-1)
+	for s := ProposalAppended; s < numProposalStages; s++ {
+		h := c.histograms[s]
+		h.Buckets = append([]time.Duration(nil), h.Buckets...)
+		h.Counts = append([]uint64(nil), h.Counts...)
+		hs[s] = h
+	}
+	return hs
+}
@@ -0,0 +1,206 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package raft
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	pb "go.etcd.io/raft/v3/raftpb"
+)
+
+// proposalEvents is a ProposalTracer recording the events.
+type proposalEvents []ProposalEvent
+
+func (e *proposalEvents) TraceProposal(ev ProposalEvent) { *e = append(*e, ev) }
+
+// take returns the recorded events as stage@index strings, and forgets them.
+func (e *proposalEvents) take() []string {
+	var res []string
+	for _, ev := range *e {
+		res = append(res, fmt.Sprintf("%s@%d", ev.Stage, ev.Index))
+	}
+	*e = nil
+	return res
+}
+
+func TestProposalTrace(t *testing.T) {
+	var events proposalEvents
+	s := newTestMemoryStorage(withPeers(1, 2, 3))
+	cfg := newTestConfig(1, 10, 1, s)
+	cfg.ProposalTracer = &events
+	r := newRaft(cfg)
+	r.becomeCandidate()
+	r.becomeLeader()
+	require.Equal(t, []string{"Appended@1"}, events.take())
+	require.NoError(t, r.Step(pb.Message{From: 1, To: 1, Type: pb.MsgProp, Entries: []pb.Entry{{Data: []byte("foo")}}}))
+	require.Equal(t, []string{"Appended@2"}, events.take())
+
+	// The followers form a quorum before the leader persists the entries.
+	for _, id := range []uint64{2, 3} {
+		require.NoError(t, r.Step(pb.Message{From: id, To: 1, Term: r.Term, Type: pb.MsgAppResp, Index: 2}))
+	}
+	require.Equal(t, []string{"Committed@1", "Committed@2"}, events.take())
+	ents := r.raftLog.nextUnstableEnts()
+	require.NoError(t, s.Append(ents))
+	r.raftLog.stableTo(pbEntryID(&ents[len(ents)-1]))
+	require.Equal(t, []string{"Persisted@1", "Persisted@2"}, events.take())
+	r.appliedTo(1, 0 /* size */)
+	require.Equal(t, []string{"Applied@1"}, events.take())
+
+	// An entry overwritten by the next leader gets no more events.
+	require.NoError(t, r.Step(pb.Message{From: 1, To: 1, Type: pb.MsgProp, Entries: []pb.Entry{{Data: []byte("bar")}}}))
+	require.Equal(t, []string{"Appended@3"}, events.take())
+	r.becomeFollower(r.Term+1, 2)
+	_, ok := r.raftLog.maybeAppend(logSlice{
+		term:    r.Term,
+		prev:    entryID{term: r.Term - 1, index: 2},
+		entries: index(3).terms(r.Term),
+	}, 3)
+	require.True(t, ok)
+	r.appliedTo(3, 0 /* size */)
+	require.Equal(t, []string{"Applied@2"}, events.take())
+}
+
+func TestProposalTraceQueued(t *testing.T) {
+	var events proposalEvents
+	s := newTestMemoryStorage(withPeers(1))
+	rn := newTestRawNode(1, 10, 1, s)
+	rn.raft.raftLog.trace = newProposalTrace(&events, 1)
+	n := newNode(rn)
+	go n.run()
+	require.NoError(t, n.Campaign(t.Context()))
+	for {
+		rd := <-n.Ready()
+		require.NoError(t, s.Append(rd.Entries))
+		n.Advance()
+		if rd.SoftState != nil && rd.SoftState.Lead == 1 {
+			break
+		}
+	}
+	before := time.Now()
+	require.NoError(t, n.Propose(t.Context(), []byte("foo")))
+	n.Stop()
+
+	var queued, appended ProposalEvent
+	for _, ev := range events {
+		if ev.Index != 2 {
+			continue
+		}
+		switch ev.Stage {
+		case ProposalQueued:
+			queued = ev
+		case ProposalAppended:
+			appended = ev
+		}
+	}
+	require.Equal(t, uint64(2), queued.Index)
+	require.Equal(t, uint64(2), appended.Index)
+	require.False(t, queued.Time.Before(before))
+	require.False(t, appended.Time.Before(queued.Time))
+}
+
+func TestProposalLatencyCollector(t *testing.T) {
+	c := NewProposalLatencyCollector([]time.Duration{time.Millisecond, 10 * time.Millisecond})
+	t0 := time.Unix(0, 0)
+	at := func(d time.Duration) time.Time { return t0.Add(d) }
+	for _, ev := range []ProposalEvent{
+		{Stage: ProposalQueued, Term: 1, Index: 1, Time: at(0)},
+		{Stage: ProposalAppended, Term: 1, Index: 1, Time: at(2 * time.Millisecond)},
+		{Stage: ProposalCommitted, Term: 1, Index: 1, Time: at(3 * time.Millisecond)},
+		{Stage: ProposalPersisted, Term: 1, Index: 1, Time: at(23 * time.Millisecond)},
+		{Stage: ProposalApplied, Term: 1, Index: 1, Time: at(23 * time.Millisecond)},
+		// An entry overwritten at the same index doesn't count towards the
+		// latency of the next one.
+		{Stage: ProposalAppended, Term: 1, Index: 2, Time: at(0)},
+		{Stage: ProposalAppended, Term: 2, Index: 2, Time: at(time.Second)},
+		{Stage: ProposalCommitted, Term: 2, Index: 2, Time: at(time.Second + 5*time.Millisecond)},
+	} {
+		c.TraceProposal(ev)
+	}
+	hs := c.Histograms()
+	require.Len(t, hs, 4)
+	require.Equal(t, LatencyHistogram{
+		Buckets: []time.Duration{time.Millisecond, 10 * time.Millisecond},
+		Counts:  []uint64{0, 1, 0},
+		Count:   1,
+		Sum:     2 * time.Millisecond,
+	}, hs[ProposalAppended])
+	require.Equal(t, []uint64{1, 1, 0}, hs[ProposalCommitted].Counts)
+	require.Equal(t, 3*time.Millisecond, hs[ProposalCommitted].Mean())
+	require.Equal(t, []uint64{0, 0, 1}, hs[ProposalPersisted].Counts)
+	require.Equal(t, []uint64{1, 0, 0}, hs[ProposalApplied].Counts)
+	require.Zero(t, hs[ProposalApplied].Sum)
+}
+
+// TestProposalLatencyCollectorStages checks that the latencies of
+// ProposalPersisted and ProposalCommitted are measured from ProposalAppended
+// whatever their order, and that of ProposalApplied from the later of them.
+func TestProposalLatencyCollectorStages(t *testing.T) {
+	c := NewProposalLatencyCollector(nil)
+	t0 := time.Unix(0, 0)
+	at := func(d time.Duration) time.Time { return t0.Add(d) }
+	for _, ev := range []ProposalEvent{
+		{Stage: ProposalAppended, Term: 1, Index: 1, Time: at(0)},
+		{Stage: ProposalPersisted, Term: 1, Index: 1, Time: at(time.Millisecond)},
+		{Stage: ProposalCommitted, Term: 1, Index: 1, Time: at(3 * time.Millisecond)},
+		{Stage: ProposalApplied, Term: 1, Index: 1, Time: at(7 * time.Millisecond)},
+		{Stage: ProposalAppended, Term: 1, Index: 2, Time: at(0)},
+		{Stage: ProposalCommitted, Term: 1, Index: 2, Time: at(5 * time.Millisecond)},
+		{Stage: ProposalPersisted, Term: 1, Index: 2, Time: at(6 * time.Millisecond)},
+		{Stage: ProposalApplied, Term: 1, Index: 2, Time: at(8 * time.Millisecond)},
+	} {
+		c.TraceProposal(ev)
+	}
+	hs := c.Histograms()
+	require.Equal(t, 7*time.Millisecond, hs[ProposalPersisted].Sum)
+	require.Equal(t, 8*time.Millisecond, hs[ProposalCommitted].Sum)
+	require.Equal(t, 6*time.Millisecond, hs[ProposalApplied].Sum)
+}
+
+// TestProposalLatencyCollectorEviction checks that the collector forgets the
+// entries which are never applied.
+func TestProposalLatencyCollectorEviction(t *testing.T) {
+	c := NewProposalLatencyCollector(nil)
+	trace := func(stage ProposalStage, node, term, index uint64) {
+		c.TraceProposal(ProposalEvent{Stage: stage, NodeID: node, Term: term, Index: index, Time: time.Unix(0, 0)})
+	}
+	for i := uint64(1); i <= 10; i++ {
+		trace(ProposalQueued, 1, 1, i)
+		trace(ProposalAppended, 1, 1, i)
+		trace(ProposalAppended, 2, 1, i)
+	}
+	require.Len(t, c.entries[1], 10)
+
+	// The entries from index 6 are overwritten in term 2.
+	trace(ProposalAppended, 1, 2, 6)
+	require.Len(t, c.entries[1], 6)
+	// Stale events are ignored.
+	trace(ProposalCommitted, 1, 1, 7)
+	trace(ProposalAppended, 1, 1, 6)
+	require.Len(t, c.entries[1], 6)
+	require.Equal(t, uint64(2), c.entries[1][5].term)
+
+	// Applying an entry forgets the ones before it.
+	trace(ProposalApplied, 1, 2, 6)
+	require.NotContains(t, c.entries, uint64(1))
+	// Even if it isn't known.
+	trace(ProposalApplied, 2, 3, 8)
+	require.Len(t, c.entries[2], 2)
+	require.Equal(t, uint64(9), c.entries[2][0].index)
+}
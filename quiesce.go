@@ -0,0 +1,91 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package raft
+
+import (
+	pb "go.etcd.io/raft/v3/raftpb"
+	"go.etcd.io/raft/v3/tracker"
+)
+
+// canQuiesce returns true if the leader may stop heartbeating its group: all
+// the entries are committed, applied and durable, every peer has acknowledged
+// all of them, and no proposal, read or leadership transfer is in progress.
+func (r *raft) canQuiesce() bool {
+	if !r.quiesce || r.state != StateLeader || r.leadTransferee != None {
+		return false
+	}
+	l := r.raftLog
+	last := l.lastIndex()
+	if l.committed != last || l.applied != last ||
+		l.hasNextOrInProgressUnstableEnts() || l.hasNextOrInProgressSnapshot() {
+		return false
+	}
+	if len(r.readOnly.pendingReadIndex) > 0 || len(r.pendingReadIndexMessages) > 0 {
+		return false
+	}
+	idle := true
+	r.trk.Visit(func(id uint64, pr *tracker.Progress) {
+		if id == r.id {
+			return
+		}
+		if pr.State != tracker.StateReplicate || pr.Match != last || pr.Inflights.Count() > 0 {
+			idle = false
+		}
+	})
+	return idle
+}
+
+// quiesceGroup sends the last heartbeat to the followers, telling them to stop
+// expecting more, and stops ticking.
+func (r *raft) quiesceGroup() {
+	r.logger.Debugf("%x quiescing at term %d [index: %d]", r.id, r.Term, r.raftLog.lastIndex())
+	r.quiesced = true
+	r.bcastHeartbeat()
+}
+
+// wakesUp returns true if the message wakes up a quiesced node: all messages
+// from peers do, and the local ones which need the node to tick again.
+func wakesUp(m pb.Message) bool {
+	if m.Type == pb.MsgHeartbeatResp && m.Quiesce {
+		// The acknowledgement of the heartbeat that quiesced the group.
+		return false
+	}
+	return !IsLocalMsg(m.Type) || m.Type == pb.MsgHup || m.Type == pb.MsgWake
+}
+
+// wake resumes ticking on a quiesced node. A leader resumes heartbeating, which
+// wakes up the followers. A follower woken up by anything but the leader wakes
+// the leader up too, so that it doesn't time out, and campaign, while the
+// leader is still quiet.
+func (r *raft) wake(m pb.Message) {
+	r.logger.Debugf("%x waking up at term %d on %s from %x", r.id, r.Term, m.Type, m.From)
+	r.quiesced = false
+	switch r.state {
+	case StateLeader:
+		// The peers haven't been heard of for as long as the group was quiet.
+		// Give them a full election timeout to show they are active before
+		// the next quorum check.
+		r.electionElapsed = 0
+		r.trk.Visit(func(id uint64, pr *tracker.Progress) {
+			pr.RecentActive = true
+		})
+		r.heartbeatElapsed = 0
+		r.bcastHeartbeat()
+	case StateFollower:
+		if r.lead != None && m.From != r.lead {
+			r.send(pb.Message{To: r.lead, Type: pb.MsgHeartbeatResp})
+		}
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package raft
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	pb "go.etcd.io/raft/v3/raftpb"
+)
+
+func quiesceConfig(c *Config) {
+	c.PreVote = true
+	c.CheckQuorum = true
+	c.Quiesce = true
+}
+
+// newQuiescedNetwork returns a network of 3 nodes with quiescence enabled,
+// whose leader 1 has quiesced the group.
+func newQuiescedNetwork(t *testing.T) *network {
+	nt := newNetworkWithConfig(quiesceConfig, nil, nil, nil)
+	nt.send(pb.Message{From: 1, To: 1, Type: pb.MsgHup})
+	nt.stabilize()
+	tickAndSend(nt, 1, nt.raft(1).heartbeatTimeout)
+	for id := uint64(1); id <= 3; id++ {
+		require.True(t, nt.raft(id).quiesced, "node %d", id)
+	}
+	return nt
+}
+
+func (nw *network) raft(id uint64) *raft { return nw.peers[id].(*raft) }
+
+// stabilize persists and applies the entries of all nodes.
+func (nw *network) stabilize() {
+	for id := range nw.peers {
+		nextEnts(nw.raft(id), nw.storage[id])
+	}
+}
+
+// tickAndSend ticks the node n times, and delivers the messages it sends.
+func tickAndSend(nt *network, id uint64, n int) {
+	r := nt.raft(id)
+	for i := 0; i < n; i++ {
+		r.tick()
+		nt.send(r.readMessages()...)
+	}
+}
+
+func TestQuiesce(t *testing.T) {
+	nt := newQuiescedNetwork(t)
+
+	// Quiesced nodes don't tick.
+	for id := uint64(1); id <= 3; id++ {
+		tickAndSend(nt, id, 2*nt.raft(id).electionTimeout)
+	}
+	for id := uint64(1); id <= 3; id++ {
+		r := nt.raft(id)
+		require.True(t, r.quiesced, "node %d", id)
+		require.Equal(t, uint64(1), r.lead)
+		require.Equal(t, uint64(1), r.Term)
+	}
+	require.Equal(t, StateLeader, nt.raft(1).state)
+
+	// A proposal wakes the group up, which doesn't quiesce while the entry
+	// isn't applied everywhere.
+	nt.send(pb.Message{From: 1, To: 1, Type: pb.MsgProp, Entries: []pb.Entry{{Data: []byte("foo")}}})
+	for id := uint64(1); id <= 3; id++ {
+		require.False(t, nt.raft(id).quiesced, "node %d", id)
+	}
+	tickAndSend(nt, 1, nt.raft(1).heartbeatTimeout)
+	require.False(t, nt.raft(1).quiesced)
+	nt.stabilize()
+	tickAndSend(nt, 1, nt.raft(1).heartbeatTimeout)
+	require.True(t, nt.raft(1).quiesced)
+}
+
+func TestQuiesceFollowerBehind(t *testing.T) {
+	nt := newNetworkWithConfig(quiesceConfig, nil, nil, nil)
+	nt.send(pb.Message{From: 1, To: 1, Type: pb.MsgHup})
+	nt.isolate(3)
+	nt.send(pb.Message{From: 1, To: 1, Type: pb.MsgProp, Entries: []pb.Entry{{Data: []byte("foo")}}})
+	nt.stabilize()
+	tickAndSend(nt, 1, nt.raft(1).heartbeatTimeout)
+	require.False(t, nt.raft(1).quiesced)
+	require.False(t, nt.raft(2).quiesced)
+}
+
+// TestQuiesceLeaderFailure checks that the followers of a quiesced leader which
+// failed elect a new leader once woken up.
+func TestQuiesceLeaderFailure(t *testing.T) {
+	nt := newQuiescedNetwork(t)
+	nt.isolate(1)
+
+	// Node 2 wakes up, e.g. as it lost its connection to the leader, and wakes
+	// node 3 up when it campaigns.
+	nt.send(pb.Message{From: 2, To: 2, Type: pb.MsgWake})
+	require.False(t, nt.raft(2).quiesced)
+	require.True(t, nt.raft(3).quiesced)
+	setRandomizedElectionTimeout(nt.raft(2), nt.raft(2).electionTimeout)
+	tickAndSend(nt, 2, nt.raft(2).electionTimeout)
+	require.False(t, nt.raft(3).quiesced)
+	// Node 3 still believes in the leader, and doesn't grant its vote until it
+	// didn't hear from it for an election timeout.
+	require.Equal(t, StatePreCandidate, nt.raft(2).state)
+	for i := 0; i < 2*nt.raft(2).electionTimeout && nt.raft(2).state != StateLeader && nt.raft(3).state != StateLeader; i++ {
+		tickAndSend(nt, 3, 1)
+		tickAndSend(nt, 2, 1)
+	}
+	require.True(t, nt.raft(2).state == StateLeader || nt.raft(3).state == StateLeader)
+	require.Equal(t, uint64(2), nt.raft(2).Term)
+}
+
+// TestQuiesceFollowerWakesLeader checks that a follower woken up, which could
+// campaign, wakes the leader up, which keeps its leadership.
+func TestQuiesceFollowerWakesLeader(t *testing.T) {
+	nt := newQuiescedNetwork(t)
+	nt.send(pb.Message{From: 2, To: 2, Type: pb.MsgWake})
+	for id := uint64(1); id <= 3; id++ {
+		require.False(t, nt.raft(id).quiesced, "node %d", id)
+	}
+	for i := 0; i < 2*nt.raft(1).electionTimeout; i++ {
+		for id := uint64(1); id <= 3; id++ {
+			tickAndSend(nt, id, 1)
+		}
+	}
+	require.Equal(t, StateLeader, nt.raft(1).state)
+	require.Equal(t, uint64(1), nt.raft(2).Term)
+}
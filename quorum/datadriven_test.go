@@ -28,7 +28,18 @@ import (
 // CommittedIndex or "vote" to verify a VoteResult. The underlying configuration
 // and inputs are specified via the arguments 'cfg' and 'cfgj' (for the majority
 // config and, optionally, majority config joint to the first one) and 'idx'
-// (for CommittedIndex) and 'votes' (for VoteResult).
+// (for CommittedIndex) and 'votes' (for VoteResult). The optional arguments
+// 'weights' and 'weightsj' assign voting weights to the voters of 'cfg' and
+// 'cfgj' (in the same order), in which case the weighted variants are tested.
+// Similarly, 'election' and 'replication' set the thresholds of a
+// FlexibleQuorum. The "ack" command takes 'votes' like "vote", but checks them
+// against the replication threshold. The argument 'zones' assigns zones to the
+// voters (in the order of 'idx', with _ for no zone) and 'k' sets the number
+// of zones that committed indexes and won votes have to span; the "zoneloss"
+// command checks whether each half tolerates the loss of a zone. The voters
+// listed in 'witnesses' don't store the log, so that committed indexes and
+// acks have to include two of the other voters, and don't count towards the
+// zones of acks.
 //
 // Internally, the harness runs some additional checks on each test case for
 // which it is known that the result shouldn't change. For example,
@@ -57,6 +68,16 @@ func TestDataDriven(t *testing.T) {
 			// used are 1 (voted against) and 2 (voted for). This looks awkward,
 			// but is convenient because it allows sharing code between the two.
 			var votes []Index
+			// Voting weights for the voters in ids and idsj, respectively.
+			var weights, weightsj []uint64
+			// Thresholds of a flexible quorum.
+			var q FlexibleQuorum
+			// Zones of the voters in the order in which they appear in
+			// (ids,idsj), without repetition, and the number of zones to span.
+			var zoneNames []string
+			var k int
+			// Witnesses among the voters.
+			var witnesses map[uint64]struct{}
 
 			// Parse the args.
 			for _, arg := range d.CmdArgs {
@@ -85,6 +106,31 @@ func TestDataDriven(t *testing.T) {
 							require.NotZero(t, n, "cannot use 0 as idx")
 						}
 						idxs = append(idxs, Index(n))
+					case "weights", "weightsj":
+						var n uint64
+						arg.Scan(t, i, &n)
+						if arg.Key == "weights" {
+							weights = append(weights, n)
+						} else {
+							weightsj = append(weightsj, n)
+						}
+					case "election":
+						arg.Scan(t, i, &q.Election)
+					case "replication":
+						arg.Scan(t, i, &q.Replication)
+					case "zones":
+						var zone string
+						arg.Scan(t, i, &zone)
+						zoneNames = append(zoneNames, zone)
+					case "k":
+						arg.Scan(t, i, &k)
+					case "witnesses":
+						var n uint64
+						arg.Scan(t, i, &n)
+						if witnesses == nil {
+							witnesses = map[uint64]struct{}{}
+						}
+						witnesses[n] = struct{}{}
 					case "votes":
 						var s string
 						arg.Scan(t, i, &s)
@@ -114,6 +160,40 @@ func TestDataDriven(t *testing.T) {
 				cj[id] = struct{}{}
 			}
 
+			makeWeights := func(ids, weights []uint64) Weights {
+				if len(weights) == 0 {
+					return nil
+				}
+				require.Len(t, weights, len(ids), "weights must match the config")
+				w := Weights{}
+				for i, id := range ids {
+					w[id] = weights[i]
+				}
+				return w
+			}
+			w := JointWeights{makeWeights(ids, weights), makeWeights(idsj, weightsj)}
+			// The weighted and flexible variants are only used if requested,
+			// as they'd hide bugs in the majority code otherwise.
+			weighted := len(weights) > 0 || len(weightsj) > 0 || q != (FlexibleQuorum{}) || len(zoneNames) > 0 || witnesses != nil
+
+			var zones Zones
+			if len(zoneNames) > 0 {
+				zones = Zones{}
+				var p int
+				for _, id := range append(append([]uint64(nil), ids...), idsj...) {
+					if _, ok := zones[id]; ok || p >= len(zoneNames) {
+						continue
+					}
+					zones[id] = zoneNames[p]
+					p++
+				}
+				for id, zone := range zones {
+					if zone == "_" {
+						delete(zones, id)
+					}
+				}
+			}
+
 			// Helper that returns an AckedIndexer which has the specified indexes
 			// mapped to the right IDs.
 			makeLookuper := func(idxs []Index, ids, idsj []uint64) mapAckIndexer {
@@ -146,9 +226,9 @@ func TestDataDriven(t *testing.T) {
 				return l
 			}
 
-			{
+			if d.Cmd != "zoneloss" {
 				input := idxs
-				if d.Cmd == "vote" {
+				if d.Cmd == "vote" || d.Cmd == "ack" {
 					input = votes
 				}
 				if voters := JointConfig([2]MajorityConfig{c, cj}).IDs(); len(voters) != len(input) {
@@ -162,6 +242,21 @@ func TestDataDriven(t *testing.T) {
 			case "committed":
 				l := makeLookuper(idxs, ids, idsj)
 
+				if weighted {
+					cc := JointConfig([2]MajorityConfig{c, cj})
+					fmt.Fprint(&buf, cc.Describe(l))
+					committed := func(cc JointConfig, w JointWeights) Index {
+						return min(cc.FlexibleCommittedIndex(q, w, l), cc.ZoneCommittedIndex(zones, witnesses, k, l), cc.StoringCommittedIndex(witnesses, l))
+					}
+					idx := committed(cc, w)
+					// Interchanging the majorities shouldn't make a difference. If it does, print.
+					if aIdx := committed(JointConfig{cj, c}, JointWeights{w[1], w[0]}); aIdx != idx {
+						fmt.Fprintf(&buf, "%s <-- via symmetry\n", aIdx)
+					}
+					fmt.Fprintf(&buf, "%s\n", idx)
+					break
+				}
+
 				// Branch based on whether this is a majority or joint quorum
 				// test case.
 				if !joint {
@@ -218,14 +313,39 @@ func TestDataDriven(t *testing.T) {
 					}
 					fmt.Fprintf(&buf, "%s\n", idx)
 				}
-			case "vote":
+			case "vote", "ack":
 				ll := makeLookuper(votes, ids, idsj)
 				l := map[uint64]bool{}
 				for id, v := range ll {
 					l[id] = v != 1 // NB: 1 == false, 2 == true
 				}
 
-				if !joint {
+				if weighted || d.Cmd == "ack" {
+					result := JointConfig.FlexibleVoteResult
+					if d.Cmd == "ack" {
+						result = JointConfig.FlexibleAckResult
+					}
+					zoneResult := func(cc JointConfig) VoteResult {
+						return cc.ZoneVoteResult(zones, k, l)
+					}
+					if d.Cmd == "ack" {
+						zoneResult = func(cc JointConfig) VoteResult {
+							return cc.ZoneAckResult(zones, witnesses, k, l)
+						}
+					}
+					zoned := func(cc JointConfig, w JointWeights) VoteResult {
+						r := CombineVoteResults(result(cc, q, w, l), zoneResult(cc))
+						if d.Cmd == "ack" {
+							r = CombineVoteResults(r, cc.StoringAckResult(witnesses, l))
+						}
+						return r
+					}
+					r := zoned(JointConfig{c, cj}, w)
+					if ar := zoned(JointConfig{cj, c}, JointWeights{w[1], w[0]}); ar != r {
+						fmt.Fprintf(&buf, "%v <-- via symmetry\n", ar)
+					}
+					fmt.Fprintf(&buf, "%v\n", r)
+				} else if !joint {
 					// Test a majority quorum.
 					r := c.VoteResult(l)
 					fmt.Fprintf(&buf, "%v\n", r)
@@ -238,6 +358,17 @@ func TestDataDriven(t *testing.T) {
 					}
 					fmt.Fprintf(&buf, "%v\n", r)
 				}
+			case "zoneloss":
+				for i, cfg := range []MajorityConfig{c, cj} {
+					if i == 1 && !joint {
+						break
+					}
+					if err := cfg.CheckZoneLoss(q, w[i], zones, witnesses, k); err != nil {
+						fmt.Fprintf(&buf, "%s\n", err)
+					} else {
+						fmt.Fprintf(&buf, "%s tolerates a zone loss\n", cfg)
+					}
+				}
 			default:
 				t.Fatalf("unknown command: %s", d.Cmd)
 			}
@@ -0,0 +1,137 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quorum
+
+import "fmt"
+
+// FlexibleQuorum configures asymmetric quorums in the style of Flexible Paxos:
+// winning an election requires votes carrying a total weight of at least
+// Election, while committing an entry requires acknowledgements carrying at
+// least Replication. Without Weights, the weight of a set of voters is its
+// size. The zero FlexibleQuorum uses majorities for both.
+//
+// For a config with total weight N, the thresholds are valid if
+//
+//   - Election + Replication > N, so that every election quorum intersects
+//     every replication quorum and a new leader learns all committed entries,
+//     and
+//   - 2 * Election > N, so that at most one leader is elected per term.
+//
+// A config for which the thresholds are not valid falls back to majorities.
+// Raft only lets this happen while a config is built up one voter at a time.
+// Since the thresholds are not part of the replicated configuration, all
+// members of a group have to use the same ones.
+type FlexibleQuorum struct {
+	Election    uint64
+	Replication uint64
+}
+
+func (q FlexibleQuorum) String() string {
+	if q == (FlexibleQuorum{}) {
+		return "majority"
+	}
+	return fmt.Sprintf("election=%d replication=%d", q.Election, q.Replication)
+}
+
+// Validate returns an error if the thresholds are not valid for a config with
+// the given total weight.
+func (q FlexibleQuorum) Validate(total uint64) error {
+	if q == (FlexibleQuorum{}) {
+		return nil
+	}
+	switch {
+	case q.Election == 0 || q.Replication == 0:
+		return fmt.Errorf("%s: both thresholds must be set", q)
+	case q.Election > total || q.Replication > total:
+		return fmt.Errorf("%s: thresholds exceed the total weight %d", q, total)
+	case q.Election+q.Replication <= total:
+		return fmt.Errorf("%s: election and replication quorums must intersect (total weight %d)", q, total)
+	case 2*q.Election <= total:
+		return fmt.Errorf("%s: election quorums must intersect (total weight %d)", q, total)
+	}
+	return nil
+}
+
+// thresholds returns the election and replication thresholds for a config
+// with the given total weight, falling back to majorities if q is not valid
+// for it.
+func (q FlexibleQuorum) thresholds(total uint64) (election, replication uint64) {
+	if q == (FlexibleQuorum{}) || q.Validate(total) != nil {
+		return total/2 + 1, total/2 + 1
+	}
+	return q.Election, q.Replication
+}
+
+// ReplicationThreshold returns the weight of the acknowledgements needed to
+// commit an entry in a config with the given total weight.
+func (q FlexibleQuorum) ReplicationThreshold(total uint64) uint64 {
+	_, replication := q.thresholds(total)
+	return replication
+}
+
+// FlexibleCommittedIndex is like WeightedCommittedIndex, but an index is
+// committed once it has been acked by voters carrying the replication
+// threshold.
+func (c MajorityConfig) FlexibleCommittedIndex(q FlexibleQuorum, w Weights, l AckedIndexer) Index {
+	if q == (FlexibleQuorum{}) {
+		return c.WeightedCommittedIndex(w, l)
+	}
+	_, replication := q.thresholds(c.TotalWeight(w))
+	return c.thresholdCommittedIndex(w, replication, l)
+}
+
+// FlexibleVoteResult is like WeightedVoteResult, but the vote is won once the
+// yes votes carry the election threshold.
+func (c MajorityConfig) FlexibleVoteResult(q FlexibleQuorum, w Weights, votes map[uint64]bool) VoteResult {
+	if q == (FlexibleQuorum{}) {
+		return c.WeightedVoteResult(w, votes)
+	}
+	election, _ := q.thresholds(c.TotalWeight(w))
+	return c.thresholdVoteResult(w, election, votes)
+}
+
+// FlexibleAckResult is like FlexibleVoteResult, but uses the replication
+// threshold. It determines whether a set of acknowledgements (for example,
+// from recently active followers) suffices to act as leader.
+func (c MajorityConfig) FlexibleAckResult(q FlexibleQuorum, w Weights, acks map[uint64]bool) VoteResult {
+	if q == (FlexibleQuorum{}) {
+		return c.WeightedVoteResult(w, acks)
+	}
+	_, replication := q.thresholds(c.TotalWeight(w))
+	return c.thresholdVoteResult(w, replication, acks)
+}
+
+// FlexibleCommittedIndex is like CommittedIndex, applying the thresholds and
+// the weights of each half to the respective majority config.
+func (c JointConfig) FlexibleCommittedIndex(q FlexibleQuorum, w JointWeights, l AckedIndexer) Index {
+	idx0 := c[0].FlexibleCommittedIndex(q, w[0], l)
+	idx1 := c[1].FlexibleCommittedIndex(q, w[1], l)
+	if idx0 < idx1 {
+		return idx0
+	}
+	return idx1
+}
+
+// FlexibleVoteResult is like VoteResult, applying the thresholds and the
+// weights of each half to the respective majority config.
+func (c JointConfig) FlexibleVoteResult(q FlexibleQuorum, w JointWeights, votes map[uint64]bool) VoteResult {
+	return CombineVoteResults(c[0].FlexibleVoteResult(q, w[0], votes), c[1].FlexibleVoteResult(q, w[1], votes))
+}
+
+// FlexibleAckResult is like FlexibleVoteResult, but uses the replication
+// threshold.
+func (c JointConfig) FlexibleAckResult(q FlexibleQuorum, w JointWeights, acks map[uint64]bool) VoteResult {
+	return CombineVoteResults(c[0].FlexibleAckResult(q, w[0], acks), c[1].FlexibleAckResult(q, w[1], acks))
+}
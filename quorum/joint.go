@@ -59,9 +59,14 @@ func (c JointConfig) CommittedIndex(l AckedIndexer) Index {
 // a result indicating whether the vote is pending, lost, or won. A joint quorum
 // requires both majority quorums to vote in favor.
 func (c JointConfig) VoteResult(votes map[uint64]bool) VoteResult {
-	r1 := c[0].VoteResult(votes)
-	r2 := c[1].VoteResult(votes)
+	return CombineVoteResults(c[0].VoteResult(votes), c[1].VoteResult(votes))
+}
 
+// CombineVoteResults returns the result of a joint vote given the results in
+// both halves: VoteWon if both are, VoteLost if either is, and VotePending
+// otherwise. It also combines the results of independent conditions which
+// all have to hold.
+func CombineVoteResults(r1, r2 VoteResult) VoteResult {
 	if r1 == r2 {
 		// If they agree, return the agreed state.
 		return r1
@@ -41,6 +41,22 @@ func TestQuick(t *testing.T) {
 		}
 		require.NoError(t, quick.CheckEqual(fn1, fn2, cfg))
 	})
+
+	// With all weights set to one, the weighted computation must agree with
+	// the unweighted one.
+	t.Run("weighted_commit", func(t *testing.T) {
+		fn1 := func(c memberMap, l idxMap) uint64 {
+			return uint64(MajorityConfig(c).CommittedIndex(mapAckIndexer(l)))
+		}
+		fn2 := func(c memberMap, l idxMap) uint64 {
+			w := Weights{}
+			for id := range c {
+				w[id] = 1
+			}
+			return uint64(MajorityConfig(c).WeightedCommittedIndex(w, mapAckIndexer(l)))
+		}
+		require.NoError(t, quick.CheckEqual(fn1, fn2, cfg))
+	})
 }
 
 // smallRandIdxMap returns a reasonably sized map of ids to commit indexes.
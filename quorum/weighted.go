@@ -0,0 +1,173 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quorum
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// Weights assigns voting weights to the voters of a MajorityConfig. Voters
+// without an entry carry a weight of one, so that the zero Weights describes
+// a plain majority config. Weights must be positive.
+//
+// With weights, a quorum is any set of voters carrying more than half of the
+// total weight of the MajorityConfig.
+type Weights map[uint64]uint64
+
+// Weight returns the weight of the given voter.
+func (w Weights) Weight(id uint64) uint64 {
+	if v, ok := w[id]; ok {
+		return v
+	}
+	return 1
+}
+
+func (w Weights) String() string {
+	sl := make([]uint64, 0, len(w))
+	for id := range w {
+		sl = append(sl, id)
+	}
+	slices.Sort(sl)
+	var buf strings.Builder
+	buf.WriteByte('(')
+	for i, id := range sl {
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+		fmt.Fprintf(&buf, "%d:%d", id, w[id])
+	}
+	buf.WriteByte(')')
+	return buf.String()
+}
+
+// JointWeights holds the Weights of the two halves of a JointConfig.
+type JointWeights [2]Weights
+
+func (w JointWeights) String() string {
+	if len(w[1]) > 0 {
+		return w[0].String() + "&&" + w[1].String()
+	}
+	return w[0].String()
+}
+
+// TotalWeight returns the sum of the weights of the voters in the config.
+func (c MajorityConfig) TotalWeight(w Weights) uint64 {
+	var total uint64
+	for id := range c {
+		total += w.Weight(id)
+	}
+	return total
+}
+
+// WeightedCommittedIndex is like CommittedIndex, but an index is committed
+// once it has been acked by voters carrying more than half of the total
+// weight.
+func (c MajorityConfig) WeightedCommittedIndex(w Weights, l AckedIndexer) Index {
+	if len(w) == 0 {
+		return c.CommittedIndex(l)
+	}
+	return c.thresholdCommittedIndex(w, c.TotalWeight(w)/2+1, l)
+}
+
+// thresholdCommittedIndex returns the largest index acked by voters carrying
+// at least the given weight.
+func (c MajorityConfig) thresholdCommittedIndex(w Weights, threshold uint64, l AckedIndexer) Index {
+	if len(c) == 0 {
+		return math.MaxUint64
+	}
+	type acked struct {
+		idx    Index
+		weight uint64
+	}
+	info := make([]acked, 0, len(c))
+	for id := range c {
+		// Voters that haven't reported in are treated as having acked index
+		// zero.
+		idx, _ := l.AckedIndex(id)
+		info = append(info, acked{idx: idx, weight: w.Weight(id)})
+	}
+	// Walk the indexes from the largest down, until the voters that acked
+	// them carry the threshold.
+	slices.SortFunc(info, func(a, b acked) int {
+		return cmp.Compare(b.idx, a.idx)
+	})
+	var sum uint64
+	for _, a := range info {
+		sum += a.weight
+		if sum >= threshold {
+			return a.idx
+		}
+	}
+	return 0
+}
+
+// WeightedVoteResult is like VoteResult, but the vote is won once voters
+// carrying more than half of the total weight have voted yes, and lost once
+// that is no longer possible.
+func (c MajorityConfig) WeightedVoteResult(w Weights, votes map[uint64]bool) VoteResult {
+	if len(w) == 0 {
+		return c.VoteResult(votes)
+	}
+	return c.thresholdVoteResult(w, c.TotalWeight(w)/2+1, votes)
+}
+
+// thresholdVoteResult returns whether the yes votes carry at least the given
+// weight (VoteWon), may still do so (VotePending) or can no longer do so
+// (VoteLost).
+func (c MajorityConfig) thresholdVoteResult(w Weights, threshold uint64, votes map[uint64]bool) VoteResult {
+	if len(c) == 0 {
+		return VoteWon
+	}
+	var yes, missing uint64
+	for id := range c {
+		weight := w.Weight(id)
+		v, ok := votes[id]
+		if !ok {
+			missing += weight
+			continue
+		}
+		if v {
+			yes += weight
+		}
+	}
+	if yes >= threshold {
+		return VoteWon
+	}
+	if yes+missing >= threshold {
+		return VotePending
+	}
+	return VoteLost
+}
+
+// WeightedCommittedIndex is like CommittedIndex, using the weights of each
+// half for the respective majority config.
+func (c JointConfig) WeightedCommittedIndex(w JointWeights, l AckedIndexer) Index {
+	idx0 := c[0].WeightedCommittedIndex(w[0], l)
+	idx1 := c[1].WeightedCommittedIndex(w[1], l)
+	if idx0 < idx1 {
+		return idx0
+	}
+	return idx1
+}
+
+// WeightedVoteResult is like VoteResult, using the weights of each half for
+// the respective majority config.
+func (c JointConfig) WeightedVoteResult(w JointWeights, votes map[uint64]bool) VoteResult {
+	return CombineVoteResults(c[0].WeightedVoteResult(w[0], votes), c[1].WeightedVoteResult(w[1], votes))
+}
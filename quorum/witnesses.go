@@ -0,0 +1,112 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quorum
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+// storingQuorum returns the number of voters storing the log, i.e. voters
+// that aren't witnesses, that have to ack an entry for it to be committed:
+// two, or a majority of them if there are fewer than three. It returns zero
+// if the config has no witnesses, in which case the usual quorum suffices.
+func (c MajorityConfig) storingQuorum(witnesses map[uint64]struct{}) int {
+	var n, storing int
+	for id := range c {
+		if _, ok := witnesses[id]; ok {
+			n++
+		} else {
+			storing++
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+	return min(2, storing/2+1)
+}
+
+// StoringCommittedIndex returns the largest index that has been acked by at
+// least two voters storing the log (i.e. voters that aren't witnesses), or a
+// majority of them if there are fewer than three. This keeps a quorum made
+// up of the leader and witnesses from committing an entry that only the
+// leader stores. It returns math.MaxUint64 if the config has no witnesses.
+// The result is meant to bound the one of CommittedIndex (or one of its
+// variants) from above.
+func (c MajorityConfig) StoringCommittedIndex(witnesses map[uint64]struct{}, l AckedIndexer) Index {
+	required := c.storingQuorum(witnesses)
+	if required == 0 {
+		return math.MaxUint64
+	}
+	srt := make([]Index, 0, len(c))
+	for id := range c {
+		if _, ok := witnesses[id]; ok {
+			continue
+		}
+		idx, _ := l.AckedIndex(id)
+		srt = append(srt, idx)
+	}
+	if len(srt) < required {
+		return 0
+	}
+	slices.SortFunc(srt, func(a, b Index) int { return cmp.Compare(b, a) })
+	return srt[required-1]
+}
+
+// StoringAckResult returns VoteWon if the acks include as many voters storing
+// the log as StoringCommittedIndex requires, VoteLost if the voters storing
+// the log that acked or haven't been heard from yet can't reach as many, and
+// VotePending otherwise. Only the voters storing the log are considered; the
+// result is meant to be combined with that of VoteResult (or one of its
+// variants).
+func (c MajorityConfig) StoringAckResult(witnesses map[uint64]struct{}, acks map[uint64]bool) VoteResult {
+	required := c.storingQuorum(witnesses)
+	if required == 0 {
+		return VoteWon
+	}
+	var yes, possible int
+	for id := range c {
+		if _, ok := witnesses[id]; ok {
+			continue
+		}
+		v, voted := acks[id]
+		if !voted || v {
+			possible++
+		}
+		if v {
+			yes++
+		}
+	}
+	if yes >= required {
+		return VoteWon
+	}
+	if possible >= required {
+		return VotePending
+	}
+	return VoteLost
+}
+
+// StoringCommittedIndex is like the MajorityConfig method, taking the lower
+// bound of both halves.
+func (c JointConfig) StoringCommittedIndex(witnesses map[uint64]struct{}, l AckedIndexer) Index {
+	return min(c[0].StoringCommittedIndex(witnesses, l), c[1].StoringCommittedIndex(witnesses, l))
+}
+
+// StoringAckResult is like the MajorityConfig method, requiring the voters
+// storing the log in both halves to ack.
+func (c JointConfig) StoringAckResult(witnesses map[uint64]struct{}, acks map[uint64]bool) VoteResult {
+	return CombineVoteResults(c[0].StoringAckResult(witnesses, acks), c[1].StoringAckResult(witnesses, acks))
+}
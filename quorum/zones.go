@@ -0,0 +1,200 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quorum
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// Zones assigns nodes to failure domains (zones), such as availability zones
+// or racks. Nodes without an entry are not in any known zone and don't count
+// towards the zones spanned by a set of voters.
+type Zones map[uint64]string
+
+func (z Zones) String() string {
+	sl := make([]uint64, 0, len(z))
+	for id := range z {
+		sl = append(sl, id)
+	}
+	slices.Sort(sl)
+	var buf strings.Builder
+	buf.WriteByte('(')
+	for i, id := range sl {
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+		fmt.Fprintf(&buf, "%d:%s", id, z[id])
+	}
+	buf.WriteByte(')')
+	return buf.String()
+}
+
+// zones returns the sorted zones of the voters in the config, leaving out
+// those in exclude.
+func (c MajorityConfig) zones(z Zones, exclude map[uint64]struct{}) []string {
+	var sl []string
+	for id := range c {
+		if _, ok := exclude[id]; ok {
+			continue
+		}
+		if zone, ok := z[id]; ok && !slices.Contains(sl, zone) {
+			sl = append(sl, zone)
+		}
+	}
+	slices.Sort(sl)
+	return sl
+}
+
+// requiredZones returns the number of zones a quorum has to span to satisfy
+// a requirement of k zones: k, or all zones of the voters not in exclude if
+// they span fewer.
+func (c MajorityConfig) requiredZones(z Zones, exclude map[uint64]struct{}, k int) int {
+	return min(k, len(c.zones(z, exclude)))
+}
+
+// ZoneCommittedIndex returns the largest index that has been acked by voters
+// spanning at least k distinct zones, or all zones of the config if it spans
+// fewer. Witnesses don't store the entries they ack, so neither their acks
+// nor their zones count. It returns math.MaxUint64 if there is no such
+// requirement, i.e. if k is zero or none of the other voters has a zone. The
+// result is meant to bound the one of CommittedIndex (or one of its variants)
+// from above.
+func (c MajorityConfig) ZoneCommittedIndex(z Zones, witnesses map[uint64]struct{}, k int, l AckedIndexer) Index {
+	required := c.requiredZones(z, witnesses, k)
+	if required == 0 {
+		return math.MaxUint64
+	}
+	// The highest index acked by any voter of each zone.
+	acked := map[string]Index{}
+	for id := range c {
+		zone, ok := z[id]
+		if _, witness := witnesses[id]; !ok || witness {
+			continue
+		}
+		idx, _ := l.AckedIndex(id)
+		acked[zone] = max(acked[zone], idx)
+	}
+	srt := make([]Index, 0, len(acked))
+	for _, idx := range acked {
+		srt = append(srt, idx)
+	}
+	slices.SortFunc(srt, func(a, b Index) int { return cmp.Compare(b, a) })
+	return srt[required-1]
+}
+
+// ZoneVoteResult returns VoteWon if the voters that voted yes span at least
+// k distinct zones (or all zones of the config if it spans fewer), VoteLost
+// if the voters that voted yes or haven't voted yet can't span as many, and
+// VotePending otherwise. Only the zones are considered; the result is meant
+// to be combined with that of VoteResult (or one of its variants).
+func (c MajorityConfig) ZoneVoteResult(z Zones, k int, votes map[uint64]bool) VoteResult {
+	return c.zoneResult(z, nil, k, votes)
+}
+
+// ZoneAckResult is like ZoneVoteResult, but for acknowledgements of appended
+// entries: like in ZoneCommittedIndex, the witnesses and their zones don't
+// count.
+func (c MajorityConfig) ZoneAckResult(z Zones, witnesses map[uint64]struct{}, k int, acks map[uint64]bool) VoteResult {
+	return c.zoneResult(z, witnesses, k, acks)
+}
+
+// zoneResult implements ZoneVoteResult and ZoneAckResult, leaving out the
+// voters in exclude.
+func (c MajorityConfig) zoneResult(z Zones, exclude map[uint64]struct{}, k int, votes map[uint64]bool) VoteResult {
+	required := c.requiredZones(z, exclude, k)
+	if required == 0 {
+		return VoteWon
+	}
+	yes, possible := map[string]struct{}{}, map[string]struct{}{}
+	for id := range c {
+		zone, ok := z[id]
+		if _, excluded := exclude[id]; !ok || excluded {
+			continue
+		}
+		v, voted := votes[id]
+		if !voted || v {
+			possible[zone] = struct{}{}
+		}
+		if v {
+			yes[zone] = struct{}{}
+		}
+	}
+	if len(yes) >= required {
+		return VoteWon
+	}
+	if len(possible) >= required {
+		return VotePending
+	}
+	return VoteLost
+}
+
+// CheckZoneLoss returns an error unless the voters outside of any single zone
+// carry enough weight to both win elections and commit entries under q, and
+// the voters among them other than witnesses span at least k zones. Such a
+// config remains available when a zone fails and, if k is at least two, keeps
+// every committed entry as well.
+func (c MajorityConfig) CheckZoneLoss(q FlexibleQuorum, w Weights, z Zones, witnesses map[uint64]struct{}, k int) error {
+	zones := c.zones(z, nil)
+	if len(zones) < 2 {
+		return fmt.Errorf("voters %s span %d zone(s)", c, len(zones))
+	}
+	storing := c.zones(z, witnesses)
+	total := c.TotalWeight(w)
+	election, replication := q.thresholds(total)
+	needed := max(election, replication)
+	for _, zone := range zones {
+		var lost uint64
+		for id := range c {
+			if z[id] == zone {
+				lost += w.Weight(id)
+			}
+		}
+		if total-lost < needed {
+			return fmt.Errorf("losing zone %s leaves voters %s with weight %d of %d, need %d",
+				zone, c, total-lost, total, needed)
+		}
+		left := len(storing)
+		if slices.Contains(storing, zone) {
+			left--
+		}
+		if left < k {
+			return fmt.Errorf("losing zone %s leaves voters %s storing the log in %d zone(s), need %d",
+				zone, c, left, k)
+		}
+	}
+	return nil
+}
+
+// ZoneCommittedIndex is like the MajorityConfig method, taking the lower
+// bound of both halves.
+func (c JointConfig) ZoneCommittedIndex(z Zones, witnesses map[uint64]struct{}, k int, l AckedIndexer) Index {
+	return min(c[0].ZoneCommittedIndex(z, witnesses, k, l), c[1].ZoneCommittedIndex(z, witnesses, k, l))
+}
+
+// ZoneVoteResult is like the MajorityConfig method, requiring the zones of
+// both halves to be spanned.
+func (c JointConfig) ZoneVoteResult(z Zones, k int, votes map[uint64]bool) VoteResult {
+	return CombineVoteResults(c[0].ZoneVoteResult(z, k, votes), c[1].ZoneVoteResult(z, k, votes))
+}
+
+// ZoneAckResult is like the MajorityConfig method, requiring the zones of
+// both halves to be spanned.
+func (c JointConfig) ZoneAckResult(z Zones, witnesses map[uint64]struct{}, k int, acks map[uint64]bool) VoteResult {
+	return CombineVoteResults(c[0].ZoneAckResult(z, witnesses, k, acks), c[1].ZoneAckResult(z, witnesses, k, acks))
+}
@@ -19,11 +19,13 @@ import (
 	"crypto/rand"
 	"errors"
 	"fmt"
+	"log/slog"
 	"math"
 	"math/big"
 	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"go.etcd.io/raft/v3/confchange"
 	"go.etcd.io/raft/v3/quorum"
@@ -184,6 +186,14 @@ type Config struct {
 	// write.
 	AsyncStorageWrites bool
 
+	// AsyncStorageReads makes the leader read the entries it sends to
+	// followers with AsyncStorage.FetchEntries, which can read them in the
+	// background, instead of Storage.Entries. The Storage must implement
+	// AsyncStorage. The leader keeps serving proposals and the other
+	// followers while the entries of a follower that fell behind are read, and
+	// sends them once reported with RawNode.ReportEntries.
+	AsyncStorageReads bool
+
 	// MaxSizePerMsg limits the max byte size of each append message. Smaller
 	// value lowers the raft recovery cost(initial probing and message lost
 	// during normal operation). On the other side, it might affect the
@@ -197,6 +207,11 @@ type Config struct {
 	// Ready structs to encompass all outstanding entries in unacknowledged
 	// MsgStorageApply messages when AsyncStorageWrites is enabled.
 	MaxCommittedSizePerReady uint64
+	// EntryCacheSize limits the total byte size of the most recent stable
+	// entries kept in memory by the log, which spares reading them from
+	// Storage again when sending them to followers or applying them. Zero
+	// disables the cache. See Status.EntryCache for its hit rate.
+	EntryCacheSize uint64
 	// MaxUncommittedEntriesSize limits the aggregate byte size of the
 	// uncommitted entries that may be appended to a leader's log. Once this
 	// limit is exceeded, proposals will begin to return ErrProposalDropped
@@ -242,8 +257,14 @@ type Config struct {
 	ReadOnlyOption ReadOnlyOption
 
 	// Logger is the logger used for raft log. For multinode which can host
-	// multiple raft group, each raft group can have its own logger
+	// multiple raft group, each raft group can have its own logger. If it is
+	// a StructuredLogger, such as a SlogLogger, every line carries the ID,
+	// term and state of the node and the GroupID as attributes.
 	Logger Logger
+	// GroupID identifies the raft group of the node in the lines logged
+	// through a StructuredLogger, which helps telling apart the groups hosted
+	// in one process. Zero omits it.
+	GroupID uint64
 
 	// DisableProposalForwarding set to true means that followers will drop
 	// proposals, rather than forwarding them to the leader. One use case for
@@ -284,6 +305,122 @@ type Config struct {
 	// https://github.com/etcd-io/raft/issues/83
 	StepDownOnRemoval bool
 
+	// ElectionQuorum and ReplicationQuorum configure asymmetric quorums in the
+	// style of Flexible Paxos. A candidate needs votes from voters carrying at
+	// least ElectionQuorum to become leader, while an entry is committed once
+	// acknowledged by voters carrying at least ReplicationQuorum. Without
+	// weights, this is the number of voters. For example, a group of five
+	// voters with ElectionQuorum 4 and ReplicationQuorum 2 commits entries on
+	// the leader and a single follower, at the expense of needing four votes
+	// in elections.
+	//
+	// The thresholds must satisfy ElectionQuorum + ReplicationQuorum > N and
+	// 2 * ElectionQuorum > N for the total weight N of the voters. Raft
+	// refuses to start with initial voters for which this doesn't hold, and
+	// refuses conf changes after which it no longer holds. Only configs still
+	// being built up one voter at a time, which never suited the thresholds,
+	// use majorities instead. Both thresholds must be set, or neither, in
+	// which case majorities are used throughout. All members of the group
+	// must use the same thresholds.
+	ElectionQuorum    uint64
+	ReplicationQuorum uint64
+
+	// MinCommitZones is the number of zones (see ConfChangeSingle.Zone) that
+	// the voters acknowledging an entry have to span for it to be committed,
+	// and that the voters granting a candidate their votes have to span for
+	// it to win an election. With a value of two or more, a committed entry
+	// survives the loss of any one zone. A config (or half of a joint config)
+	// spanning fewer zones has to span all of them instead, and voters without
+	// a zone don't count towards the zones. Neither do the acknowledgements of
+	// witnesses, which don't store the entries, though their votes do. Zero
+	// disables the requirement. All members of the group must use the same
+	// value.
+	//
+	// Independently of this setting, once nodes are placed in zones, a
+	// configuration change is refused if the incoming voters wouldn't
+	// tolerate the loss of any one zone after the change, i.e. if afterwards
+	// the voters outside of some zone couldn't win an election, commit entries
+	// or store them in MinCommitZones zones.
+	MinCommitZones int
+
+	// LenientZoneChecks relaxes the zone check of configuration changes (see
+	// MinCommitZones) to only refuse changes after which voters that tolerated
+	// the loss of a zone no longer do. It allows rolling out zones to an
+	// existing group, or growing a group a voter at a time, through
+	// configurations that don't tolerate the loss of a zone yet, and should
+	// be disabled again once the configuration does.
+	LenientZoneChecks bool
+
+	// LeaseDuration enables leader leases (see the "Leader leases" section of
+	// the package documentation) when positive. A follower acknowledging a
+	// heartbeat promises not to vote for another candidate for this long, and
+	// the leader holds the lease once a quorum acknowledged a heartbeat, until
+	// LeaseDuration minus MaxClockOffset has passed since sending it. The
+	// leader exposes the lease expiry in Status.LeaseExpiry. The duration
+	// should be shorter than the election timeout: a follower refuses to
+	// campaign or vote for anyone but the leader while its promise holds, so
+	// a longer lease delays elections after a leader failure. All members of
+	// the group must use the same value.
+	LeaseDuration time.Duration
+	// MaxClockOffset bounds the difference between the clocks of any two
+	// peers. Leases are only safe if the bound holds. It must be smaller than
+	// LeaseDuration.
+	MaxClockOffset time.Duration
+	// Clock is the clock used for leader leases and follower reads. Defaults
+	// to the system clock.
+	Clock Clock
+
+	// FollowerReads makes the leader establish a safe read index (see the
+	// "Follower reads" section of the package documentation) with every round
+	// of heartbeats acknowledged by a quorum, and piggyback it on heartbeats
+	// and appends. Any peer can then serve reads with bounded staleness at the
+	// safe read index, see RawNode.SafeReadIndex. Staleness bounds rely on
+	// MaxClockOffset. All members of the group must use the same value.
+	FollowerReads bool
+
+	// EntryChecksums makes the leader compute a checksum of each entry it
+	// appends to its log. Entries carrying a checksum are verified when
+	// received in an append, and dropped if corrupted, and before they are
+	// handed out in Ready.CommittedEntries, panicking if corrupted. Peers
+	// verify checksums regardless of this setting.
+	EntryChecksums bool
+
+	// HashCheckInterval is the number of ticks between the cross-replica
+	// consistency checks proposed by the leader. Zero disables them. See the
+	// "Hash checks" section of the package documentation for the part the
+	// application has to play.
+	HashCheckInterval int
+	// OnHashMismatch is called when the hash of the state machine of this
+	// replica differs from the one of the leader at a hash check. It is
+	// called from within the raft node, and must not call back into it.
+	OnHashMismatch func(HashMismatch)
+
+	// ProposalTracer, if set, receives the lifecycle events of the entries
+	// appended by the node as a leader, from the proposal to its application.
+	// See ProposalLatencyCollector for a tracer computing the latency of each
+	// stage.
+	ProposalTracer ProposalTracer
+
+	// Quiesce lets the leader stop heartbeating when its group is idle: once
+	// all the entries are committed, applied and acknowledged by every peer,
+	// and nothing is in progress, it sends a last heartbeat telling the
+	// followers to go quiet, and stops ticking. The followers then suspend
+	// their election timeout (whatever their own setting). Any message from a
+	// peer, a proposal, or an explicit wake-up (see RawNode.Wake) wakes a node
+	// up, and a follower woken up wakes the leader up in turn.
+	//
+	// A quiesced leader doesn't check the quorum (see CheckQuorum), and a
+	// group whose leader failed while quiesced doesn't elect a new one until
+	// one of its nodes is woken up. The application is thus expected to wake
+	// the groups whose leader it suspects to be down, e.g. if the connection
+	// to it broke.
+	Quiesce bool
+
+	// Metrics is notified of the events of the node, such as elections,
+	// messages and dropped proposals, to be counted by the application. If
+	// nil, NoopMetrics is used.
+	Metrics Metrics
+
 	// raft state tracer
 	TraceLogger TraceLogger
 }
@@ -308,6 +445,10 @@ func (c *Config) validate() error {
 		return errors.New("storage cannot be nil")
 	}
 
+	if _, ok := c.Storage.(AsyncStorage); c.AsyncStorageReads && !ok {
+		return errors.New("storage must implement AsyncStorage with AsyncStorageReads")
+	}
+
 	if c.MaxUncommittedEntriesSize == 0 {
 		c.MaxUncommittedEntriesSize = noLimit
 	}
@@ -331,10 +472,54 @@ func (c *Config) validate() error {
 		c.Logger = getLogger()
 	}
 
+	if c.Metrics == nil {
+		c.Metrics = NoopMetrics{}
+	}
+
 	if c.ReadOnlyOption == ReadOnlyLeaseBased && !c.CheckQuorum {
 		return errors.New("CheckQuorum must be enabled when ReadOnlyOption is ReadOnlyLeaseBased")
 	}
 
+	if (c.ElectionQuorum == 0) != (c.ReplicationQuorum == 0) {
+		return errors.New("ElectionQuorum and ReplicationQuorum must be set together")
+	}
+	if q := (quorum.FlexibleQuorum{Election: c.ElectionQuorum, Replication: c.ReplicationQuorum}); q != (quorum.FlexibleQuorum{}) {
+		// The thresholds must suit the initial voters, when they are known.
+		_, cs, err := c.Storage.InitialState()
+		if err != nil {
+			return err
+		}
+		for _, half := range [...]struct {
+			voters  []uint64
+			weights []pb.VoterWeight
+		}{{cs.Voters, cs.Weights}, {cs.VotersOutgoing, cs.WeightsOutgoing}} {
+			if len(half.voters) == 0 {
+				continue
+			}
+			if err := q.Validate(votersWeight(half.voters, half.weights)); err != nil {
+				return fmt.Errorf("invalid quorums for voters %v: %w", half.voters, err)
+			}
+		}
+	}
+
+	if c.MinCommitZones < 0 {
+		return errors.New("min commit zones must not be negative")
+	}
+
+	if c.HashCheckInterval < 0 {
+		return errors.New("hash check interval must not be negative")
+	}
+
+	if c.LeaseDuration < 0 || c.MaxClockOffset < 0 {
+		return errors.New("lease duration and max clock offset must not be negative")
+	}
+	if c.LeaseDuration > 0 && c.MaxClockOffset >= c.LeaseDuration {
+		return errors.New("max clock offset must be smaller than lease duration")
+	}
+	if c.Clock == nil {
+		c.Clock = systemClock{}
+	}
+
 	return nil
 }
 
@@ -431,6 +616,22 @@ type raft struct {
 	// current term.
 	pendingReadIndexMessages []pb.Message
 
+	lease leaderLease
+
+	entryChecksums bool
+	hashCheck      hashChecker
+
+	// fetcher reads the entries sent to followers in the background, if
+	// AsyncStorageReads is enabled.
+	fetcher *entryFetcher
+
+	metrics Metrics
+
+	// quiesce enables quiescing the group when idle, see Config.Quiesce.
+	quiesce bool
+	// quiesced is set while the node doesn't tick, until it wakes up.
+	quiesced bool
+
 	traceLogger TraceLogger
 }
 
@@ -438,7 +639,10 @@ func newRaft(c *Config) *raft {
 	if err := c.validate(); err != nil {
 		panic(err.Error())
 	}
-	raftlog := newLogWithSize(c.Storage, c.Logger, entryEncodingSize(c.MaxCommittedSizePerReady))
+	logger, nodeLogger := newNodeLogger(c.Logger, c.GroupID)
+	raftlog := newLogWithSize(c.Storage, logger, entryEncodingSize(c.MaxCommittedSizePerReady))
+	raftlog.cache = newEntryCache(c.EntryCacheSize)
+	raftlog.trace = newProposalTrace(c.ProposalTracer, c.ID)
 	hs, cs, err := c.Storage.InitialState()
 	if err != nil {
 		panic(err) // TODO(bdarnell)
@@ -454,16 +658,40 @@ func newRaft(c *Config) *raft {
 		trk:                         tracker.MakeProgressTracker(c.MaxInflightMsgs, c.MaxInflightBytes),
 		electionTimeout:             c.ElectionTick,
 		heartbeatTimeout:            c.HeartbeatTick,
-		logger:                      c.Logger,
+		logger:                      logger,
 		checkQuorum:                 c.CheckQuorum,
 		preVote:                     c.PreVote,
 		readOnly:                    newReadOnly(c.ReadOnlyOption),
 		disableProposalForwarding:   c.DisableProposalForwarding,
 		disableConfChangeValidation: c.DisableConfChangeValidation,
 		stepDownOnRemoval:           c.StepDownOnRemoval,
+		metrics:                     c.Metrics,
+		quiesce:                     c.Quiesce,
 		traceLogger:                 c.TraceLogger,
+		lease: leaderLease{
+			duration:      c.LeaseDuration,
+			maxOffset:     c.MaxClockOffset,
+			followerReads: c.FollowerReads,
+			clock:         c.Clock,
+		},
+		entryChecksums: c.EntryChecksums,
+		hashCheck: hashChecker{
+			interval:   c.HashCheckInterval,
+			onMismatch: c.OnHashMismatch,
+		},
+	}
+
+	if nodeLogger != nil {
+		nodeLogger.r = r
+	}
+	if c.AsyncStorageReads {
+		r.fetcher = newEntryFetcher(c.Storage.(AsyncStorage))
 	}
 
+	r.trk.Quorum = quorum.FlexibleQuorum{Election: c.ElectionQuorum, Replication: c.ReplicationQuorum}
+	r.trk.MinCommitZones = c.MinCommitZones
+	r.trk.LenientZoneChecks = c.LenientZoneChecks
+
 	traceInitState(r)
 
 	lastID := r.raftLog.lastEntryID()
@@ -483,6 +711,12 @@ func newRaft(c *Config) *raft {
 		raftlog.appliedTo(c.Applied, 0 /* size */)
 	}
 	r.becomeFollower(r.Term, None)
+	if r.lease.enabled() {
+		// This peer may have acknowledged heartbeats before restarting, but
+		// doesn't remember the leader it made the promise to. Honor it for
+		// any leader.
+		r.lease.promise(None)
+	}
 
 	var nodesStrs []string
 	for _, n := range r.trk.VoterNodes() {
@@ -596,6 +830,7 @@ func (r *raft) send(m pb.Message) {
 		r.msgs = append(r.msgs, m)
 		traceSendMessage(r, &m)
 	}
+	r.metrics.MessageSent(m.Type)
 }
 
 // sendAppend sends an append RPC with new entries (if any) and the
@@ -635,7 +870,11 @@ func (r *raft) maybeSendAppend(to uint64, sendIfEmpty bool) bool {
 	// leader to send an append), allowing it to be acked or rejected, both of
 	// which will clear out Inflights.
 	if pr.State != tracker.StateReplicate || !pr.Inflights.Full() {
-		ents, err = r.raftLog.entries(pr.Next, r.maxMsgSize)
+		ents, err = r.entriesToSend(pr.Next)
+	}
+	if err == ErrEntriesPending {
+		// The entries are sent once they are read, see handleStorageEntries.
+		return false
 	}
 	if len(ents) == 0 && !sendIfEmpty {
 		return false
@@ -644,18 +883,27 @@ func (r *raft) maybeSendAppend(to uint64, sendIfEmpty bool) bool {
 	if err != nil { // send a snapshot if we failed to get the entries
 		return r.maybeSendSnapshot(to, pr)
 	}
+	if pr.IsWitness {
+		ents = witnessEntries(ents)
+	}
 
 	// Send the actual MsgApp otherwise, and update the progress accordingly.
-	r.send(pb.Message{
+	m := pb.Message{
 		To:      to,
 		Type:    pb.MsgApp,
 		Index:   prevIndex,
 		LogTerm: prevTerm,
 		Entries: ents,
 		Commit:  r.raftLog.committed,
-	})
+	}
+	r.attachSafeRead(&m)
+	r.send(m)
+	paused := pr.IsPaused()
 	pr.SentEntries(len(ents), uint64(payloadsSize(ents)))
 	pr.SentCommit(r.raftLog.committed)
+	if !paused && pr.IsPaused() {
+		r.metrics.FlowControlPaused(to)
+	}
 	return true
 }
 
@@ -663,14 +911,14 @@ func (r *raft) maybeSendAppend(to uint64, sendIfEmpty bool) bool {
 // node. Returns true iff the snapshot message has been emitted successfully.
 func (r *raft) maybeSendSnapshot(to uint64, pr *tracker.Progress) bool {
 	if !pr.RecentActive {
-		r.logger.Debugf("ignore sending snapshot to %x since it is not recently active", to)
+		r.peerLogger(to).Debugf("ignore sending snapshot to %x since it is not recently active", to)
 		return false
 	}
 
 	snapshot, err := r.raftLog.snapshot()
 	if err != nil {
 		if err == ErrSnapshotTemporarilyUnavailable {
-			r.logger.Debugf("%x failed to send snapshot to %x because snapshot is temporarily unavailable", r.id, to)
+			r.peerLogger(to).Debugf("%x failed to send snapshot to %x because snapshot is temporarily unavailable", r.id, to)
 			return false
 		}
 		panic(err) // TODO(bdarnell)
@@ -679,17 +927,36 @@ func (r *raft) maybeSendSnapshot(to uint64, pr *tracker.Progress) bool {
 		panic("need non-empty snapshot")
 	}
 	sindex, sterm := snapshot.Metadata.Index, snapshot.Metadata.Term
-	r.logger.Debugf("%x [firstindex: %d, commit: %d] sent snapshot[index: %d, term: %d] to %x [%s]",
+	r.peerLogger(to, slog.Uint64("index", sindex)).Debugf("%x [firstindex: %d, commit: %d] sent snapshot[index: %d, term: %d] to %x [%s]",
 		r.id, r.raftLog.firstIndex(), r.raftLog.committed, sindex, sterm, to, pr)
 	pr.BecomeSnapshot(sindex)
 	r.logger.Debugf("%x paused sending replication messages to %x [%s]", r.id, to, pr)
 
+	if pr.IsWitness {
+		// Witnesses only need the metadata of the snapshot.
+		snapshot.Data = nil
+	}
 	r.send(pb.Message{To: to, Type: pb.MsgSnap, Snapshot: &snapshot})
+	r.metrics.SnapshotSent(to)
 	return true
 }
 
+// witnessEntries returns a copy of the entries suitable for sending to a
+// witness: the payloads of normal entries are stripped, while configuration
+// changes are kept intact so that the witness can track the membership.
+func witnessEntries(ents []pb.Entry) []pb.Entry {
+	stripped := make([]pb.Entry, len(ents))
+	for i, e := range ents {
+		if e.Type == pb.EntryNormal {
+			e.Data, e.Checksum = nil, 0
+		}
+		stripped[i] = e
+	}
+	return stripped
+}
+
 // sendHeartbeat sends a heartbeat RPC to the given peer.
-func (r *raft) sendHeartbeat(to uint64, ctx []byte) {
+func (r *raft) sendHeartbeat(to uint64, ctx []byte, seq uint64) {
 	pr := r.trk.Progress[to]
 	// Attach the commit as min(to.matched, r.committed).
 	// When the leader sends out heartbeat message,
@@ -698,12 +965,18 @@ func (r *raft) sendHeartbeat(to uint64, ctx []byte) {
 	// The leader MUST NOT forward the follower's commit to
 	// an unmatched index.
 	commit := min(pr.Match, r.raftLog.committed)
-	r.send(pb.Message{
+	m := pb.Message{
 		To:      to,
 		Type:    pb.MsgHeartbeat,
 		Commit:  commit,
 		Context: ctx,
-	})
+		// The heartbeat round of the leader lease, if any.
+		Index: seq,
+		// The last heartbeat before the group goes quiet.
+		Quiesce: r.quiesced,
+	}
+	r.attachSafeRead(&m)
+	r.send(m)
 	pr.SentCommit(commit)
 }
 
@@ -729,11 +1002,12 @@ func (r *raft) bcastHeartbeat() {
 }
 
 func (r *raft) bcastHeartbeatWithCtx(ctx []byte) {
+	seq := r.sendLeaseRound()
 	r.trk.Visit(func(id uint64, _ *tracker.Progress) {
 		if id == r.id {
 			return
 		}
-		r.sendHeartbeat(id, ctx)
+		r.sendHeartbeat(id, ctx, seq)
 	})
 }
 
@@ -785,14 +1059,17 @@ func (r *raft) reset(term uint64) {
 	if r.Term != term {
 		r.Term = term
 		r.Vote = None
+		r.metrics.TermBumped(term)
 	}
 	r.lead = None
+	r.quiesced = false
 
 	r.electionElapsed = 0
 	r.heartbeatElapsed = 0
 	r.resetRandomizedElectionTimeout()
 
 	r.abortLeaderTransfer()
+	r.lease.resetLeader()
 
 	r.trk.ResetVotes()
 	r.trk.Visit(func(id uint64, pr *tracker.Progress) {
@@ -801,6 +1078,7 @@ func (r *raft) reset(term uint64) {
 			Next:      r.raftLog.lastIndex() + 1,
 			Inflights: tracker.NewInflights(r.trk.MaxInflight, r.trk.MaxInflightBytes),
 			IsLearner: pr.IsLearner,
+			IsWitness: pr.IsWitness,
 		}
 		if id == r.id {
 			pr.Match = r.raftLog.lastIndex()
@@ -810,6 +1088,9 @@ func (r *raft) reset(term uint64) {
 	r.pendingConfIndex = 0
 	r.uncommittedSize = 0
 	r.readOnly = newReadOnly(r.readOnly.option)
+	if r.fetcher != nil {
+		r.fetcher.reset()
+	}
 }
 
 func (r *raft) appendEntry(es ...pb.Entry) (accepted bool) {
@@ -817,6 +1098,9 @@ func (r *raft) appendEntry(es ...pb.Entry) (accepted bool) {
 	for i := range es {
 		es[i].Term = r.Term
 		es[i].Index = li + 1 + uint64(i)
+		if r.entryChecksums {
+			es[i].Checksum = entryChecksum(es[i])
+		}
 	}
 	// Track the size of this uncommitted proposal.
 	if !r.increaseUncommittedSize(es) {
@@ -832,6 +1116,7 @@ func (r *raft) appendEntry(es ...pb.Entry) (accepted bool) {
 
 	// use latest "last" index after truncate/append
 	li = r.raftLog.append(es...)
+	r.raftLog.trace.appended(es)
 	// The leader needs to self-ack the entries just appended once they have
 	// been durably persisted (since it doesn't send an MsgApp to itself). This
 	// response message will be added to msgsAfterAppend and delivered back to
@@ -848,6 +1133,9 @@ func (r *raft) appendEntry(es ...pb.Entry) (accepted bool) {
 
 // tickElection is run by followers and candidates after r.electionTimeout.
 func (r *raft) tickElection() {
+	if r.quiesced {
+		return
+	}
 	r.electionElapsed++
 
 	if r.promotable() && r.pastElectionTimeout() {
@@ -860,6 +1148,9 @@ func (r *raft) tickElection() {
 
 // tickHeartbeat is run by leaders to send a MsgBeat after r.heartbeatTimeout.
 func (r *raft) tickHeartbeat() {
+	if r.quiesced {
+		return
+	}
 	r.heartbeatElapsed++
 	r.electionElapsed++
 
@@ -879,9 +1170,14 @@ func (r *raft) tickHeartbeat() {
 	if r.state != StateLeader {
 		return
 	}
+	r.maybeProposeHashCheck()
 
 	if r.heartbeatElapsed >= r.heartbeatTimeout {
 		r.heartbeatElapsed = 0
+		if r.canQuiesce() {
+			r.quiesceGroup()
+			return
+		}
 		if err := r.Step(pb.Message{From: r.id, Type: pb.MsgBeat}); err != nil {
 			r.logger.Debugf("error occurred during checking sending heartbeat: %v", err)
 		}
@@ -889,6 +1185,9 @@ func (r *raft) tickHeartbeat() {
 }
 
 func (r *raft) becomeFollower(term uint64, lead uint64) {
+	if r.state == StateCandidate || r.state == StatePreCandidate {
+		r.metrics.ElectionLost(r.state == StatePreCandidate)
+	}
 	r.step = stepFollower
 	r.reset(term)
 	r.tick = r.tickElection
@@ -984,7 +1283,16 @@ func (r *raft) hup(t CampaignType) {
 		r.logger.Warningf("%x cannot campaign at term %d since there are still pending configuration changes to apply", r.id, r.Term)
 		return
 	}
+	if promised, remaining := r.lease.promisedAgainst(r.id); promised && t != campaignTransfer {
+		r.logger.Infof("%x cannot campaign at term %d since its lease promise is not expired (remaining: %v)",
+			r.id, r.Term, remaining)
+		return
+	}
 
+	if r.state == StateCandidate || r.state == StatePreCandidate {
+		// The previous election timed out.
+		r.metrics.ElectionLost(r.state == StatePreCandidate)
+	}
 	r.logger.Infof("%x is starting a new election at term %d", r.id, r.Term)
 	r.campaign(t)
 }
@@ -1028,6 +1336,7 @@ func (r *raft) campaign(t CampaignType) {
 		// better safe than sorry.
 		r.logger.Warningf("%x is unpromotable; campaign() should have been called", r.id)
 	}
+	r.metrics.ElectionStarted(t == campaignPreElection)
 	var term uint64
 	var voteMsg pb.MessageType
 	if t == campaignPreElection {
@@ -1061,7 +1370,7 @@ func (r *raft) campaign(t CampaignType) {
 		}
 		// TODO(pav-kv): it should be ok to simply print %+v for the lastEntryID.
 		last := r.raftLog.lastEntryID()
-		r.logger.Infof("%x [logterm: %d, index: %d] sent %s request to %x at term %d",
+		r.peerLogger(id, slog.String("msg_type", voteMsg.String())).Infof("%x [logterm: %d, index: %d] sent %s request to %x at term %d",
 			r.id, last.term, last.index, voteMsg, id, r.Term)
 
 		var ctx []byte
@@ -1074,9 +1383,9 @@ func (r *raft) campaign(t CampaignType) {
 
 func (r *raft) poll(id uint64, t pb.MessageType, v bool) (granted int, rejected int, result quorum.VoteResult) {
 	if v {
-		r.logger.Infof("%x received %s from %x at term %d", r.id, t, id, r.Term)
+		r.peerLogger(id).Infof("%x received %s from %x at term %d", r.id, t, id, r.Term)
 	} else {
-		r.logger.Infof("%x received %s rejection from %x at term %d", r.id, t, id, r.Term)
+		r.peerLogger(id).Infof("%x received %s rejection from %x at term %d", r.id, t, id, r.Term)
 	}
 	r.trk.RecordVote(id, v)
 	return r.trk.TallyVotes()
@@ -1084,6 +1393,12 @@ func (r *raft) poll(id uint64, t pb.MessageType, v bool) (granted int, rejected
 
 func (r *raft) Step(m pb.Message) error {
 	traceReceiveMessage(r, &m)
+	if m.From != None && m.From != r.id && !IsLocalMsg(m.Type) {
+		r.metrics.MessageReceived(m.Type)
+	}
+	if r.quiesced && wakesUp(m) {
+		r.wake(m)
+	}
 
 	// Handle the message term, which may result in our stepping down to a follower.
 	switch {
@@ -1102,6 +1417,15 @@ func (r *raft) Step(m pb.Message) error {
 					r.id, last.term, last.index, r.Vote, m.Type, m.From, m.LogTerm, m.Index, r.Term, r.electionTimeout-r.electionElapsed)
 				return nil
 			}
+			if promised, remaining := r.lease.promisedAgainst(m.From); !force && promised {
+				// This peer acknowledged a heartbeat of the leader within the
+				// lease duration, and the leader may be relying on it to serve
+				// reads.
+				last := r.raftLog.lastEntryID()
+				r.logger.Infof("%x [logterm: %d, index: %d, vote: %x] ignored %s from %x [logterm: %d, index: %d] at term %d: lease promise is not expired (remaining: %v)",
+					r.id, last.term, last.index, r.Vote, m.Type, m.From, m.LogTerm, m.Index, r.Term, remaining)
+				return nil
+			}
 		}
 		switch {
 		case m.Type == pb.MsgPreVote:
@@ -1113,7 +1437,7 @@ func (r *raft) Step(m pb.Message) error {
 			// rejected our vote so we should become a follower at the new
 			// term.
 		default:
-			r.logger.Infof("%x [term: %d] received a %s message with higher term from %x [term: %d]",
+			r.peerLogger(m.From, slog.String("msg_type", m.Type.String())).Infof("%x [term: %d] received a %s message with higher term from %x [term: %d]",
 				r.id, r.Term, m.Type, m.From, m.Term)
 			if m.Type == pb.MsgApp || m.Type == pb.MsgHeartbeat || m.Type == pb.MsgSnap {
 				r.becomeFollower(m.Term, m.From)
@@ -1152,9 +1476,10 @@ func (r *raft) Step(m pb.Message) error {
 			// we drop messages with a lower term.
 			last := r.raftLog.lastEntryID()
 			// TODO(pav-kv): it should be ok to simply print %+v of the lastEntryID.
-			r.logger.Infof("%x [logterm: %d, index: %d, vote: %x] rejected %s from %x [logterm: %d, index: %d] at term %d",
+			r.peerLogger(m.From, slog.String("msg_type", m.Type.String())).Infof("%x [logterm: %d, index: %d, vote: %x] rejected %s from %x [logterm: %d, index: %d] at term %d",
 				r.id, last.term, last.index, r.Vote, m.Type, m.From, m.LogTerm, m.Index, r.Term)
 			r.send(pb.Message{To: m.From, Term: r.Term, Type: pb.MsgPreVoteResp, Reject: true})
+			r.metrics.PreVoteRejected()
 		} else if m.Type == pb.MsgStorageAppendResp {
 			if m.Index != 0 {
 				// Don't consider the appended log entries to be stable because
@@ -1179,6 +1504,9 @@ func (r *raft) Step(m pb.Message) error {
 	}
 
 	switch m.Type {
+	case pb.MsgWake:
+		// The node is awake at this point, see above.
+
 	case pb.MsgHup:
 		if r.preVote {
 			r.hup(campaignPreElection)
@@ -1201,6 +1529,12 @@ func (r *raft) Step(m pb.Message) error {
 			r.reduceUncommittedSize(payloadsSize(m.Entries))
 		}
 
+	case pb.MsgStateHash:
+		r.handleStateHash(m)
+
+	case pb.MsgStorageEntries:
+		r.handleStorageEntries(m)
+
 	case pb.MsgVote, pb.MsgPreVote:
 		// We can vote if this is a repeat of a vote we've already cast...
 		canVote := r.Vote == m.From ||
@@ -1230,7 +1564,7 @@ func (r *raft) Step(m pb.Message) error {
 			// it won't win the election, at least in the absence of the bug discussed
 			// in:
 			// https://github.com/etcd-io/etcd/issues/7625#issuecomment-488798263.
-			r.logger.Infof("%x [logterm: %d, index: %d, vote: %x] cast %s for %x [logterm: %d, index: %d] at term %d",
+			r.peerLogger(m.From, slog.String("msg_type", m.Type.String())).Infof("%x [logterm: %d, index: %d, vote: %x] cast %s for %x [logterm: %d, index: %d] at term %d",
 				r.id, lastID.term, lastID.index, r.Vote, m.Type, m.From, candLastID.term, candLastID.index, r.Term)
 			// When responding to Msg{Pre,}Vote messages we include the term
 			// from the message, not the local term. To see why, consider the
@@ -1248,9 +1582,12 @@ func (r *raft) Step(m pb.Message) error {
 				r.Vote = m.From
 			}
 		} else {
-			r.logger.Infof("%x [logterm: %d, index: %d, vote: %x] rejected %s from %x [logterm: %d, index: %d] at term %d",
+			r.peerLogger(m.From, slog.String("msg_type", m.Type.String())).Infof("%x [logterm: %d, index: %d, vote: %x] rejected %s from %x [logterm: %d, index: %d] at term %d",
 				r.id, lastID.term, lastID.index, r.Vote, m.Type, m.From, candLastID.term, candLastID.index, r.Term)
 			r.send(pb.Message{To: m.From, Term: r.Term, Type: voteRespMsgType(m.Type), Reject: true})
+			if m.Type == pb.MsgPreVote {
+				r.metrics.PreVoteRejected()
+			}
 		}
 
 	default:
@@ -1291,10 +1628,12 @@ func stepLeader(r *raft, m pb.Message) error {
 			// If we are not currently a member of the range (i.e. this node
 			// was removed from the configuration while serving as leader),
 			// drop any new proposals.
+			r.metrics.ProposalDropped(ProposalDropNotMember)
 			return ErrProposalDropped
 		}
 		if r.leadTransferee != None {
 			r.logger.Debugf("%x [term %d] transfer leadership to %x is in progress; dropping proposal", r.id, r.Term, r.leadTransferee)
+			r.metrics.ProposalDropped(ProposalDropTransfer)
 			return ErrProposalDropped
 		}
 
@@ -1326,6 +1665,8 @@ func stepLeader(r *raft, m pb.Message) error {
 					failedCheck = "must transition out of joint config first"
 				} else if !alreadyJoint && wantsLeaveJoint {
 					failedCheck = "not in joint state; refusing empty conf change"
+				} else if err := r.checkConfChange(cc.AsV2()); err != nil {
+					failedCheck = err.Error()
 				}
 
 				if failedCheck != "" && !r.disableConfChangeValidation {
@@ -1339,6 +1680,7 @@ func stepLeader(r *raft, m pb.Message) error {
 		}
 
 		if !r.appendEntry(m.Entries...) {
+			r.metrics.ProposalDropped(ProposalDropUncommittedSize)
 			return ErrProposalDropped
 		}
 		r.bcastAppend()
@@ -1400,7 +1742,7 @@ func stepLeader(r *raft, m pb.Message) error {
 			// which can easily result in hours of time spent probing and can
 			// even cause outright outages. The probes are thus optimized as
 			// described below.
-			r.logger.Debugf("%x received MsgAppResp(rejected, hint: (index %d, term %d)) from %x for index %d",
+			r.peerLogger(m.From, slog.Uint64("index", m.Index)).Debugf("%x received MsgAppResp(rejected, hint: (index %d, term %d)) from %x for index %d",
 				r.id, m.RejectHint, m.LogTerm, m.From, m.Index)
 			nextProbeIdx := m.RejectHint
 			if m.LogTerm > 0 {
@@ -1501,7 +1843,7 @@ func stepLeader(r *raft, m pb.Message) error {
 				nextProbeIdx, _ = r.raftLog.findConflictByTerm(m.RejectHint, m.LogTerm)
 			}
 			if pr.MaybeDecrTo(m.Index, nextProbeIdx) {
-				r.logger.Debugf("%x decreased progress of %x to [%s]", r.id, m.From, pr)
+				r.peerLogger(m.From).Debugf("%x decreased progress of %x to [%s]", r.id, m.From, pr)
 				if pr.State == tracker.StateReplicate {
 					pr.BecomeProbe()
 				}
@@ -1527,7 +1869,7 @@ func stepLeader(r *raft, m pb.Message) error {
 					// the follower from the log, we will accept it. This gives
 					// systems more flexibility in how they implement snapshots;
 					// see the comments on PendingSnapshot.
-					r.logger.Debugf("%x recovered from needing snapshot, resumed sending replication messages to %x [%s]", r.id, m.From, pr)
+					r.peerLogger(m.From).Debugf("%x recovered from needing snapshot, resumed sending replication messages to %x [%s]", r.id, m.From, pr)
 					// Transition back to replicating state via probing state
 					// (which takes the snapshot into account). If we didn't
 					// move to replicating state, that would only happen with
@@ -1565,12 +1907,16 @@ func stepLeader(r *raft, m pb.Message) error {
 				if m.From == r.leadTransferee && pr.Match == r.raftLog.lastIndex() {
 					r.logger.Infof("%x sent MsgTimeoutNow to %x after received MsgAppResp", r.id, m.From)
 					r.sendTimeoutNow(m.From)
+				} else {
+					r.maybeTransferToPreferred(m.From)
 				}
 			}
 		}
 	case pb.MsgHeartbeatResp:
 		pr.RecentActive = true
 		pr.MsgAppFlowPaused = false
+		r.maybeExtendLease(m.From, m.Index)
+		r.maybeTransferToPreferred(m.From)
 
 		// NB: if the follower is paused (full Inflights), this will still send an
 		// empty append, allowing it to recover from situations in which all the
@@ -1593,7 +1939,7 @@ func stepLeader(r *raft, m pb.Message) error {
 			return nil
 		}
 
-		if r.trk.Voters.VoteResult(r.readOnly.recvAck(m.From, m.Context)) != quorum.VoteWon {
+		if r.trk.AckResult(r.readOnly.recvAck(m.From, m.Context)) != quorum.VoteWon {
 			return nil
 		}
 
@@ -1633,6 +1979,10 @@ func stepLeader(r *raft, m pb.Message) error {
 			r.logger.Debugf("%x is learner. Ignored transferring leadership", r.id)
 			return nil
 		}
+		if pr.IsWitness {
+			r.logger.Debugf("%x is witness. Ignored transferring leadership", m.From)
+			return nil
+		}
 		leadTransferee := m.From
 		lastLeadTransferee := r.leadTransferee
 		if lastLeadTransferee != None {
@@ -1649,7 +1999,10 @@ func stepLeader(r *raft, m pb.Message) error {
 			return nil
 		}
 		// Transfer leadership to third party.
-		r.logger.Infof("%x [term %d] starts to transfer leadership to %x", r.id, r.Term, leadTransferee)
+		r.peerLogger(leadTransferee).Infof("%x [term %d] starts to transfer leadership to %x", r.id, r.Term, leadTransferee)
+		// The transferee campaigns regardless of the promises made to this
+		// leader, so hand the lease off by giving it up.
+		r.revokeLease()
 		// Transfer leadership should be finished in one electionTimeout, so reset r.electionElapsed.
 		r.electionElapsed = 0
 		r.leadTransferee = leadTransferee
@@ -1678,6 +2031,7 @@ func stepCandidate(r *raft, m pb.Message) error {
 	switch m.Type {
 	case pb.MsgProp:
 		r.logger.Infof("%x no leader at term %d; dropping proposal", r.id, r.Term)
+		r.metrics.ProposalDropped(ProposalDropNoLeader)
 		return ErrProposalDropped
 	case pb.MsgApp:
 		r.becomeFollower(m.Term, m.From) // always m.Term == r.Term
@@ -1693,6 +2047,7 @@ func stepCandidate(r *raft, m pb.Message) error {
 		r.logger.Infof("%x has received %d %s votes and %d vote rejections", r.id, gr, m.Type, rj)
 		switch res {
 		case quorum.VoteWon:
+			r.metrics.ElectionWon(r.state == StatePreCandidate)
 			if r.state == StatePreCandidate {
 				r.campaign(campaignElection)
 			} else {
@@ -1715,9 +2070,11 @@ func stepFollower(r *raft, m pb.Message) error {
 	case pb.MsgProp:
 		if r.lead == None {
 			r.logger.Infof("%x no leader at term %d; dropping proposal", r.id, r.Term)
+			r.metrics.ProposalDropped(ProposalDropNoLeader)
 			return ErrProposalDropped
 		} else if r.disableProposalForwarding {
 			r.logger.Infof("%x not forwarding to leader %x at term %d; dropping proposal", r.id, r.lead, r.Term)
+			r.metrics.ProposalDropped(ProposalDropForwardingDisabled)
 			return ErrProposalDropped
 		}
 		m.To = r.lead
@@ -1787,6 +2144,14 @@ func (r *raft) handleAppendEntries(m pb.Message) {
 	// TODO(pav-kv): construct logSlice up the stack next to receiving the
 	// message, and validate it before taking any action (e.g. bumping term).
 	a := logSliceFromMsgApp(&m)
+	if e, ok := verifyEntries(a.entries); !ok {
+		// Drop the message. The leader retries, and the entries will hopefully
+		// arrive intact then.
+		r.peerLogger(m.From, slog.Uint64("index", e.Index)).Errorf("%x [term %d] dropping append from %x: entry %d/%d fails its checksum",
+			r.id, r.Term, m.From, e.Term, e.Index)
+		return
+	}
+	r.maybeAdvanceSafeRead(m)
 
 	if a.prev.index < r.raftLog.committed {
 		r.send(pb.Message{To: m.From, Type: pb.MsgAppResp, Index: r.raftLog.committed})
@@ -1796,7 +2161,7 @@ func (r *raft) handleAppendEntries(m pb.Message) {
 		r.send(pb.Message{To: m.From, Type: pb.MsgAppResp, Index: mlastIndex})
 		return
 	}
-	r.logger.Debugf("%x [logterm: %d, index: %d] rejected MsgApp [logterm: %d, index: %d] from %x",
+	r.peerLogger(m.From, slog.Uint64("index", m.Index)).Debugf("%x [logterm: %d, index: %d] rejected MsgApp [logterm: %d, index: %d] from %x",
 		r.id, r.raftLog.zeroTermOnOutOfBounds(r.raftLog.term(m.Index)), m.Index, m.LogTerm, m.Index, m.From)
 
 	// Our log does not match the leader's at index m.Index. Return a hint to the
@@ -1829,7 +2194,19 @@ func (r *raft) handleAppendEntries(m pb.Message) {
 
 func (r *raft) handleHeartbeat(m pb.Message) {
 	r.raftLog.commitTo(m.Commit)
-	r.send(pb.Message{To: m.From, Type: pb.MsgHeartbeatResp, Context: m.Context})
+	r.maybeAdvanceSafeRead(m)
+	resp := pb.Message{To: m.From, Type: pb.MsgHeartbeatResp, Context: m.Context, Quiesce: m.Quiesce}
+	if m.Quiesce {
+		r.logger.Debugf("%x quiescing at term %d on heartbeat from %x", r.id, r.Term, m.From)
+		r.quiesced = true
+	}
+	if r.lease.numbered() {
+		resp.Index = m.Index
+	}
+	if r.lease.enabled() {
+		r.lease.promise(m.From)
+	}
+	r.send(resp)
 }
 
 func (r *raft) handleSnapshot(m pb.Message) {
@@ -1932,16 +2309,64 @@ func (r *raft) restore(s pb.Snapshot) bool {
 	assertConfStatesEquivalent(r.logger, cs, r.switchToConfig(cfg, trk))
 
 	last := r.raftLog.lastEntryID()
-	r.logger.Infof("%x [commit: %d, lastindex: %d, lastterm: %d] restored snapshot [index: %d, term: %d]",
+	r.peerLogger(None, slog.Uint64("index", id.index)).Infof("%x [commit: %d, lastindex: %d, lastterm: %d] restored snapshot [index: %d, term: %d]",
 		r.id, r.raftLog.committed, last.index, last.term, id.index, id.term)
 	return true
 }
 
+// votersWeight returns the total weight of the given voters of a ConfState.
+func votersWeight(voters []uint64, weights []pb.VoterWeight) uint64 {
+	w := make(quorum.Weights, len(weights))
+	for _, vw := range weights {
+		w[vw.NodeID] = vw.Weight
+	}
+	var total uint64
+	for _, id := range voters {
+		total += w.Weight(id)
+	}
+	return total
+}
+
 // promotable indicates whether state machine can be promoted to leader,
-// which is true when its own id is in progress list.
+// which is true when its own id is in progress list. Witnesses don't store
+// entry payloads and can never be promoted.
 func (r *raft) promotable() bool {
 	pr := r.trk.Progress[r.id]
-	return pr != nil && !pr.IsLearner && !r.raftLog.hasNextOrInProgressSnapshot()
+	return pr != nil && !pr.IsLearner && !pr.IsWitness && !r.raftLog.hasNextOrInProgressSnapshot()
+}
+
+// checkConfChange returns an error if the Changer would refuse to apply the
+// conf change because it is a simple change involving weighted voters,
+// because the incoming voters would no longer tolerate the loss of a zone, or
+// because ElectionQuorum and ReplicationQuorum would no longer suit them.
+// Since no other conf change can be pending, the change will apply to the
+// current config.
+func (r *raft) checkConfChange(cc pb.ConfChangeV2) error {
+	if cc.LeaveJoint() {
+		return nil
+	}
+	autoLeave, joint := cc.EnterJoint()
+	weighted := len(r.trk.Weights[0]) > 0
+	zoned := len(r.trk.Zones) > 0
+	for _, c := range cc.Changes {
+		weighted = weighted || c.Weight > 1
+		zoned = zoned || c.Zone != ""
+	}
+	flexible := r.trk.Quorum != (quorum.FlexibleQuorum{})
+	if !(weighted && !joint) && !zoned && !flexible {
+		return nil
+	}
+	changer := confchange.Changer{
+		Tracker:   r.trk,
+		LastIndex: r.raftLog.lastIndex(),
+	}
+	var err error
+	if joint {
+		_, _, err = changer.EnterJoint(autoLeave, cc.Changes...)
+	} else {
+		_, _, err = changer.Simple(cc.Changes...)
+	}
+	return err
 }
 
 func (r *raft) applyConfChange(cc pb.ConfChangeV2) pb.ConfState {
@@ -1979,6 +2404,16 @@ func (r *raft) switchToConfig(cfg tracker.Config, trk tracker.ProgressMap) pb.Co
 	r.trk.Progress = trk
 
 	r.logger.Infof("%x switched to configuration %s", r.id, r.trk.Config)
+	for i, voters := range r.trk.Voters {
+		if len(voters) == 0 {
+			continue
+		}
+		// Config.validate and the Changer refuse the other configs, so this
+		// only happens while a config is built up one voter at a time.
+		if err := r.trk.Quorum.Validate(voters.TotalWeight(r.trk.Weights[i])); err != nil {
+			r.logger.Infof("%x uses majority quorums for voters %s until they suit %s", r.id, voters, r.trk.Quorum)
+		}
+	}
 	cs := r.trk.ConfState()
 	pr, ok := r.trk.Progress[r.id]
 
@@ -2048,6 +2483,48 @@ func (r *raft) pastElectionTimeout() bool {
 
 func (r *raft) resetRandomizedElectionTimeout() {
 	r.randomizedElectionTimeout = r.electionTimeout + globalRand.Intn(r.electionTimeout)
+	// Voters with a lower priority wait an extra election timeout for each
+	// level of priority above theirs, which gives the higher priority ones a
+	// head start in campaigning.
+	r.randomizedElectionTimeout += r.electionTimeout * r.priorityRank()
+}
+
+// priorityRank returns the number of distinct priorities higher than that of
+// this peer among the voters that can become leader.
+func (r *raft) priorityRank() int {
+	own := r.trk.Priorities[r.id]
+	var higher []uint64
+	for id := range r.trk.Voters.IDs() {
+		if p := r.trk.Priorities[id]; p > own && !slices.Contains(higher, p) {
+			if _, isWitness := r.trk.Witnesses[id]; !isWitness {
+				higher = append(higher, p)
+			}
+		}
+	}
+	return len(higher)
+}
+
+// maybeTransferToPreferred transfers leadership to the given voter if it has
+// the highest priority among the voters, a higher one than the leader, and
+// an up-to-date log.
+func (r *raft) maybeTransferToPreferred(id uint64) {
+	priority := r.trk.Priorities[id]
+	if r.leadTransferee != None || priority <= r.trk.Priorities[r.id] {
+		return
+	}
+	pr := r.trk.Progress[id]
+	if pr == nil || pr.IsLearner || pr.IsWitness || pr.Match != r.raftLog.lastIndex() {
+		return
+	}
+	for voter := range r.trk.Voters.IDs() {
+		if r.trk.Priorities[voter] > priority {
+			return
+		}
+	}
+	r.logger.Infof("%x [term %d] prefers %x with priority %d as leader", r.id, r.Term, id, priority)
+	if err := r.Step(pb.Message{Type: pb.MsgTransferLeader, From: id}); err != nil {
+		r.logger.Debugf("error occurred during transferring leadership to %x: %v", id, err)
+	}
 }
 
 func (r *raft) sendTimeoutNow(to uint64) {
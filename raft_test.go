@@ -20,6 +20,7 @@ import (
 	"math/rand"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -3296,6 +3297,177 @@ func TestTransferNonMember(t *testing.T) {
 	require.Equal(t, StateFollower, r.state)
 }
 
+// TestElectionTimeoutPriority verifies that voters with a lower priority wait
+// an extra election timeout for each level of priority above theirs.
+func TestElectionTimeoutPriority(t *testing.T) {
+	et := 10
+	withPriorities := func(ms *MemoryStorage) {
+		ms.snapshot.Metadata.ConfState.Priorities = []pb.NodePriority{
+			{NodeID: 2, Priority: 1}, {NodeID: 3, Priority: 2}, {NodeID: 4, Priority: 2},
+		}
+	}
+	for _, tt := range []struct {
+		id   uint64
+		rank int
+	}{
+		{1, 2},
+		{2, 1},
+		{3, 0},
+		{4, 0},
+	} {
+		r := newTestRaft(tt.id, et, 1, newTestMemoryStorage(withPeers(1, 2, 3, 4), withPriorities))
+		for i := 0; i < 10*et; i++ {
+			r.resetRandomizedElectionTimeout()
+			require.GreaterOrEqual(t, r.randomizedElectionTimeout, (1+tt.rank)*et)
+			require.Less(t, r.randomizedElectionTimeout, (2+tt.rank)*et)
+		}
+	}
+}
+
+type testClock struct{ now time.Time }
+
+func (c *testClock) Now() time.Time { return c.now }
+
+// TestLeasePromise verifies that a follower which acknowledged a heartbeat
+// refuses to vote for other candidates until the lease duration has passed,
+// unless the candidate campaigns to take over a leadership transfer.
+func TestLeasePromise(t *testing.T) {
+	for _, tt := range []struct {
+		elapsed time.Duration
+		ctx     []byte
+		granted bool
+	}{
+		{0, nil, false},
+		{999 * time.Millisecond, nil, false},
+		{time.Second, nil, true},
+		{0, []byte(campaignTransfer), true},
+	} {
+		t.Run("", func(t *testing.T) {
+			clock := &testClock{now: time.Unix(0, 0)}
+			cfg := newTestConfig(1, 10, 1, newTestMemoryStorage(withPeers(1, 2, 3)))
+			cfg.LeaseDuration, cfg.MaxClockOffset, cfg.Clock = time.Second, 100*time.Millisecond, clock
+			r := newRaft(cfg)
+			// Outlast the promise made when starting.
+			clock.now = clock.now.Add(time.Second)
+
+			r.becomeFollower(1, 2)
+			require.NoError(t, r.Step(pb.Message{From: 2, To: 1, Term: 1, Type: pb.MsgHeartbeat, Index: 5}))
+			msgs := r.readMessages()
+			require.Len(t, msgs, 1)
+			require.Equal(t, pb.MsgHeartbeatResp, msgs[0].Type)
+			require.Equal(t, uint64(5), msgs[0].Index)
+
+			clock.now = clock.now.Add(tt.elapsed)
+			require.NoError(t, r.Step(pb.Message{From: 3, To: 1, Term: 2, Type: pb.MsgVote, LogTerm: 1, Index: 11, Context: tt.ctx}))
+			r.advanceMessagesAfterAppend()
+			msgs = r.readMessages()
+			if !tt.granted {
+				require.Empty(t, msgs)
+				require.Equal(t, uint64(1), r.Term)
+				return
+			}
+			require.Len(t, msgs, 1)
+			require.Equal(t, pb.MsgVoteResp, msgs[0].Type)
+			require.False(t, msgs[0].Reject)
+		})
+	}
+}
+
+// TestEntryChecksums verifies that the leader checksums the entries it appends
+// when configured to, and that followers drop appends with corrupted entries.
+func TestEntryChecksums(t *testing.T) {
+	cfg := newTestConfig(1, 10, 1, newTestMemoryStorage(withPeers(1)))
+	cfg.EntryChecksums = true
+	r := newRaft(cfg)
+	r.becomeCandidate()
+	r.becomeLeader()
+	require.NoError(t, r.Step(pb.Message{From: 1, To: 1, Type: pb.MsgProp, Entries: []pb.Entry{{Data: []byte("foo")}}}))
+	ents := r.raftLog.nextUnstableEnts()
+	require.Len(t, ents, 2)
+	for _, e := range ents {
+		require.NotZero(t, e.Checksum)
+		require.Equal(t, entryChecksum(e), e.Checksum)
+	}
+
+	for _, tt := range []struct {
+		corrupt bool
+		wIndex  uint64
+	}{
+		{false, 2},
+		{true, 0},
+	} {
+		t.Run("", func(t *testing.T) {
+			ents := []pb.Entry{
+				{Term: 1, Index: 1},
+				{Term: 1, Index: 2, Data: []byte("foo")},
+			}
+			for i := range ents {
+				ents[i].Checksum = entryChecksum(ents[i])
+			}
+			if tt.corrupt {
+				ents[1].Data = []byte("fob")
+			}
+			r := newTestRaft(2, 10, 1, newTestMemoryStorage(withPeers(1, 2)))
+			r.becomeFollower(1, 1)
+			require.NoError(t, r.Step(pb.Message{From: 1, To: 2, Term: 1, Type: pb.MsgApp, Entries: ents}))
+			require.Equal(t, tt.wIndex, r.raftLog.lastIndex())
+			if tt.corrupt {
+				require.Empty(t, r.msgsAfterAppend)
+			}
+		})
+	}
+}
+
+// TestCommittedEntryChecksum verifies that a corrupted committed entry is never
+// handed out to the application.
+func TestCommittedEntryChecksum(t *testing.T) {
+	e := pb.Entry{Term: 1, Index: 1, Data: []byte("foo")}
+	e.Checksum = entryChecksum(e)
+	e.Data = []byte("fob")
+	storage := newTestMemoryStorage(withPeers(1))
+	require.NoError(t, storage.Append([]pb.Entry{e}))
+	require.NoError(t, storage.SetHardState(pb.HardState{Term: 1, Commit: 1}))
+	r := newTestRaft(1, 10, 1, storage)
+	require.Panics(t, func() { r.raftLog.nextCommittedEnts(true) })
+}
+
+// TestHashMismatch verifies that a mismatch between the hash reported by the
+// application and the one of the leader is reported, regardless of the order
+// in which they are learned.
+func TestHashMismatch(t *testing.T) {
+	verdict := func(index, hash uint64) pb.Entry {
+		m := stateHashMsg(index, hash)
+		return pb.Entry{Term: 1, Index: index + 1, Type: pb.EntryHashCheck, Data: m.Entries[0].Data}
+	}
+	for _, tt := range []struct {
+		hash, leaderHash uint64
+		leaderFirst      bool
+		wMismatch        []HashMismatch
+	}{
+		{1, 1, false, nil},
+		{1, 1, true, nil},
+		{1, 2, false, []HashMismatch{{Index: 5, Hash: 1, LeaderHash: 2}}},
+		{1, 2, true, []HashMismatch{{Index: 5, Hash: 1, LeaderHash: 2}}},
+	} {
+		t.Run("", func(t *testing.T) {
+			var mismatches []HashMismatch
+			cfg := newTestConfig(2, 10, 1, newTestMemoryStorage(withPeers(1, 2)))
+			cfg.OnHashMismatch = func(hm HashMismatch) { mismatches = append(mismatches, hm) }
+			r := newRaft(cfg)
+			if tt.leaderFirst {
+				r.checkHashes([]pb.Entry{verdict(5, tt.leaderHash)})
+				require.NoError(t, r.Step(stateHashMsg(5, tt.hash)))
+			} else {
+				require.NoError(t, r.Step(stateHashMsg(5, tt.hash)))
+				r.checkHashes([]pb.Entry{verdict(5, tt.leaderHash)})
+			}
+			require.Equal(t, tt.wMismatch, mismatches)
+			require.Empty(t, r.hashCheck.local)
+			require.Empty(t, r.hashCheck.leader)
+		})
+	}
+}
+
 // TestNodeWithSmallerTermCanCompleteElection tests the scenario where a node
 // that has been partitioned away (and fallen behind) rejoins the cluster at
 // about the same time the leader node gets partitioned away.
@@ -4151,3 +4323,30 @@ func newTestRawNode(id uint64, election, heartbeat int, storage Storage) *RawNod
 	}
 	return rn
 }
+
+// TestConfigValidateFlexibleQuorum checks that a node doesn't start with
+// flexible quorums that don't suit its initial voters.
+func TestConfigValidateFlexibleQuorum(t *testing.T) {
+	for _, tt := range []struct {
+		peers []uint64
+		ok    bool
+	}{
+		{nil, true},
+		{[]uint64{1, 2, 3}, false},
+		{[]uint64{1, 2, 3, 4}, true},
+		{[]uint64{1, 2, 3, 4, 5}, true},
+		{[]uint64{1, 2, 3, 4, 5, 6, 7, 8}, false},
+	} {
+		var opts []testMemoryStorageOptions
+		if len(tt.peers) > 0 {
+			opts = append(opts, withPeers(tt.peers...))
+		}
+		cfg := newTestConfig(1, 10, 1, newTestMemoryStorage(opts...))
+		cfg.ElectionQuorum, cfg.ReplicationQuorum = 4, 2
+		if tt.ok {
+			assert.NoError(t, cfg.validate(), "%v", tt.peers)
+		} else {
+			assert.Error(t, cfg.validate(), "%v", tt.peers)
+		}
+	}
+}
@@ -116,8 +116,15 @@ func (c ConfChangeV2) LeaveJoint() bool {
 // slice of ConfChangeSingle. The supported operations are:
 // - vn: make n a voter,
 // - ln: make n a learner,
+// - wn: make n a witness,
 // - rn: remove n, and
 // - un: update n.
+//
+// Voters and witnesses can be given a voting weight by appending it to the
+// operation, e.g. v1:3 makes 1 a voter with weight 3. Nodes added by any of
+// the first three operations can be placed in a zone by appending @zone, e.g.
+// v1:3@east or l2@west. Voters and learners can be given an election priority
+// by appending ^priority last, e.g. v1@east^2.
 func ConfChangesFromString(s string) ([]ConfChangeSingle, error) {
 	var ccs []ConfChangeSingle
 	toks := strings.Split(strings.TrimSpace(s), " ")
@@ -129,11 +136,14 @@ func ConfChangesFromString(s string) ([]ConfChangeSingle, error) {
 			return nil, fmt.Errorf("unknown token %s", tok)
 		}
 		var cc ConfChangeSingle
+		var err error
 		switch tok[0] {
 		case 'v':
 			cc.Type = ConfChangeAddNode
 		case 'l':
 			cc.Type = ConfChangeAddLearnerNode
+		case 'w':
+			cc.Type = ConfChangeAddWitness
 		case 'r':
 			cc.Type = ConfChangeRemoveNode
 		case 'u':
@@ -141,11 +151,39 @@ func ConfChangesFromString(s string) ([]ConfChangeSingle, error) {
 		default:
 			return nil, fmt.Errorf("unknown input: %s", tok)
 		}
-		id, err := strconv.ParseUint(tok[1:], 10, 64)
+		rest, priorityStr, prioritized := strings.Cut(tok[1:], "^")
+		if prioritized {
+			if cc.Type != ConfChangeAddNode && cc.Type != ConfChangeAddLearnerNode {
+				return nil, fmt.Errorf("only voters and learners can have a priority: %s", tok)
+			}
+			if cc.Priority, err = strconv.ParseUint(priorityStr, 10, 64); err != nil {
+				return nil, err
+			}
+		}
+		rest, zone, zoned := strings.Cut(rest, "@")
+		if zoned {
+			if cc.Type != ConfChangeAddNode && cc.Type != ConfChangeAddLearnerNode && cc.Type != ConfChangeAddWitness {
+				return nil, fmt.Errorf("only added nodes can have a zone: %s", tok)
+			}
+			if zone == "" {
+				return nil, fmt.Errorf("empty zone: %s", tok)
+			}
+			cc.Zone = zone
+		}
+		idStr, weightStr, weighted := strings.Cut(rest, ":")
+		id, err := strconv.ParseUint(idStr, 10, 64)
 		if err != nil {
 			return nil, err
 		}
 		cc.NodeID = id
+		if weighted {
+			if cc.Type != ConfChangeAddNode && cc.Type != ConfChangeAddWitness {
+				return nil, fmt.Errorf("only voters and witnesses can have a weight: %s", tok)
+			}
+			if cc.Weight, err = strconv.ParseUint(weightStr, 10, 64); err != nil {
+				return nil, err
+			}
+		}
 		ccs = append(ccs, cc)
 	}
 	return ccs, nil
@@ -163,6 +201,8 @@ func ConfChangesToString(ccs []ConfChangeSingle) string {
 			buf.WriteByte('v')
 		case ConfChangeAddLearnerNode:
 			buf.WriteByte('l')
+		case ConfChangeAddWitness:
+			buf.WriteByte('w')
 		case ConfChangeRemoveNode:
 			buf.WriteByte('r')
 		case ConfChangeUpdateNode:
@@ -171,6 +211,15 @@ func ConfChangesToString(ccs []ConfChangeSingle) string {
 			buf.WriteString("unknown")
 		}
 		fmt.Fprintf(&buf, "%d", cc.NodeID)
+		if cc.Weight != 0 {
+			fmt.Fprintf(&buf, ":%d", cc.Weight)
+		}
+		if cc.Zone != "" {
+			fmt.Fprintf(&buf, "@%s", cc.Zone)
+		}
+		if cc.Priority != 0 {
+			fmt.Fprintf(&buf, "^%d", cc.Priority)
+		}
 	}
 	return buf.String()
 }
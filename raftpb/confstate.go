@@ -15,6 +15,7 @@
 package raftpb
 
 import (
+	"cmp"
 	"fmt"
 	"reflect"
 	"slices"
@@ -29,12 +30,54 @@ func (cs ConfState) Equivalent(cs2 ConfState) error {
 		*sl = append([]uint64(nil), *sl...)
 		slices.Sort(*sl)
 	}
+	// Weights of zero and one both stand for the default weight.
+	sw := func(sl *[]VoterWeight) {
+		*sl = slices.DeleteFunc(append([]VoterWeight(nil), *sl...), func(w VoterWeight) bool {
+			return w.Weight <= 1
+		})
+		if len(*sl) == 0 {
+			*sl = nil
+		}
+		slices.SortFunc(*sl, func(a, b VoterWeight) int {
+			return cmp.Compare(a.NodeID, b.NodeID)
+		})
+	}
+	// An empty zone stands for no zone.
+	sz := func(sl *[]NodeZone) {
+		*sl = slices.DeleteFunc(append([]NodeZone(nil), *sl...), func(z NodeZone) bool {
+			return z.Zone == ""
+		})
+		if len(*sl) == 0 {
+			*sl = nil
+		}
+		slices.SortFunc(*sl, func(a, b NodeZone) int {
+			return cmp.Compare(a.NodeID, b.NodeID)
+		})
+	}
+
+	// A priority of zero is the default.
+	sp := func(sl *[]NodePriority) {
+		*sl = slices.DeleteFunc(append([]NodePriority(nil), *sl...), func(p NodePriority) bool {
+			return p.Priority == 0
+		})
+		if len(*sl) == 0 {
+			*sl = nil
+		}
+		slices.SortFunc(*sl, func(a, b NodePriority) int {
+			return cmp.Compare(a.NodeID, b.NodeID)
+		})
+	}
 
 	for _, cs := range []*ConfState{&cs1, &cs2} {
 		s(&cs.Voters)
 		s(&cs.Learners)
 		s(&cs.VotersOutgoing)
 		s(&cs.LearnersNext)
+		s(&cs.Witnesses)
+		sw(&cs.Weights)
+		sw(&cs.WeightsOutgoing)
+		sz(&cs.Zones)
+		sp(&cs.Priorities)
 	}
 
 	if !reflect.DeepEqual(cs1, cs2) {
@@ -46,6 +46,30 @@ func TestConfState_Equivalent(t *testing.T) {
 		{ConfState{Voters: []uint64{1, 4, 3}}, ConfState{Voters: []uint64{2, 1, 3}}, false},
 		// Non-equivalent learners.
 		{ConfState{Voters: []uint64{1, 2, 3, 4}}, ConfState{Voters: []uint64{2, 1, 3}}, false},
+		// Reordered and non-equivalent witnesses.
+		{ConfState{Voters: []uint64{1, 2, 3}, Witnesses: []uint64{3, 2}}, ConfState{Voters: []uint64{1, 2, 3}, Witnesses: []uint64{2, 3}}, true},
+		{ConfState{Voters: []uint64{1, 2, 3}, Witnesses: []uint64{3}}, ConfState{Voters: []uint64{1, 2, 3}}, false},
+		// Reordered and non-equivalent weights.
+		{ConfState{Voters: []uint64{1, 2, 3}, Weights: []VoterWeight{{NodeID: 3, Weight: 2}, {NodeID: 1, Weight: 3}}},
+			ConfState{Voters: []uint64{1, 2, 3}, Weights: []VoterWeight{{NodeID: 1, Weight: 3}, {NodeID: 3, Weight: 2}}}, true},
+		{ConfState{Voters: []uint64{1, 2, 3}, Weights: []VoterWeight{{NodeID: 1, Weight: 3}}},
+			ConfState{Voters: []uint64{1, 2, 3}, WeightsOutgoing: []VoterWeight{{NodeID: 1, Weight: 3}}}, false},
+		{ConfState{Voters: []uint64{1, 2}, Weights: []VoterWeight{{NodeID: 1, Weight: 1}, {NodeID: 2, Weight: 0}}},
+			ConfState{Voters: []uint64{1, 2}}, true},
+		// Reordered and non-equivalent zones.
+		{ConfState{Voters: []uint64{1, 2}, Zones: []NodeZone{{NodeID: 2, Zone: "b"}, {NodeID: 1, Zone: "a"}}},
+			ConfState{Voters: []uint64{1, 2}, Zones: []NodeZone{{NodeID: 1, Zone: "a"}, {NodeID: 2, Zone: "b"}}}, true},
+		{ConfState{Voters: []uint64{1, 2}, Zones: []NodeZone{{NodeID: 1, Zone: "a"}}},
+			ConfState{Voters: []uint64{1, 2}, Zones: []NodeZone{{NodeID: 1, Zone: "b"}}}, false},
+		{ConfState{Voters: []uint64{1, 2}, Zones: []NodeZone{{NodeID: 1, Zone: ""}}},
+			ConfState{Voters: []uint64{1, 2}}, true},
+		// Reordered and non-equivalent priorities.
+		{ConfState{Voters: []uint64{1, 2}, Priorities: []NodePriority{{NodeID: 2, Priority: 1}, {NodeID: 1, Priority: 2}}},
+			ConfState{Voters: []uint64{1, 2}, Priorities: []NodePriority{{NodeID: 1, Priority: 2}, {NodeID: 2, Priority: 1}}}, true},
+		{ConfState{Voters: []uint64{1, 2}, Priorities: []NodePriority{{NodeID: 1, Priority: 2}}},
+			ConfState{Voters: []uint64{1, 2}, Priorities: []NodePriority{{NodeID: 2, Priority: 2}}}, false},
+		{ConfState{Voters: []uint64{1, 2}, Priorities: []NodePriority{{NodeID: 1, Priority: 0}}},
+			ConfState{Voters: []uint64{1, 2}}, true},
 		// Sensitive to AutoLeave flag.
 		{ConfState{AutoLeave: true}, ConfState{}, false},
 	}
@@ -0,0 +1,628 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package raftstore implements a durable raft.Storage backed by files in a
+// directory.
+//
+// Besides the methods of raft.Storage, Storage has the methods of
+// raft.MemoryStorage used to write to it (SetHardState, Append, ApplySnapshot,
+// CreateSnapshot and Compact), and can be used in its place.
+//
+// The log is split into segment files of about Options.SegmentSize bytes,
+// named after the index of their first entry. Each entry is stored as a
+// record: its length and CRC-32C checksum, followed by the entry. Only the
+// terms and file offsets of the entries are kept in memory, and Entries reads
+// the entries from the segments. Compact deletes the segments holding only
+// compacted entries.
+//
+// The HardState, the last compacted entry and a reference to the latest
+// snapshot are kept in a state file, which is replaced atomically and ties
+// the other files together. Each snapshot, including its ConfState, is stored
+// in a file of its own. Applying a snapshot starts a new generation of
+// segments, so that the segments of the replaced log are ignored if a crash
+// prevents their deletion.
+//
+// Options.Sync decides when writes are synced to stable storage. With
+// SyncAlways, the default, every method returns once its writes are durable,
+// as raft expects. On opening the storage, a record failing its checksum is
+// an error, unless Options.Recovery is RecoverTornWrites and the record is at
+// the end of the log, in which case it and the records after it are assumed
+// to stem from a write torn by a crash, and are dropped.
+package raftstore
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go.etcd.io/raft/v3"
+	pb "go.etcd.io/raft/v3/raftpb"
+)
+
+// SyncPolicy decides when writes are synced to stable storage.
+type SyncPolicy int
+
+const (
+	// SyncAlways syncs all writes before returning.
+	SyncAlways SyncPolicy = iota
+	// SyncInterval syncs appended entries at most once per
+	// Options.SyncInterval, and when calling Sync or Close, and all other
+	// writes before returning. Entries appended since the last sync may be
+	// lost in a crash, which violates the expectations of raft, and must
+	// only be used if the application can tolerate it. The entries replaced
+	// by an append are still dropped durably before it returns.
+	SyncInterval
+	// SyncNever leaves syncing to the operating system. Meant for tests.
+	SyncNever
+)
+
+// RecoveryMode decides how records failing their checksum are handled when
+// opening the storage.
+type RecoveryMode int
+
+const (
+	// RecoverStrict fails to open the storage.
+	RecoverStrict RecoveryMode = iota
+	// RecoverTornWrites drops the records from the first one failing its
+	// checksum or being truncated in the last segment, which is the result
+	// of a write torn by a crash. Other records failing their checksum fail
+	// to open the storage.
+	RecoverTornWrites
+)
+
+// Options configures a Storage.
+type Options struct {
+	// SegmentSize is the size from which a new segment file is started.
+	// Defaults to 64 MiB.
+	SegmentSize int64
+	// Sync is the sync policy. Defaults to SyncAlways.
+	Sync SyncPolicy
+	// SyncInterval is the interval between syncs with SyncInterval. Defaults
+	// to 100ms.
+	SyncInterval time.Duration
+	// Recovery is the recovery mode. Defaults to RecoverStrict.
+	Recovery RecoveryMode
+}
+
+const (
+	defaultSegmentSize  = 64 << 20
+	defaultSyncInterval = 100 * time.Millisecond
+
+	stateFile = "state"
+)
+
+// entryPos is the position of an entry in the segments.
+type entryPos struct {
+	term uint64
+	seg  *segment
+	off  int64
+	size int
+}
+
+// Storage is a raft.Storage backed by files. It is safe for concurrent use.
+// After an error writing to it, a Storage must be closed and opened again.
+type Storage struct {
+	mu   sync.Mutex
+	dir  string
+	opts Options
+
+	st   state
+	snap pb.Snapshot
+	// ents[i] is the position of the entry at index st.compactedIndex+1+i.
+	ents []entryPos
+	// segs holds the segments of the log in increasing order.
+	segs []*segment
+	// unsynced holds the segments written since the last sync.
+	unsynced []*segment
+	lastSync time.Time
+}
+
+var _ raft.Storage = (*Storage)(nil)
+
+// Open opens the storage in the given directory, creating it if needed.
+func Open(dir string, opts Options) (*Storage, error) {
+	if opts.SegmentSize <= 0 {
+		opts.SegmentSize = defaultSegmentSize
+	}
+	if opts.SyncInterval <= 0 {
+		opts.SyncInterval = defaultSyncInterval
+	}
+	if opts.Sync < SyncAlways || opts.Sync > SyncNever {
+		return nil, fmt.Errorf("raftstore: invalid sync policy %d", opts.Sync)
+	}
+	if opts.Recovery < RecoverStrict || opts.Recovery > RecoverTornWrites {
+		return nil, fmt.Errorf("raftstore: invalid recovery mode %d", opts.Recovery)
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	s := &Storage{dir: dir, opts: opts, lastSync: time.Now()}
+	if err := s.load(); err != nil {
+		for _, seg := range s.segs {
+			if seg.f != nil {
+				seg.f.Close()
+			}
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+func snapshotName(term, index uint64) string {
+	return fmt.Sprintf("%016x-%016x.snap", term, index)
+}
+
+// load reads the files of the storage, and deletes those left behind by
+// operations interrupted by a crash.
+func (s *Storage) load() error {
+	switch buf, err := readFile(filepath.Join(s.dir, stateFile)); {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return err
+	default:
+		if err := s.st.unmarshal(buf); err != nil {
+			return err
+		}
+	}
+	snapFile := ""
+	if s.st.snapIndex > 0 {
+		snapFile = snapshotName(s.st.snapTerm, s.st.snapIndex)
+		buf, err := readFile(filepath.Join(s.dir, snapFile))
+		if err != nil {
+			return err
+		}
+		if err := s.snap.Unmarshal(buf); err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrCorrupt, snapFile, err)
+		}
+	}
+
+	files, err := os.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+	for _, fi := range files {
+		name := fi.Name()
+		stale := strings.HasSuffix(name, ".tmp") || (strings.HasSuffix(name, ".snap") && name != snapFile)
+		if epoch, first, ok := parseSegmentName(name); ok {
+			if epoch == s.st.epoch {
+				s.segs = append(s.segs, &segment{epoch: epoch, first: first, path: filepath.Join(s.dir, name)})
+				continue
+			}
+			stale = true
+		}
+		if stale {
+			if err := os.Remove(filepath.Join(s.dir, name)); err != nil {
+				return err
+			}
+		}
+	}
+	slices.SortFunc(s.segs, func(a, b *segment) int { return cmp.Compare(a.first, b.first) })
+
+	for i, seg := range s.segs {
+		if seg.f, err = os.OpenFile(seg.path, os.O_RDWR, 0); err != nil {
+			return err
+		}
+		seg.last = seg.first - 1
+		off, err := seg.scan(func(e pb.Entry, off int64, size int) error {
+			if e.Index != seg.last+1 {
+				return fmt.Errorf("%w: found entry %d after %d", ErrCorrupt, e.Index, seg.last)
+			}
+			seg.last = e.Index
+			if e.Index <= s.st.compactedIndex {
+				return nil
+			}
+			if last := s.lastIndex(); e.Index != last+1 {
+				return fmt.Errorf("%w: missing entries between %d and %d", ErrCorrupt, last, e.Index)
+			}
+			s.ents = append(s.ents, entryPos{term: e.Term, seg: seg, off: off, size: size})
+			return nil
+		})
+		seg.size = off
+		if err == nil {
+			continue
+		}
+		torn := errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, errChecksum)
+		if !torn || s.opts.Recovery != RecoverTornWrites || i != len(s.segs)-1 {
+			return fmt.Errorf("%s: %w", seg.path, err)
+		}
+		if err := seg.truncate(off, seg.last); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InitialState implements the raft.Storage interface.
+func (s *Storage) InitialState() (pb.HardState, pb.ConfState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.st.hardState, s.snap.Metadata.ConfState, nil
+}
+
+// SetHardState saves the current HardState.
+func (s *Storage) SetHardState(st pb.HardState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	prev := s.st
+	s.st.hardState = st
+	return s.writeState(prev)
+}
+
+// Entries implements the raft.Storage interface.
+func (s *Storage) Entries(lo, hi, maxSize uint64) ([]pb.Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if lo <= s.st.compactedIndex {
+		return nil, raft.ErrCompacted
+	}
+	if hi > s.lastIndex()+1 {
+		panic(fmt.Sprintf("entries' hi(%d) is out of bound lastindex(%d)", hi, s.lastIndex()))
+	}
+	if len(s.ents) == 0 {
+		return nil, raft.ErrUnavailable
+	}
+	var ents []pb.Entry
+	var size uint64
+	for i := lo; i < hi; i++ {
+		pos := s.ents[i-s.st.compactedIndex-1]
+		if size += uint64(pos.size); len(ents) > 0 && size > maxSize {
+			break
+		}
+		e, err := pos.seg.read(pos.off)
+		if err != nil {
+			return nil, err
+		}
+		ents = append(ents, e)
+	}
+	return ents, nil
+}
+
+// Term implements the raft.Storage interface.
+func (s *Storage) Term(i uint64) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.term(i)
+}
+
+func (s *Storage) term(i uint64) (uint64, error) {
+	switch {
+	case i < s.st.compactedIndex:
+		return 0, raft.ErrCompacted
+	case i == s.st.compactedIndex:
+		return s.st.compactedTerm, nil
+	case i > s.lastIndex():
+		return 0, raft.ErrUnavailable
+	}
+	return s.ents[i-s.st.compactedIndex-1].term, nil
+}
+
+// LastIndex implements the raft.Storage interface.
+func (s *Storage) LastIndex() (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastIndex(), nil
+}
+
+func (s *Storage) lastIndex() uint64 {
+	return s.st.compactedIndex + uint64(len(s.ents))
+}
+
+// FirstIndex implements the raft.Storage interface.
+func (s *Storage) FirstIndex() (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.st.compactedIndex + 1, nil
+}
+
+// Snapshot implements the raft.Storage interface.
+func (s *Storage) Snapshot() (pb.Snapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.snap, nil
+}
+
+// ApplySnapshot overwrites the contents of the storage with those of the
+// given snapshot.
+func (s *Storage) ApplySnapshot(snap pb.Snapshot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.st.snapIndex >= snap.Metadata.Index {
+		return raft.ErrSnapOutOfDate
+	}
+	if err := s.writeSnapshot(snap); err != nil {
+		return err
+	}
+	prev := s.st
+	s.st.epoch++
+	s.st.compactedIndex, s.st.compactedTerm = snap.Metadata.Index, snap.Metadata.Term
+	s.st.snapIndex, s.st.snapTerm = snap.Metadata.Index, snap.Metadata.Term
+	if err := s.writeState(prev); err != nil {
+		return err
+	}
+	s.snap = snap
+	s.ents = nil
+	segs := s.segs
+	s.segs, s.unsynced = nil, nil
+	for _, seg := range segs {
+		if err := seg.remove(); err != nil {
+			return err
+		}
+	}
+	return s.removeSnapshot(prev)
+}
+
+// CreateSnapshot makes a snapshot which can be retrieved with Snapshot() and
+// can be used to reconstruct the state at that point. If any configuration
+// changes have been made since the last compaction, the result of the last
+// ApplyConfChange must be passed in.
+func (s *Storage) CreateSnapshot(i uint64, cs *pb.ConfState, data []byte) (pb.Snapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if i <= s.st.snapIndex {
+		return pb.Snapshot{}, raft.ErrSnapOutOfDate
+	}
+	if i > s.lastIndex() {
+		panic(fmt.Sprintf("snapshot %d is out of bound lastindex(%d)", i, s.lastIndex()))
+	}
+	term, err := s.term(i)
+	if err != nil {
+		return pb.Snapshot{}, err
+	}
+	snap := s.snap
+	snap.Metadata.Index, snap.Metadata.Term = i, term
+	if cs != nil {
+		snap.Metadata.ConfState = *cs
+	}
+	snap.Data = data
+	if err := s.writeSnapshot(snap); err != nil {
+		return pb.Snapshot{}, err
+	}
+	prev := s.st
+	s.st.snapIndex, s.st.snapTerm = i, term
+	if err := s.writeState(prev); err != nil {
+		return pb.Snapshot{}, err
+	}
+	s.snap = snap
+	return snap, s.removeSnapshot(prev)
+}
+
+// Compact discards all log entries prior to compactIndex, deleting the
+// segments holding only discarded entries. It is the application's
+// responsibility to not attempt to compact an index greater than the applied
+// index.
+func (s *Storage) Compact(compactIndex uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if compactIndex <= s.st.compactedIndex {
+		return raft.ErrCompacted
+	}
+	if compactIndex > s.lastIndex() {
+		panic(fmt.Sprintf("compact %d is out of bound lastindex(%d)", compactIndex, s.lastIndex()))
+	}
+	i := compactIndex - s.st.compactedIndex
+	prev := s.st
+	s.st.compactedIndex, s.st.compactedTerm = compactIndex, s.ents[i-1].term
+	if err := s.writeState(prev); err != nil {
+		return err
+	}
+	s.ents = slices.Clone(s.ents[i:])
+	for len(s.segs) > 0 && s.segs[0].last <= compactIndex {
+		seg := s.segs[0]
+		s.segs = s.segs[1:]
+		s.unsynced = slices.DeleteFunc(s.unsynced, func(u *segment) bool { return u == seg })
+		if err := seg.remove(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Append the new entries to storage, replacing the existing entries from the
+// index of the first new one on.
+func (s *Storage) Append(entries []pb.Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	first := s.st.compactedIndex + 1
+	last := entries[0].Index + uint64(len(entries)) - 1
+
+	// shortcut if there is no new entry.
+	if last < first {
+		return nil
+	}
+	// truncate compacted entries
+	if first > entries[0].Index {
+		entries = entries[first-entries[0].Index:]
+	}
+
+	switch index := entries[0].Index; {
+	case index <= s.lastIndex():
+		if err := s.truncate(index); err != nil {
+			return err
+		}
+	case index > s.lastIndex()+1:
+		panic(fmt.Sprintf("missing log entry [last: %d, append at: %d]", s.lastIndex(), index))
+	}
+	if err := s.writeEntries(entries); err != nil {
+		return err
+	}
+	if s.opts.Sync == SyncAlways || (s.opts.Sync == SyncInterval && time.Since(s.lastSync) >= s.opts.SyncInterval) {
+		return s.sync()
+	}
+	return nil
+}
+
+// truncate drops the entries from the given index on. Unless the sync policy
+// is SyncNever, the truncation is durable when it returns, whatever the sync
+// policy: otherwise a crash could bring the dropped entries back after those
+// replacing them, or leave a gap before the segments following the truncated
+// one.
+func (s *Storage) truncate(index uint64) error {
+	sync := s.opts.Sync != SyncNever
+	removed := false
+	for len(s.segs) > 0 {
+		seg := s.segs[len(s.segs)-1]
+		if seg.first <= index {
+			break
+		}
+		s.segs = s.segs[:len(s.segs)-1]
+		s.unsynced = slices.DeleteFunc(s.unsynced, func(u *segment) bool { return u == seg })
+		if err := seg.remove(); err != nil {
+			return err
+		}
+		removed = true
+	}
+	// The segments following the truncated one are removed first, so that
+	// they are gone if the truncation survives a crash.
+	if removed && sync {
+		if err := syncDir(s.dir); err != nil {
+			return err
+		}
+	}
+	if n := len(s.segs); n > 0 && s.segs[n-1].last >= index {
+		seg := s.segs[n-1]
+		if err := seg.truncate(s.ents[index-s.st.compactedIndex-1].off, index-1); err != nil {
+			return err
+		}
+		if sync {
+			if err := syncFile(seg.f); err != nil {
+				return err
+			}
+		}
+	}
+	s.ents = s.ents[:index-s.st.compactedIndex-1]
+	return nil
+}
+
+// writeEntries writes the entries, which follow the last one, to the
+// segments, starting new ones as needed.
+func (s *Storage) writeEntries(entries []pb.Entry) error {
+	var (
+		seg    *segment
+		buf    []byte
+		bufOff int64
+	)
+	flush := func() error {
+		if len(buf) == 0 {
+			return nil
+		}
+		_, err := seg.f.WriteAt(buf, bufOff)
+		buf = buf[:0]
+		return err
+	}
+	if len(s.segs) > 0 {
+		seg = s.segs[len(s.segs)-1]
+	}
+	for _, e := range entries {
+		if seg == nil || seg.last+1 != e.Index || seg.size >= s.opts.SegmentSize {
+			if err := flush(); err != nil {
+				return err
+			}
+			var err error
+			if seg, err = createSegment(s.dir, s.st.epoch, e.Index, s.opts.Sync != SyncNever); err != nil {
+				return err
+			}
+			s.segs = append(s.segs, seg)
+		}
+		if len(buf) == 0 {
+			bufOff = seg.size
+			if !slices.Contains(s.unsynced, seg) {
+				s.unsynced = append(s.unsynced, seg)
+			}
+		}
+		data, err := e.Marshal()
+		if err != nil {
+			return err
+		}
+		buf = appendRecord(buf, data)
+		s.ents = append(s.ents, entryPos{term: e.Term, seg: seg, off: seg.size, size: len(data)})
+		seg.size += recordHeaderSize + int64(len(data))
+		seg.last = e.Index
+	}
+	return flush()
+}
+
+// Sync syncs the entries appended since the last sync to stable storage.
+func (s *Storage) Sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sync()
+}
+
+func (s *Storage) sync() error {
+	if s.opts.Sync != SyncNever {
+		for _, seg := range s.unsynced {
+			if err := syncFile(seg.f); err != nil {
+				return err
+			}
+		}
+	}
+	s.unsynced = s.unsynced[:0]
+	s.lastSync = time.Now()
+	return nil
+}
+
+// Close syncs and closes the storage.
+func (s *Storage) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := s.sync()
+	for _, seg := range s.segs {
+		err = errors.Join(err, seg.f.Close())
+	}
+	s.segs = nil
+	return err
+}
+
+// writeState writes the state file, restoring the given previous state in
+// memory if that fails.
+func (s *Storage) writeState(prev state) error {
+	buf, err := s.st.marshal()
+	if err == nil {
+		err = writeFile(filepath.Join(s.dir, stateFile), buf, s.opts.Sync != SyncNever)
+	}
+	if err != nil {
+		s.st = prev
+	}
+	return err
+}
+
+func (s *Storage) writeSnapshot(snap pb.Snapshot) error {
+	buf, err := snap.Marshal()
+	if err != nil {
+		return err
+	}
+	name := snapshotName(snap.Metadata.Term, snap.Metadata.Index)
+	return writeFile(filepath.Join(s.dir, name), buf, s.opts.Sync != SyncNever)
+}
+
+// removeSnapshot removes the snapshot file referenced by the given state, if
+// it was replaced.
+func (s *Storage) removeSnapshot(prev state) error {
+	if prev.snapIndex == 0 || (prev.snapIndex == s.st.snapIndex && prev.snapTerm == s.st.snapTerm) {
+		return nil
+	}
+	return os.Remove(filepath.Join(s.dir, snapshotName(prev.snapTerm, prev.snapIndex)))
+}
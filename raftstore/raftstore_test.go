@@ -0,0 +1,328 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package raftstore
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	pb "go.etcd.io/raft/v3/raftpb"
+	"go.etcd.io/raft/v3/storagetest"
+)
+
+// testOptions uses tiny segments to exercise rolling over to new ones.
+var testOptions = Options{SegmentSize: 64, Sync: SyncNever}
+
+func open(t *testing.T, dir string, opts Options) *Storage {
+	s, err := Open(dir, opts)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.Close() })
+	return s
+}
+
+func TestConformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storagetest.Storage {
+		return open(t, t.TempDir(), testOptions)
+	})
+}
+
+// reopening reopens the storage after every write, which checks that all
+// writes are persisted.
+type reopening struct {
+	*Storage
+	t *testing.T
+}
+
+func (r *reopening) reopen(err error) error {
+	require.NoError(r.t, r.Storage.Close())
+	r.Storage = open(r.t, r.Storage.dir, r.Storage.opts)
+	return err
+}
+
+func (r *reopening) SetHardState(st pb.HardState) error {
+	return r.reopen(r.Storage.SetHardState(st))
+}
+
+func (r *reopening) Append(entries []pb.Entry) error {
+	return r.reopen(r.Storage.Append(entries))
+}
+
+func (r *reopening) ApplySnapshot(snap pb.Snapshot) error {
+	return r.reopen(r.Storage.ApplySnapshot(snap))
+}
+
+func (r *reopening) CreateSnapshot(i uint64, cs *pb.ConfState, data []byte) (pb.Snapshot, error) {
+	snap, err := r.Storage.CreateSnapshot(i, cs, data)
+	return snap, r.reopen(err)
+}
+
+func (r *reopening) Compact(compactIndex uint64) error {
+	return r.reopen(r.Storage.Compact(compactIndex))
+}
+
+func TestConformanceReopen(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storagetest.Storage {
+		return &reopening{Storage: open(t, t.TempDir(), testOptions), t: t}
+	})
+}
+
+func entries(first, last uint64, data string) []pb.Entry {
+	var ents []pb.Entry
+	for i := first; i <= last; i++ {
+		ents = append(ents, pb.Entry{Index: i, Term: 1, Data: []byte(data)})
+	}
+	return ents
+}
+
+func segments(t *testing.T, dir string) []string {
+	names, err := filepath.Glob(filepath.Join(dir, "*.log"))
+	require.NoError(t, err)
+	return names
+}
+
+func TestCompactRemovesSegments(t *testing.T) {
+	dir := t.TempDir()
+	s := open(t, dir, testOptions)
+	require.NoError(t, s.Append(entries(1, 20, "0123456789")))
+	n := len(segments(t, dir))
+	require.Greater(t, n, 5)
+
+	require.NoError(t, s.Compact(10))
+	require.Less(t, len(segments(t, dir)), n)
+	ents, err := s.Entries(11, 21, math.MaxUint64)
+	require.NoError(t, err)
+	require.Equal(t, entries(11, 20, "0123456789"), ents)
+
+	// A snapshot replaces all segments.
+	require.NoError(t, s.ApplySnapshot(pb.Snapshot{Metadata: pb.SnapshotMetadata{Index: 30, Term: 2}}))
+	require.Empty(t, segments(t, dir))
+}
+
+func TestTornWrite(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		tear func(data []byte) []byte
+	}{
+		{"truncated header", func(data []byte) []byte { return append(data, 1, 2, 3) }},
+		{"truncated record", func(data []byte) []byte { return data[:len(data)-2] }},
+		{"corrupted record", func(data []byte) []byte {
+			data[len(data)-1] ^= 0xff
+			return data
+		}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			s, err := Open(dir, testOptions)
+			require.NoError(t, err)
+			require.NoError(t, s.Append(entries(1, 20, "0123456789")))
+			require.NoError(t, s.Close())
+
+			segs := segments(t, dir)
+			last := segs[len(segs)-1]
+			data, err := os.ReadFile(last)
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(last, tt.tear(data), 0o600))
+
+			_, err = Open(dir, testOptions)
+			require.Error(t, err)
+
+			opts := testOptions
+			opts.Recovery = RecoverTornWrites
+			s = open(t, dir, opts)
+			li, err := s.LastIndex()
+			require.NoError(t, err)
+			require.GreaterOrEqual(t, li, uint64(19))
+			// The log can be appended to after recovery.
+			require.NoError(t, s.Append(entries(li+1, 25, "x")))
+			ents, err := s.Entries(1, 26, math.MaxUint64)
+			require.NoError(t, err)
+			require.Len(t, ents, 25)
+		})
+	}
+}
+
+func TestCorruptionBeforeTail(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Open(dir, testOptions)
+	require.NoError(t, err)
+	require.NoError(t, s.Append(entries(1, 20, "0123456789")))
+	require.NoError(t, s.Close())
+
+	first := segments(t, dir)[0]
+	data, err := os.ReadFile(first)
+	require.NoError(t, err)
+	data[len(data)-1] ^= 0xff
+	require.NoError(t, os.WriteFile(first, data, 0o600))
+
+	opts := testOptions
+	opts.Recovery = RecoverTornWrites
+	_, err = Open(dir, opts)
+	require.ErrorIs(t, err, ErrCorrupt)
+}
+
+func TestStaleFilesRemoved(t *testing.T) {
+	dir := t.TempDir()
+	s := open(t, dir, testOptions)
+	require.NoError(t, s.Append(entries(1, 5, "a")))
+	require.NoError(t, s.Close())
+
+	// Leave behind the files of an interrupted ApplySnapshot: a segment of a
+	// previous epoch, a snapshot that didn't make it into the state file and a
+	// temporary file.
+	stale := []string{
+		filepath.Join(dir, segmentName(7, 1)),
+		filepath.Join(dir, snapshotName(3, 9)),
+		filepath.Join(dir, stateFile+".tmp"),
+	}
+	for _, name := range stale {
+		require.NoError(t, os.WriteFile(name, []byte("stale"), 0o600))
+	}
+	s = open(t, dir, testOptions)
+	for _, name := range stale {
+		require.NoFileExists(t, name)
+	}
+	li, err := s.LastIndex()
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), li)
+}
+
+// durableFile is the content of a file as of its last sync.
+type durableFile struct {
+	fi   os.FileInfo
+	data []byte
+}
+
+// crashSim tracks what the syncs of the storage make durable in a directory,
+// and rebuilds the directory as a crash would leave it: with the files that
+// were present at the last sync of the directory, with the content they had
+// at their last sync.
+type crashSim struct {
+	t   *testing.T
+	dir string
+	// synced holds the files synced since the last sync of the directory.
+	synced  []durableFile
+	durable map[string]durableFile
+}
+
+// newCrashSim starts tracking the syncs in the directory, whose current
+// content is considered durable.
+func newCrashSim(t *testing.T, dir string) *crashSim {
+	c := &crashSim{t: t, dir: dir, durable: map[string]durableFile{}}
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	for _, f := range files {
+		c.durable[f.Name()] = c.read(filepath.Join(dir, f.Name()))
+	}
+	prevFile, prevDir := syncFile, syncDir
+	t.Cleanup(func() { syncFile, syncDir = prevFile, prevDir })
+	syncFile = func(f *os.File) error {
+		c.fileSynced(f.Name())
+		return nil
+	}
+	syncDir = func(d string) error {
+		require.Equal(t, dir, d)
+		c.dirSynced()
+		return nil
+	}
+	return c
+}
+
+func (c *crashSim) read(path string) durableFile {
+	fi, err := os.Stat(path)
+	require.NoError(c.t, err)
+	data, err := os.ReadFile(path)
+	require.NoError(c.t, err)
+	return durableFile{fi: fi, data: data}
+}
+
+func (c *crashSim) fileSynced(path string) {
+	df := c.read(path)
+	c.synced = slices.DeleteFunc(c.synced, func(s durableFile) bool { return os.SameFile(s.fi, df.fi) })
+	c.synced = append(c.synced, df)
+	for name, d := range c.durable {
+		if os.SameFile(d.fi, df.fi) {
+			c.durable[name] = df
+		}
+	}
+}
+
+func (c *crashSim) dirSynced() {
+	files, err := os.ReadDir(c.dir)
+	require.NoError(c.t, err)
+	durable := map[string]durableFile{}
+	for _, f := range files {
+		fi, err := os.Stat(filepath.Join(c.dir, f.Name()))
+		require.NoError(c.t, err)
+		df := durableFile{fi: fi}
+		if i := slices.IndexFunc(c.synced, func(s durableFile) bool { return os.SameFile(s.fi, fi) }); i >= 0 {
+			df = c.synced[i]
+		} else if d, ok := c.durable[f.Name()]; ok && os.SameFile(d.fi, fi) {
+			df = d
+		}
+		durable[f.Name()] = df
+	}
+	c.durable, c.synced = durable, nil
+}
+
+// crash returns a new directory with the durable content of the directory.
+func (c *crashSim) crash() string {
+	dir := c.t.TempDir()
+	for name, df := range c.durable {
+		require.NoError(c.t, os.WriteFile(filepath.Join(dir, name), df.data, 0o600))
+	}
+	return dir
+}
+
+// TestCrashAfterTruncate checks that the entries replaced by an append don't
+// come back after a crash.
+func TestCrashAfterTruncate(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		first, last uint64
+	}{
+		{"same segment", 5, 5},
+		{"new segments", 5, 12},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := Options{SegmentSize: 64}
+			dir := t.TempDir()
+			s, err := Open(dir, opts)
+			require.NoError(t, err)
+			require.NoError(t, s.Append(entries(1, 20, "0123456789")))
+			require.NoError(t, s.Close())
+
+			c := newCrashSim(t, dir)
+			s = open(t, dir, opts)
+			overwrite := entries(tt.first, tt.last, "abc")
+			for i := range overwrite {
+				overwrite[i].Term = 2
+			}
+			require.NoError(t, s.Append(overwrite))
+
+			s = open(t, c.crash(), opts)
+			li, err := s.LastIndex()
+			require.NoError(t, err)
+			require.Equal(t, tt.last, li)
+			ents, err := s.Entries(1, li+1, math.MaxUint64)
+			require.NoError(t, err)
+			require.Equal(t, append(entries(1, tt.first-1, "0123456789"), overwrite...), ents)
+		})
+	}
+}
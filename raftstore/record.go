@@ -0,0 +1,180 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package raftstore
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+
+	pb "go.etcd.io/raft/v3/raftpb"
+)
+
+// ErrCorrupt is returned when a file of the storage fails its checksum or
+// can't be decoded.
+var ErrCorrupt = errors.New("raftstore: corrupt data")
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// A record is a payload preceded by a header holding its length and CRC-32C
+// checksum, both little-endian uint32s.
+const recordHeaderSize = 8
+
+// appendRecord appends the record holding the payload to buf.
+func appendRecord(buf, payload []byte) []byte {
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(payload)))
+	buf = binary.LittleEndian.AppendUint32(buf, crc32.Checksum(payload, crcTable))
+	return append(buf, payload...)
+}
+
+// errChecksum is returned for records failing their checksum.
+var errChecksum = fmt.Errorf("%w: checksum mismatch", ErrCorrupt)
+
+// readRecord reads the record at the given offset of a file of the given size,
+// and returns its payload. It returns io.EOF at the end of the file, and
+// io.ErrUnexpectedEOF or errChecksum if the record is truncated or fails its
+// checksum.
+func readRecord(r io.ReaderAt, off, size int64) ([]byte, error) {
+	if off >= size {
+		return nil, io.EOF
+	}
+	var hdr [recordHeaderSize]byte
+	if size-off < recordHeaderSize {
+		return nil, io.ErrUnexpectedEOF
+	}
+	if _, err := r.ReadAt(hdr[:], off); err != nil {
+		return nil, err
+	}
+	n := int64(binary.LittleEndian.Uint32(hdr[0:]))
+	if size-off-recordHeaderSize < n {
+		return nil, io.ErrUnexpectedEOF
+	}
+	payload := make([]byte, n)
+	if _, err := r.ReadAt(payload, off+recordHeaderSize); err != nil {
+		return nil, err
+	}
+	if crc32.Checksum(payload, crcTable) != binary.LittleEndian.Uint32(hdr[4:]) {
+		return nil, fmt.Errorf("%w at offset %d", errChecksum, off)
+	}
+	return payload, nil
+}
+
+// writeFile atomically replaces the file at path with one holding a single
+// record with the payload.
+func writeFile(path string, payload []byte, sync bool) error {
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(appendRecord(nil, payload)); err != nil {
+		f.Close()
+		return err
+	}
+	if sync {
+		if err := syncFile(f); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+	if sync {
+		return syncDir(filepath.Dir(path))
+	}
+	return nil
+}
+
+// readFile reads the single record of the file at path.
+func readFile(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	payload, err := readRecord(f, 0, fi.Size())
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		err = fmt.Errorf("%w: %s is truncated", ErrCorrupt, path)
+	}
+	return payload, err
+}
+
+// syncFile and syncDir sync a file and the entries of a directory to stable
+// storage. Tests replace them to simulate crashes.
+var (
+	syncFile = (*os.File).Sync
+	syncDir  = func(dir string) error {
+		d, err := os.Open(dir)
+		if err != nil {
+			return err
+		}
+		defer d.Close()
+		return d.Sync()
+	}
+)
+
+// state is the content of the state file, which is replaced atomically and
+// ties together the files of the storage.
+type state struct {
+	hardState pb.HardState
+	// epoch is the generation of the log segments, incremented whenever a
+	// snapshot replaces the log. Segments of other epochs are stale.
+	epoch uint64
+	// compacted is the last compacted entry. Its term is retained to match
+	// the log against.
+	compactedIndex, compactedTerm uint64
+	// snapIndex and snapTerm identify the snapshot file, if snapIndex is not
+	// zero.
+	snapIndex, snapTerm uint64
+}
+
+const stateFixedSize = 5 * 8
+
+func (st *state) marshal() ([]byte, error) {
+	hs, err := st.hardState.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, 0, stateFixedSize+len(hs))
+	for _, v := range []uint64{st.epoch, st.compactedIndex, st.compactedTerm, st.snapIndex, st.snapTerm} {
+		buf = binary.LittleEndian.AppendUint64(buf, v)
+	}
+	return append(buf, hs...), nil
+}
+
+func (st *state) unmarshal(buf []byte) error {
+	if len(buf) < stateFixedSize {
+		return fmt.Errorf("%w: state too short", ErrCorrupt)
+	}
+	for i, v := range []*uint64{&st.epoch, &st.compactedIndex, &st.compactedTerm, &st.snapIndex, &st.snapTerm} {
+		*v = binary.LittleEndian.Uint64(buf[8*i:])
+	}
+	if err := st.hardState.Unmarshal(buf[stateFixedSize:]); err != nil {
+		return fmt.Errorf("%w: %v", ErrCorrupt, err)
+	}
+	return nil
+}
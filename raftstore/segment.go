@@ -0,0 +1,121 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package raftstore
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	pb "go.etcd.io/raft/v3/raftpb"
+)
+
+// segment is a file of the log, holding a record for each of a range of
+// consecutive entries.
+type segment struct {
+	epoch, first uint64
+	path         string
+	f            *os.File
+	// size is the size of the records in the file, and last the index of the
+	// last entry, or first-1 if there is none.
+	size int64
+	last uint64
+}
+
+func segmentName(epoch, first uint64) string {
+	return fmt.Sprintf("%016x-%016x.log", epoch, first)
+}
+
+func parseSegmentName(name string) (epoch, first uint64, ok bool) {
+	if _, err := fmt.Sscanf(name, "%016x-%016x.log", &epoch, &first); err != nil {
+		return 0, 0, false
+	}
+	return epoch, first, name == segmentName(epoch, first)
+}
+
+func createSegment(dir string, epoch, first uint64, sync bool) (*segment, error) {
+	path := filepath.Join(dir, segmentName(epoch, first))
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	if sync {
+		if err := syncDir(dir); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+	return &segment{epoch: epoch, first: first, path: path, f: f, last: first - 1}, nil
+}
+
+// scan calls fn for the entries of the segment, in order, along with the
+// offset and size of their records. It stops at the first record that can't
+// be read, and returns its offset along with the error.
+func (s *segment) scan(fn func(e pb.Entry, off int64, size int) error) (int64, error) {
+	fi, err := s.f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	var off int64
+	for {
+		payload, err := readRecord(s.f, off, fi.Size())
+		if err == io.EOF {
+			return off, nil
+		} else if err != nil {
+			return off, err
+		}
+		var e pb.Entry
+		if err := e.Unmarshal(payload); err != nil {
+			return off, fmt.Errorf("%w: %v", ErrCorrupt, err)
+		}
+		if err := fn(e, off, len(payload)); err != nil {
+			return off, err
+		}
+		off += recordHeaderSize + int64(len(payload))
+	}
+}
+
+// read reads the entry whose record is at the given offset.
+func (s *segment) read(off int64) (pb.Entry, error) {
+	var e pb.Entry
+	payload, err := readRecord(s.f, off, s.size)
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return e, fmt.Errorf("reading %s: %w", s.path, err)
+	}
+	if err := e.Unmarshal(payload); err != nil {
+		return e, fmt.Errorf("%w: %v", ErrCorrupt, err)
+	}
+	return e, nil
+}
+
+// truncate drops the records from the given offset on.
+func (s *segment) truncate(off int64, last uint64) error {
+	if err := s.f.Truncate(off); err != nil {
+		return err
+	}
+	s.size, s.last = off, last
+	return nil
+}
+
+func (s *segment) remove() error {
+	if err := s.f.Close(); err != nil {
+		return err
+	}
+	return os.Remove(s.path)
+}
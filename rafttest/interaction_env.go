@@ -19,9 +19,11 @@ import (
 	"fmt"
 	"math"
 	"strings"
+	"time"
 
 	"go.etcd.io/raft/v3"
 	pb "go.etcd.io/raft/v3/raftpb"
+	"go.etcd.io/raft/v3/storagewrap"
 )
 
 // InteractionOpts groups the options for an InteractionEnv.
@@ -37,10 +39,14 @@ type InteractionOpts struct {
 type Node struct {
 	*raft.RawNode
 	Storage
+	// StorageWrap wraps the Storage in the raft.Config, to observe and
+	// perturb the reads of raft.
+	StorageWrap *storagewrap.Storage
 
 	Config     *raft.Config
 	AppendWork []pb.Message // []MsgStorageAppend
 	ApplyWork  []pb.Message // []MsgStorageApply
+	ReadWork   []EntriesRead
 	History    []pb.Snapshot
 }
 
@@ -50,6 +56,9 @@ type InteractionEnv struct {
 	Options  *InteractionOpts
 	Nodes    []Node
 	Messages []pb.Message // in-flight messages
+	// Clock is shared by all nodes, and only advances through the
+	// advance-clock command.
+	Clock *ManualClock
 
 	Output *RedirectLogger
 }
@@ -61,6 +70,7 @@ func NewInteractionEnv(opts *InteractionOpts) *InteractionEnv {
 	}
 	return &InteractionEnv{
 		Options: opts,
+		Clock:   &ManualClock{now: time.Unix(0, 0)},
 		Output: &RedirectLogger{
 			Builder: &strings.Builder{},
 		},
@@ -43,8 +43,15 @@ func (env *InteractionEnv) Handle(t *testing.T, d datadriven.TestData) string {
 	case "add-nodes":
 		// Example:
 		//
-		// add-nodes <number-of-nodes-to-add> voters=(1 2 3) learners=(4 5) index=2 content=foo async-storage-writes=true
+		// add-nodes <number-of-nodes-to-add> voters=(1 2 3) learners=(4 5) witnesses=(3) weights=(3 1 1) index=2 content=foo async-storage-writes=true async-storage-reads=true
 		err = env.handleAddNodes(t, d)
+	case "advance-clock":
+		// Advance the clock shared by all nodes.
+		//
+		// Example:
+		//
+		// advance-clock 100ms
+		err = env.handleAdvanceClock(t, d)
 	case "campaign":
 		// Example:
 		//
@@ -55,6 +62,14 @@ func (env *InteractionEnv) Handle(t *testing.T, d datadriven.TestData) string {
 		//
 		// compact <id> <new-first-index>
 		err = env.handleCompact(t, d)
+	case "corrupt-state":
+		// Append the given content to the state machine of the given node,
+		// bypassing raft.
+		//
+		// Example:
+		//
+		// corrupt-state 2 content=foo
+		err = env.handleCorruptState(t, d)
 	case "deliver-msgs":
 		// Deliver the messages for a given recipient.
 		//
@@ -72,11 +87,26 @@ func (env *InteractionEnv) Handle(t *testing.T, d datadriven.TestData) string {
 		//
 		// process-append-thread 3
 		err = env.handleProcessAppendThread(t, d)
+	case "process-storage-reads":
+		// Perform the entry reads started in the background by the given
+		// nodes (see async-storage-reads in add-nodes), and report them.
+		//
+		// Example:
+		//
+		// process-storage-reads 1
+		err = env.handleProcessStorageReads(t, d)
 	case "process-apply-thread":
 		// Example:
 		//
 		// process-apply-thread 3
 		err = env.handleProcessApplyThread(t, d)
+	case "lease":
+		// Print the remaining leader lease of the given node.
+		//
+		// Example:
+		//
+		// lease 1
+		err = env.handleLease(t, d)
 	case "log-level":
 		// Set the log level. NONE disables all output, including from the test
 		// harness (except errors).
@@ -120,6 +150,25 @@ func (env *InteractionEnv) Handle(t *testing.T, d datadriven.TestData) string {
 		//
 		// status 5
 		err = env.handleStatus(t, d)
+	case "storage-fault":
+		// Inject a fault into the given calls of a method of the storage of
+		// the given node, numbered from 1 starting with the next call, or
+		// into every nth call. Without arguments, stops the injection.
+		//
+		// Example:
+		//
+		// storage-fault 1 method=Snapshot error=snapshot-temporarily-unavailable calls=(1,2)
+		// storage-fault 1 method=Entries delay=10ms every=2
+		// storage-fault 1
+		err = env.handleStorageFault(t, d)
+	case "storage-stats":
+		// Print the statistics of the calls raft made into the storage of the
+		// given node.
+		//
+		// Example:
+		//
+		// storage-stats 1
+		err = env.handleStorageStats(t, d)
 	case "tick-election":
 		// Tick an election timeout interval for the given node (but beware the
 		// randomized timeout).
@@ -149,6 +198,21 @@ func (env *InteractionEnv) Handle(t *testing.T, d datadriven.TestData) string {
 		//
 		// forget-leader 1
 		err = env.handleForgetLeader(t, d)
+	case "wake":
+		// Wakes up the given node if it is quiesced.
+		//
+		// Example:
+		//
+		// wake 1
+		err = env.handleWake(t, d)
+	case "safe-read-index":
+		// Print the safe read index of the given node, and whether reads
+		// served at it satisfy the given maximum staleness.
+		//
+		// Example:
+		//
+		// safe-read-index 2 max-staleness=1s
+		err = env.handleSafeReadIndex(t, d)
 	case "send-snapshot":
 		// Sends a snapshot to a node. Takes the source and destination node.
 		// The message will be queued, but not delivered automatically.
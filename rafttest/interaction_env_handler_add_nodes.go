@@ -19,11 +19,13 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/cockroachdb/datadriven"
 
 	"go.etcd.io/raft/v3"
 	pb "go.etcd.io/raft/v3/raftpb"
+	"go.etcd.io/raft/v3/storagewrap"
 )
 
 func (env *InteractionEnv) handleAddNodes(t *testing.T, d datadriven.TestData) error {
@@ -41,6 +43,25 @@ func (env *InteractionEnv) handleAddNodes(t *testing.T, d datadriven.TestData) e
 				var id uint64
 				arg.Scan(t, i, &id)
 				snap.Metadata.ConfState.Learners = append(snap.Metadata.ConfState.Learners, id)
+			case "weights":
+				// Weights are given for the voters, in the same order.
+				var w uint64
+				arg.Scan(t, i, &w)
+				snap.Metadata.ConfState.Weights = append(snap.Metadata.ConfState.Weights, pb.VoterWeight{Weight: w})
+			case "zones":
+				// Zones are given for the voters, in the same order.
+				var zone string
+				arg.Scan(t, i, &zone)
+				snap.Metadata.ConfState.Zones = append(snap.Metadata.ConfState.Zones, pb.NodeZone{Zone: zone})
+			case "priorities":
+				// Priorities are given for the voters, in the same order.
+				var priority uint64
+				arg.Scan(t, i, &priority)
+				snap.Metadata.ConfState.Priorities = append(snap.Metadata.ConfState.Priorities, pb.NodePriority{Priority: priority})
+			case "witnesses":
+				var id uint64
+				arg.Scan(t, i, &id)
+				snap.Metadata.ConfState.Witnesses = append(snap.Metadata.ConfState.Witnesses, id)
 			case "inflight":
 				arg.Scan(t, i, &cfg.MaxInflightMsgs)
 			case "index":
@@ -50,8 +71,12 @@ func (env *InteractionEnv) handleAddNodes(t *testing.T, d datadriven.TestData) e
 				arg.Scan(t, i, &snap.Data)
 			case "async-storage-writes":
 				arg.Scan(t, i, &cfg.AsyncStorageWrites)
+			case "async-storage-reads":
+				arg.Scan(t, i, &cfg.AsyncStorageReads)
 			case "prevote":
 				arg.Scan(t, i, &cfg.PreVote)
+			case "quiesce":
+				arg.Scan(t, i, &cfg.Quiesce)
 			case "checkquorum":
 				arg.Scan(t, i, &cfg.CheckQuorum)
 			case "max-committed-size-per-ready":
@@ -69,9 +94,54 @@ func (env *InteractionEnv) handleAddNodes(t *testing.T, d datadriven.TestData) e
 				}
 			case "step-down-on-removal":
 				arg.Scan(t, i, &cfg.StepDownOnRemoval)
+			case "election-quorum":
+				arg.Scan(t, i, &cfg.ElectionQuorum)
+			case "replication-quorum":
+				arg.Scan(t, i, &cfg.ReplicationQuorum)
+			case "min-commit-zones":
+				arg.Scan(t, i, &cfg.MinCommitZones)
+			case "lenient-zone-checks":
+				arg.Scan(t, i, &cfg.LenientZoneChecks)
+			case "follower-reads":
+				arg.Scan(t, i, &cfg.FollowerReads)
+			case "entry-checksums":
+				arg.Scan(t, i, &cfg.EntryChecksums)
+			case "hash-check-interval":
+				arg.Scan(t, i, &cfg.HashCheckInterval)
+			case "lease-duration":
+				dur, err := time.ParseDuration(arg.Vals[i])
+				if err != nil {
+					return err
+				}
+				cfg.LeaseDuration = dur
+			case "max-clock-offset":
+				dur, err := time.ParseDuration(arg.Vals[i])
+				if err != nil {
+					return err
+				}
+				cfg.MaxClockOffset = dur
 			}
 		}
 	}
+	for i := range snap.Metadata.ConfState.Weights {
+		if i >= len(snap.Metadata.ConfState.Voters) {
+			return errors.New("more weights than voters")
+		}
+		snap.Metadata.ConfState.Weights[i].NodeID = snap.Metadata.ConfState.Voters[i]
+	}
+	for i := range snap.Metadata.ConfState.Zones {
+		if i >= len(snap.Metadata.ConfState.Voters) {
+			return errors.New("more zones than voters")
+		}
+		snap.Metadata.ConfState.Zones[i].NodeID = snap.Metadata.ConfState.Voters[i]
+	}
+	cs := &snap.Metadata.ConfState
+	for i := range cs.Priorities {
+		if i >= len(cs.Voters) {
+			return errors.New("more priorities than voters")
+		}
+		cs.Priorities[i].NodeID = cs.Voters[i]
+	}
 	return env.AddNodes(n, cfg, snap)
 }
 
@@ -130,7 +200,15 @@ func (env *InteractionEnv) AddNodes(n int, cfg raft.Config, snap pb.Snapshot) er
 			}
 		}
 		cfg := cfg // fork the config stub
-		cfg.ID, cfg.Storage = id, s
+		wrapped := storagewrap.New(s)
+		cfg.ID, cfg.Storage, cfg.Clock = id, wrapped, env.Clock
+		if cfg.AsyncStorageReads {
+			cfg.Storage = asyncReadStorage{Storage: wrapped, env: env, idx: int(id - 1)}
+		}
+		cfg.OnHashMismatch = func(hm raft.HashMismatch) {
+			fmt.Fprintf(env.Output, "%x: hash mismatch at index %d: %x, leader %x\n",
+				id, hm.Index, hm.Hash, hm.LeaderHash)
+		}
 		if env.Options.OnConfig != nil {
 			env.Options.OnConfig(&cfg)
 			if cfg.ID != id {
@@ -153,9 +231,10 @@ func (env *InteractionEnv) AddNodes(n int, cfg raft.Config, snap pb.Snapshot) er
 			RawNode: rn,
 			// TODO(tbg): allow a more general Storage, as long as it also allows
 			// us to apply snapshots, append entries, and update the HardState.
-			Storage: s,
-			Config:  &cfg,
-			History: []pb.Snapshot{snap},
+			Storage:     s,
+			StorageWrap: wrapped,
+			Config:      &cfg,
+			History:     []pb.Snapshot{snap},
 		}
 		env.Nodes = append(env.Nodes, node)
 	}
@@ -0,0 +1,38 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rafttest
+
+import (
+	"testing"
+
+	"github.com/cockroachdb/datadriven"
+)
+
+func (env *InteractionEnv) handleCorruptState(t *testing.T, d datadriven.TestData) error {
+	idx := firstAsNodeIdx(t, d)
+	var content string
+	d.ScanArgs(t, "content", &content)
+	return env.CorruptState(idx, content)
+}
+
+// CorruptState appends the given content to the state machine of the node at
+// the given index, without going through raft, to simulate a divergence.
+func (env *InteractionEnv) CorruptState(idx int, content string) error {
+	n := &env.Nodes[idx]
+	snap := n.History[len(n.History)-1]
+	snap.Data = append(append([]byte(nil), snap.Data...), content...)
+	n.History[len(n.History)-1] = snap
+	return nil
+}
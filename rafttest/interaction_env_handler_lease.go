@@ -0,0 +1,95 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rafttest
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/datadriven"
+)
+
+// ManualClock is a raft.Clock that only advances when told to.
+type ManualClock struct {
+	now time.Time
+}
+
+// Now implements raft.Clock.
+func (c *ManualClock) Now() time.Time { return c.now }
+
+// Advance moves the clock forward by d.
+func (c *ManualClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func (env *InteractionEnv) handleAdvanceClock(t *testing.T, d datadriven.TestData) error {
+	if len(d.CmdArgs) != 1 {
+		t.Fatalf("expected a single duration")
+	}
+	dur, err := time.ParseDuration(d.CmdArgs[0].Key)
+	if err != nil {
+		return err
+	}
+	env.Clock.Advance(dur)
+	return nil
+}
+
+func (env *InteractionEnv) handleLease(t *testing.T, d datadriven.TestData) error {
+	idx := firstAsNodeIdx(t, d)
+	return env.Lease(idx)
+}
+
+// Lease prints the remaining leader lease of the node at the given index.
+func (env *InteractionEnv) Lease(idx int) error {
+	expiry := env.Nodes[idx].Status().LeaseExpiry
+	if remaining := expiry.Sub(env.Clock.Now()); remaining > 0 {
+		fmt.Fprintf(env.Output, "lease expires in %v\n", remaining)
+	} else {
+		fmt.Fprintln(env.Output, "no lease")
+	}
+	return nil
+}
+
+func (env *InteractionEnv) handleSafeReadIndex(t *testing.T, d datadriven.TestData) error {
+	idx := firstAsNodeIdx(t, d)
+	var maxStaleness time.Duration
+	for _, arg := range d.CmdArgs[1:] {
+		switch arg.Key {
+		case "max-staleness":
+			var err error
+			if maxStaleness, err = time.ParseDuration(arg.Vals[0]); err != nil {
+				return err
+			}
+		}
+	}
+	return env.SafeReadIndex(idx, maxStaleness)
+}
+
+// SafeReadIndex prints the safe read index of the node at the given index, and
+// whether reads served at it are at most maxStaleness stale.
+func (env *InteractionEnv) SafeReadIndex(idx int, maxStaleness time.Duration) error {
+	sr := env.Nodes[idx].Status().SafeRead
+	if sr.Time.IsZero() {
+		fmt.Fprintln(env.Output, "no safe read index")
+		return nil
+	}
+	staleness := env.Clock.Now().Sub(sr.Time) + env.Nodes[idx].Config.MaxClockOffset
+	fmt.Fprintf(env.Output, "safe read index %d as of %v, staleness %v: ", sr.Index, sr.Time.Sub(time.Unix(0, 0)), staleness)
+	if _, ok := env.Nodes[idx].SafeReadIndex(maxStaleness); ok {
+		fmt.Fprintln(env.Output, "ok")
+	} else {
+		fmt.Fprintln(env.Output, "too stale")
+	}
+	return nil
+}
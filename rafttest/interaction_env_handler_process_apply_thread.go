@@ -16,6 +16,7 @@ package rafttest
 
 import (
 	"fmt"
+	"hash/fnv"
 	"testing"
 
 	"github.com/cockroachdb/datadriven"
@@ -87,6 +88,14 @@ func processApply(n *Node, ents []raftpb.Entry) error {
 			}
 			cs = n.RawNode.ApplyConfChange(cc)
 			update = cc.Context
+		case raftpb.EntryHashCheck:
+			// Markers carry no data. Report the hash of the state as of the
+			// marker; the hashes proposed by the leader are left to raft.
+			if len(ent.Data) == 0 {
+				h := fnv.New64a()
+				h.Write(n.History[len(n.History)-1].Data)
+				n.RawNode.ReportHash(ent.Index, h.Sum64())
+			}
 		default:
 			update = ent.Data
 		}
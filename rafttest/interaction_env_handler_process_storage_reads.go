@@ -0,0 +1,87 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rafttest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cockroachdb/datadriven"
+
+	"go.etcd.io/raft/v3"
+	pb "go.etcd.io/raft/v3/raftpb"
+)
+
+// EntriesRead is a read of the entries [Lo, Hi) with the MaxSize limit,
+// started by raft with AsyncStorage.FetchEntries.
+type EntriesRead struct {
+	Lo, Hi, MaxSize uint64
+}
+
+// asyncReadStorage reads entries in the background: FetchEntries queues the
+// reads on the node, and process-storage-reads performs them.
+type asyncReadStorage struct {
+	raft.Storage
+	env *InteractionEnv
+	idx int
+}
+
+func (s asyncReadStorage) FetchEntries(lo, hi, maxSize uint64) ([]pb.Entry, error) {
+	n := &s.env.Nodes[s.idx]
+	n.ReadWork = append(n.ReadWork, EntriesRead{Lo: lo, Hi: hi, MaxSize: maxSize})
+	return nil, raft.ErrEntriesPending
+}
+
+var _ raft.AsyncStorage = asyncReadStorage{}
+
+func (env *InteractionEnv) handleProcessStorageReads(t *testing.T, d datadriven.TestData) error {
+	idxs := nodeIdxs(t, d)
+	for _, idx := range idxs {
+		var err error
+		if len(idxs) > 1 {
+			fmt.Fprintf(env.Output, "> %d processing storage reads\n", idx+1)
+			env.withIndent(func() { err = env.ProcessStorageReads(idx) })
+		} else {
+			err = env.ProcessStorageReads(idx)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ProcessStorageReads performs the entry reads queued by the node with the
+// given index, and reports their results to it.
+func (env *InteractionEnv) ProcessStorageReads(idx int) error {
+	n := &env.Nodes[idx]
+	if len(n.ReadWork) == 0 {
+		env.Output.WriteString("no storage reads to perform")
+		return nil
+	}
+	reads := n.ReadWork
+	n.ReadWork = nil
+	for _, rd := range reads {
+		ents, err := n.Storage.Entries(rd.Lo, rd.Hi, rd.MaxSize)
+		fmt.Fprintf(env.Output, "read [%d,%d) max-size=%d\n", rd.Lo, rd.Hi, rd.MaxSize)
+		if err != nil {
+			fmt.Fprintf(env.Output, "error: %v\n", err)
+		} else {
+			env.Output.WriteString(raft.DescribeEntries(ents, defaultEntryFormatter))
+		}
+		n.ReportEntries(rd.Lo, ents, err)
+	}
+	return nil
+}
@@ -42,8 +42,12 @@ func (env *InteractionEnv) handleRaftState() error {
 		} else {
 			voterStatus = "(Non-Voter)"
 		}
-		fmt.Fprintf(env.Output, "%d: %s %s Term:%d Lead:%d\n",
+		fmt.Fprintf(env.Output, "%d: %s %s Term:%d Lead:%d",
 			st.ID, st.RaftState, voterStatus, st.Term, st.Lead)
+		if st.Quiesced {
+			fmt.Fprint(env.Output, " Quiesced")
+		}
+		fmt.Fprintln(env.Output)
 	}
 	return nil
 }
@@ -0,0 +1,125 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rafttest
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/datadriven"
+
+	"go.etcd.io/raft/v3"
+	"go.etcd.io/raft/v3/storagewrap"
+)
+
+var storageErrors = map[string]error{
+	"compacted":                        raft.ErrCompacted,
+	"unavailable":                      raft.ErrUnavailable,
+	"snapshot-temporarily-unavailable": raft.ErrSnapshotTemporarilyUnavailable,
+}
+
+func (env *InteractionEnv) handleStorageFault(t *testing.T, d datadriven.TestData) error {
+	idx := firstAsNodeIdx(t, d)
+	if len(d.CmdArgs) == 1 {
+		return env.StorageFault(idx, nil)
+	}
+	var method storagewrap.Method
+	var fault storagewrap.Fault
+	var calls []uint64
+	var every uint64
+	for _, arg := range d.CmdArgs[1:] {
+		for i := range arg.Vals {
+			switch arg.Key {
+			case "method":
+				m, err := storagewrap.ParseMethod(arg.Vals[i])
+				if err != nil {
+					return err
+				}
+				method = m
+			case "error":
+				err, ok := storageErrors[arg.Vals[i]]
+				if !ok {
+					return fmt.Errorf("unknown storage error %q", arg.Vals[i])
+				}
+				fault.Err = err
+			case "delay":
+				dur, err := time.ParseDuration(arg.Vals[i])
+				if err != nil {
+					return err
+				}
+				fault.Delay = dur
+			case "calls":
+				var c uint64
+				arg.Scan(t, i, &c)
+				calls = append(calls, c)
+			case "every":
+				arg.Scan(t, i, &every)
+			}
+		}
+	}
+	var inj storagewrap.Injector
+	switch {
+	case len(calls) > 0 && every > 0:
+		return fmt.Errorf("calls and every are mutually exclusive")
+	case len(calls) > 0:
+		inj = storagewrap.Schedule(method, fault, calls...)
+	case every > 0:
+		inj = storagewrap.Every(method, every, fault)
+	default:
+		inj = storagewrap.Every(method, 1, fault)
+	}
+	return env.StorageFault(idx, inj)
+}
+
+// StorageFault makes the storage of the node at the given index inject the
+// faults decided by the Injector, or stops the injection if it is nil. The
+// calls passed to the Injector are numbered from 1 starting with the next
+// call of each method.
+func (env *InteractionEnv) StorageFault(idx int, inj storagewrap.Injector) error {
+	s := env.Nodes[idx].StorageWrap
+	if inj == nil {
+		s.SetInjector(nil)
+		return nil
+	}
+	base := map[storagewrap.Method]uint64{}
+	for _, m := range storagewrap.Methods {
+		base[m] = s.Stats(m).Calls
+	}
+	s.SetInjector(storagewrap.InjectorFunc(func(m storagewrap.Method, call uint64) (storagewrap.Fault, bool) {
+		return inj.Fault(m, call-base[m])
+	}))
+	return nil
+}
+
+func (env *InteractionEnv) handleStorageStats(t *testing.T, d datadriven.TestData) error {
+	idx := firstAsNodeIdx(t, d)
+	return env.StorageStats(idx)
+}
+
+// StorageStats prints the statistics of the calls raft made into the storage
+// of the node at the given index. Latencies are omitted as they aren't
+// deterministic.
+func (env *InteractionEnv) StorageStats(idx int) error {
+	s := env.Nodes[idx].StorageWrap
+	for _, m := range storagewrap.Methods {
+		st := s.Stats(m)
+		if st.Calls == 0 {
+			continue
+		}
+		fmt.Fprintf(env.Output, "%s: calls=%d errors=%d bytes=%d\n", m, st.Calls, st.Errors, st.Bytes)
+	}
+	return nil
+}
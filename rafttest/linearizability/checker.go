@@ -0,0 +1,328 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linearizability
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Outcome is the verdict of a check.
+type Outcome int
+
+const (
+	// Ok means that the history is linearizable.
+	Ok Outcome = iota
+	// Illegal means that the history is not linearizable.
+	Illegal
+	// Unknown means that the search gave up before reaching a verdict.
+	Unknown
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case Ok:
+		return "ok"
+	case Illegal:
+		return "illegal"
+	default:
+		return "unknown"
+	}
+}
+
+// Options tune a check.
+type Options struct {
+	// MaxSteps bounds the number of search steps spent on each key. Zero
+	// means no limit. If the limit is hit, the key's outcome is Unknown.
+	MaxSteps int
+	// MaxMinimizeOps bounds the size of the counterexamples that are
+	// minimized. Larger counterexamples are reported as found. Zero selects
+	// a default of 256 operations.
+	MaxMinimizeOps int
+}
+
+// Result is the verdict of a check.
+type Result struct {
+	Outcome Outcome
+	// Keys is the number of partitions that were checked.
+	Keys int
+	// Operations is the number of operations that were checked.
+	Operations int
+	// Key is the key of the first partition that is not linearizable (or
+	// for which the search gave up).
+	Key string
+	// Counterexample is a set of operations on Key that is not linearizable
+	// and from which no single operation can be removed without making it
+	// linearizable. Only set if Outcome is Illegal.
+	Counterexample []Operation
+	// Linearized is the longest legal sequential order found for a prefix of
+	// the counterexample, for illustration.
+	Linearized []Operation
+}
+
+// Report renders the result in human-readable form.
+func (r Result) Report() string {
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "linearizability: %s (%d operations on %d keys)\n", r.Outcome, r.Operations, r.Keys)
+	switch r.Outcome {
+	case Illegal:
+		fmt.Fprintf(&buf, "minimal counterexample on key %q:\n", r.Key)
+		buf.WriteString(describe(r.Counterexample))
+		if len(r.Linearized) > 0 {
+			buf.WriteString("longest linearizable prefix:\n")
+			for i, op := range r.Linearized {
+				fmt.Fprintf(&buf, "%d. client %d %s\n", i+1, op.ClientID, op)
+			}
+		} else {
+			buf.WriteString("no operation can be linearized first\n")
+		}
+	case Unknown:
+		fmt.Fprintf(&buf, "search gave up on key %q\n", r.Key)
+	}
+	return buf.String()
+}
+
+// Check verifies that the history is linearizable with respect to a map of
+// registers, all of which are initially absent.
+func Check(history []Operation, opts Options) Result {
+	keys, parts := partition(history)
+	res := Result{Keys: len(keys)}
+	var unknown string
+	var sawUnknown bool
+	for _, key := range keys {
+		ops := parts[key]
+		res.Operations += len(ops)
+		s := search(ops, opts.MaxSteps)
+		switch s.outcome {
+		case Illegal:
+			res.Outcome = Illegal
+			res.Key = key
+			res.Counterexample, res.Linearized = minimize(ops, s, opts)
+			return res
+		case Unknown:
+			if !sawUnknown {
+				sawUnknown, unknown = true, key
+			}
+		}
+	}
+	if sawUnknown {
+		res.Outcome = Unknown
+		res.Key = unknown
+	}
+	return res
+}
+
+// minimize shrinks a non-linearizable partition to a 1-minimal
+// counterexample, that is one that becomes linearizable as soon as any one of
+// its operations is removed.
+func minimize(ops []Operation, s searchResult, opts Options) (cex, lin []Operation) {
+	limit := opts.MaxMinimizeOps
+	if limit == 0 {
+		limit = 256
+	}
+	cex = ops
+	if len(cex) <= limit {
+		for i := 0; i < len(cex); {
+			cand := slices.Delete(slices.Clone(cex), i, i+1)
+			if r := search(cand, opts.MaxSteps); r.outcome == Illegal {
+				cex, s = cand, r
+				continue
+			}
+			i++
+		}
+	}
+	for _, i := range s.longest {
+		lin = append(lin, cex[i])
+	}
+	return cex, lin
+}
+
+// register is the state of a single key.
+type register struct {
+	value string
+	found bool
+}
+
+// step applies the operation to the register, returning whether the
+// operation is legal in the given state and the resulting state.
+func (r register) step(op Operation) (bool, register) {
+	if op.Kind == Put {
+		return true, register{value: op.Value, found: true}
+	}
+	return op.Found == r.found && (!op.Found || op.Value == r.value), r
+}
+
+// entry is an invocation or a response in the doubly linked list of events
+// that the search operates on.
+type entry struct {
+	id     int
+	call   bool
+	time   int64
+	match  *entry // for calls, the corresponding return
+	prev   *entry
+	next   *entry
+	serial int // tie breaker for equal times
+}
+
+// lift removes the call entry and its matching return from the list.
+func (e *entry) lift() {
+	e.prev.next = e.next
+	e.next.prev = e.prev
+	m := e.match
+	m.prev.next = m.next
+	if m.next != nil {
+		m.next.prev = m.prev
+	}
+}
+
+// unlift undoes lift.
+func (e *entry) unlift() {
+	m := e.match
+	m.prev.next = m
+	if m.next != nil {
+		m.next.prev = m
+	}
+	e.prev.next = e
+	e.next.prev = e
+}
+
+// bitset tracks the set of linearized operations.
+type bitset []uint64
+
+func newBitset(n int) bitset { return make(bitset, (n+63)/64) }
+
+func (b bitset) set(i int)   { b[i/64] |= 1 << (uint(i) % 64) }
+func (b bitset) clear(i int) { b[i/64] &^= 1 << (uint(i) % 64) }
+
+func (b bitset) hash() uint64 {
+	h := uint64(14695981039346656037)
+	for _, w := range b {
+		h ^= w
+		h *= 1099511628211
+	}
+	return h
+}
+
+func (b bitset) equal(o bitset) bool { return slices.Equal(b, o) }
+
+type cacheEntry struct {
+	linearized bitset
+	state      register
+}
+
+type searchResult struct {
+	outcome Outcome
+	// longest holds the indexes of the operations in the longest
+	// linearization found, in linearization order.
+	longest []int
+}
+
+// search runs the Wing & Gong algorithm on a single partition.
+func search(ops []Operation, maxSteps int) searchResult {
+	events := make([]*entry, 0, 2*len(ops))
+	for i, op := range ops {
+		ret := &entry{id: i, time: op.Return, serial: 2*i + 1}
+		events = append(events, &entry{id: i, call: true, time: op.Call, match: ret, serial: 2 * i}, ret)
+	}
+	// Order by time. At equal times, invocations precede returns so that
+	// operations touching at their boundaries are considered concurrent.
+	slices.SortFunc(events, func(a, b *entry) int {
+		switch {
+		case a.time < b.time:
+			return -1
+		case a.time > b.time:
+			return 1
+		case a.call != b.call:
+			if a.call {
+				return -1
+			}
+			return 1
+		}
+		return a.serial - b.serial
+	})
+	head := &entry{id: -1}
+	prev := head
+	for _, e := range events {
+		prev.next, e.prev = e, prev
+		prev = e
+	}
+
+	type frame struct {
+		e     *entry
+		state register
+	}
+	var (
+		state      register
+		linearized = newBitset(len(ops))
+		cache      = make(map[uint64][]cacheEntry)
+		stack      []frame
+		longest    []int
+		steps      int
+	)
+	seen := func(b bitset, s register) bool {
+		h := b.hash()
+		for _, c := range cache[h] {
+			if c.state == s && c.linearized.equal(b) {
+				return true
+			}
+		}
+		cache[h] = append(cache[h], cacheEntry{linearized: slices.Clone(b), state: s})
+		return false
+	}
+
+	e := head.next
+	for head.next != nil {
+		if maxSteps > 0 {
+			if steps++; steps > maxSteps {
+				return searchResult{outcome: Unknown}
+			}
+		}
+		if e.call {
+			ok, next := state.step(ops[e.id])
+			if ok {
+				linearized.set(e.id)
+				if !seen(linearized, next) {
+					stack = append(stack, frame{e: e, state: state})
+					state = next
+					e.lift()
+					if len(stack) > len(longest) {
+						longest = longest[:0]
+						for _, f := range stack {
+							longest = append(longest, f.e.id)
+						}
+					}
+					e = head.next
+					continue
+				}
+				linearized.clear(e.id)
+			}
+			e = e.next
+			continue
+		}
+		// Reached the return of an operation that has not been linearized:
+		// backtrack.
+		if len(stack) == 0 {
+			return searchResult{outcome: Illegal, longest: longest}
+		}
+		f := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		state = f.state
+		linearized.clear(f.e.id)
+		f.e.unlift()
+		e = f.e.next
+	}
+	return searchResult{outcome: Ok}
+}
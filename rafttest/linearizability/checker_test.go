@@ -0,0 +1,166 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linearizability
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func put(client int, key, val string, call, ret int64) Operation {
+	return Operation{ClientID: client, Kind: Put, Key: key, Value: val, Call: call, Return: ret}
+}
+
+func get(client int, key, val string, call, ret int64) Operation {
+	return Operation{ClientID: client, Kind: Get, Key: key, Value: val, Found: val != "", Call: call, Return: ret}
+}
+
+func TestCheck(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		ops  []Operation
+		want Outcome
+	}{
+		{"empty", nil, Ok},
+		{"read-absent", []Operation{get(0, "a", "", 0, 1)}, Ok},
+		{"sequential", []Operation{
+			put(0, "a", "1", 0, 1),
+			get(0, "a", "1", 2, 3),
+			put(1, "a", "2", 4, 5),
+			get(1, "a", "2", 6, 7),
+		}, Ok},
+		{"concurrent-read-either", []Operation{
+			put(0, "a", "1", 0, 1),
+			put(1, "a", "2", 2, 10),
+			get(2, "a", "1", 3, 4),
+			get(3, "a", "2", 5, 6),
+		}, Ok},
+		{"stale-read", []Operation{
+			put(0, "a", "1", 0, 1),
+			put(1, "a", "2", 2, 3),
+			get(2, "a", "1", 4, 5),
+		}, Illegal},
+		{"read-goes-back", []Operation{
+			put(0, "a", "1", 0, 1),
+			put(1, "a", "2", 2, 10),
+			get(2, "a", "2", 3, 4),
+			get(3, "a", "1", 5, 6),
+		}, Illegal},
+		{"read-from-nowhere", []Operation{get(0, "a", "x", 0, 1)}, Illegal},
+		{"keys-are-independent", []Operation{
+			put(0, "a", "1", 0, 1),
+			put(0, "b", "2", 2, 3),
+			get(1, "a", "1", 4, 5),
+			get(1, "b", "2", 4, 5),
+		}, Ok},
+		{"unknown-put-takes-effect-late", []Operation{
+			{ClientID: 0, Kind: Put, Key: "a", Value: "1", Call: 0, Return: 1, Unknown: true},
+			get(1, "a", "", 2, 3),
+			get(1, "a", "1", 100, 101),
+		}, Ok},
+		{"unknown-get-ignored", []Operation{
+			put(0, "a", "1", 0, 1),
+			{ClientID: 1, Kind: Get, Key: "a", Value: "bogus", Found: true, Call: 2, Return: 3, Unknown: true},
+		}, Ok},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			res := Check(tt.ops, Options{})
+			assert.Equal(t, tt.want, res.Outcome, res.Report())
+		})
+	}
+}
+
+func TestCheckMinimizesCounterexample(t *testing.T) {
+	ops := []Operation{
+		put(0, "a", "1", 0, 1),
+		get(1, "a", "1", 2, 3),
+		put(2, "a", "2", 4, 5),
+		get(3, "a", "2", 6, 7),
+		put(0, "b", "x", 0, 1),
+		get(1, "a", "1", 8, 9), // stale
+		get(2, "a", "2", 10, 11),
+	}
+	res := Check(ops, Options{})
+	require.Equal(t, Illegal, res.Outcome)
+	assert.Equal(t, "a", res.Key)
+	assert.Less(t, len(res.Counterexample), len(ops))
+	assert.Equal(t, Illegal, Check(res.Counterexample, Options{}).Outcome)
+	for i := range res.Counterexample {
+		cand := slices.Delete(slices.Clone(res.Counterexample), i, i+1)
+		assert.Equal(t, Ok, Check(cand, Options{}).Outcome, "counterexample is not minimal: %v", cand)
+	}
+	assert.Contains(t, res.Report(), "minimal counterexample on key \"a\"")
+}
+
+func TestCheckMaxSteps(t *testing.T) {
+	var ops []Operation
+	// Many concurrent writes followed by a read of a value that was never
+	// written; proving illegality requires exploring all orders.
+	for i := 0; i < 12; i++ {
+		ops = append(ops, put(i, "a", fmt.Sprint(i), 0, 100))
+	}
+	ops = append(ops, get(12, "a", "none", 200, 201))
+	assert.Equal(t, Unknown, Check(ops, Options{MaxSteps: 10}).Outcome)
+	assert.Equal(t, Illegal, Check(ops, Options{}).Outcome)
+}
+
+// TestCheckRandomSequential verifies that histories produced by executing
+// operations against an actual map with random (but consistent) timings are
+// always found to be linearizable.
+func TestCheckRandomSequential(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for iter := 0; iter < 50; iter++ {
+		state := map[string]string{}
+		var ops []Operation
+		var now int64
+		for i := 0; i < 200; i++ {
+			key := fmt.Sprint(rng.Intn(3))
+			client := rng.Intn(5)
+			// Every operation takes effect at time now, but the interval
+			// recorded around it is randomly widened.
+			call := now - rng.Int63n(5)
+			ret := now + rng.Int63n(5)
+			if rng.Intn(2) == 0 {
+				val := fmt.Sprintf("%d-%d", iter, i)
+				state[key] = val
+				ops = append(ops, put(client, key, val, call, ret))
+			} else {
+				ops = append(ops, get(client, key, state[key], call, ret))
+			}
+			now += 3
+		}
+		res := Check(ops, Options{})
+		require.Equal(t, Ok, res.Outcome, res.Report())
+	}
+}
+
+func TestHistoryRoundTrip(t *testing.T) {
+	ops := []Operation{
+		put(0, "a", "1", 0, 1),
+		get(1, "a", "1", 2, 3),
+		{ClientID: 2, Kind: Put, Key: "b", Value: "2", Call: 4, Return: 5, Unknown: true},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, WriteHistory(&buf, ops))
+	got, err := ReadHistory(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, ops, got)
+}
@@ -0,0 +1,163 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package linearizability checks recorded client histories against a
+// key/value model.
+//
+// A history is a list of operations, each carrying the time at which the
+// client invoked it and the time at which the client observed its result.
+// The history is linearizable if every operation can be assigned a single
+// point in time between its invocation and its return such that the resulting
+// sequential execution is legal for a map of independent registers.
+//
+// The search follows Wing & Gong with the memoization introduced by Lowe (as
+// popularized by Knossos and Porcupine). Since keys are independent, the
+// history is partitioned per key and each partition is checked on its own,
+// which keeps the otherwise exponential search tractable.
+package linearizability
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strings"
+)
+
+// Kind is the type of an operation.
+type Kind string
+
+const (
+	// Get reads the value of a key.
+	Get Kind = "get"
+	// Put overwrites the value of a key.
+	Put Kind = "put"
+)
+
+// Operation is a single client operation in a history.
+type Operation struct {
+	// ClientID identifies the client that issued the operation. Operations of
+	// the same client must not overlap in time.
+	ClientID int `json:"client_id"`
+	// Kind is either Get or Put.
+	Kind Kind `json:"kind"`
+	// Key is the key the operation acted upon.
+	Key string `json:"key"`
+	// Value is the value written by a Put, or the value observed by a Get.
+	Value string `json:"value,omitempty"`
+	// Found is set for a Get that observed an existing key.
+	Found bool `json:"found,omitempty"`
+	// Call is the invocation time, in nanoseconds relative to an arbitrary
+	// but common origin.
+	Call int64 `json:"call"`
+	// Return is the time at which the result was observed, in the same unit
+	// and origin as Call.
+	Return int64 `json:"return"`
+	// Unknown is set if the client never learned the outcome of the
+	// operation (for example, because it timed out). An unknown Put may or
+	// may not have taken effect at any point after its invocation; an
+	// unknown Get carries no information and is ignored.
+	Unknown bool `json:"unknown,omitempty"`
+}
+
+func (op Operation) String() string {
+	var out string
+	switch {
+	case op.Kind == Put:
+		out = fmt.Sprintf("put(%q, %q)", op.Key, op.Value)
+	case op.Found:
+		out = fmt.Sprintf("get(%q) -> %q", op.Key, op.Value)
+	default:
+		out = fmt.Sprintf("get(%q) -> <none>", op.Key)
+	}
+	if op.Unknown {
+		out += " ?"
+	}
+	return out
+}
+
+// ReadHistory decodes a history written by WriteHistory.
+func ReadHistory(r io.Reader) ([]Operation, error) {
+	var ops []Operation
+	dec := json.NewDecoder(r)
+	for {
+		var op Operation
+		if err := dec.Decode(&op); err == io.EOF {
+			return ops, nil
+		} else if err != nil {
+			return nil, err
+		}
+		ops = append(ops, op)
+	}
+}
+
+// WriteHistory encodes the history as a stream of JSON objects, one per line.
+func WriteHistory(w io.Writer, ops []Operation) error {
+	enc := json.NewEncoder(w)
+	for _, op := range ops {
+		if err := enc.Encode(op); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// partition splits the history by key, dropping operations that carry no
+// information, and returns the keys in sorted order.
+func partition(ops []Operation) (keys []string, parts map[string][]Operation) {
+	parts = make(map[string][]Operation)
+	for _, op := range ops {
+		if op.Unknown {
+			if op.Kind != Put {
+				continue
+			}
+			// The write may take effect at any time after its invocation.
+			op.Return = math.MaxInt64
+		}
+		parts[op.Key] = append(parts[op.Key], op)
+	}
+	for k := range parts {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys, parts
+}
+
+// describe renders the operations as a timeline sorted by invocation time.
+func describe(ops []Operation) string {
+	sorted := slices.Clone(ops)
+	slices.SortStableFunc(sorted, func(a, b Operation) int {
+		if a.Call != b.Call {
+			if a.Call < b.Call {
+				return -1
+			}
+			return 1
+		}
+		return a.ClientID - b.ClientID
+	})
+	var origin int64
+	if len(sorted) > 0 {
+		origin = sorted[0].Call
+	}
+	var buf strings.Builder
+	for _, op := range sorted {
+		ret := "∞"
+		if op.Return != math.MaxInt64 {
+			ret = fmt.Sprintf("%d", op.Return-origin)
+		}
+		fmt.Fprintf(&buf, "client %d [%d, %s] %s\n", op.ClientID, op.Call-origin, ret, op)
+	}
+	return buf.String()
+}
@@ -16,6 +16,7 @@ package raft
 
 import (
 	"errors"
+	"time"
 
 	pb "go.etcd.io/raft/v3/raftpb"
 	"go.etcd.io/raft/v3/tracker"
@@ -24,6 +25,15 @@ import (
 // ErrStepLocalMsg is returned when try to step a local raft message
 var ErrStepLocalMsg = errors.New("raft: cannot step raft local message")
 
+// ErrStepSnapChunk is returned when trying to step a message streaming
+// snapshot data. Such messages are handled by the snapstream package.
+var ErrStepSnapChunk = errors.New("raft: cannot step snapshot chunk message")
+
+// ErrStepCompressed is returned when trying to step a message carrying
+// compressed entries. The compression package undoes the compression applied
+// by the sender.
+var ErrStepCompressed = errors.New("raft: cannot step message with compressed entries")
+
 // ErrStepPeerNotFound is returned when try to step a response message
 // but there is no peer found in raft.trk for that node.
 var ErrStepPeerNotFound = errors.New("raft: cannot step as peer not found")
@@ -120,6 +130,12 @@ func (rn *RawNode) Step(m pb.Message) error {
 	if IsLocalMsg(m.Type) && !IsLocalMsgTarget(m.From) {
 		return ErrStepLocalMsg
 	}
+	if isSnapChunkMsg(m.Type) {
+		return ErrStepSnapChunk
+	}
+	if hasCompressedEntries(m) {
+		return ErrStepCompressed
+	}
 	if IsResponseMsg(m.Type) && !IsLocalMsgTarget(m.From) && rn.raft.trk.Progress[m.From] == nil {
 		return ErrStepPeerNotFound
 	}
@@ -435,6 +451,7 @@ func (rn *RawNode) acceptReady(rd Ready) {
 	rn.raft.raftLog.acceptUnstable()
 	if len(rd.CommittedEntries) > 0 {
 		ents := rd.CommittedEntries
+		rn.raft.checkHashes(ents)
 		index := ents[len(ents)-1].Index
 		rn.raft.raftLog.acceptApplying(index, entsSize(ents), rn.applyUnstableEntries())
 	}
@@ -514,6 +531,8 @@ const (
 	ProgressTypePeer ProgressType = iota
 	// ProgressTypeLearner accompanies a Progress for a learner replica.
 	ProgressTypeLearner
+	// ProgressTypeWitness accompanies a Progress for a witness replica.
+	ProgressTypeWitness
 )
 
 // WithProgress is a helper to introspect the Progress for this node and its
@@ -523,6 +542,8 @@ func (rn *RawNode) WithProgress(visitor func(id uint64, typ ProgressType, pr tra
 		typ := ProgressTypePeer
 		if pr.IsLearner {
 			typ = ProgressTypeLearner
+		} else if pr.IsWitness {
+			typ = ProgressTypeWitness
 		}
 		p := *pr
 		p.Inflights = nil
@@ -535,6 +556,25 @@ func (rn *RawNode) ReportUnreachable(id uint64) {
 	_ = rn.raft.Step(pb.Message{Type: pb.MsgUnreachable, From: id})
 }
 
+// ReportHash reports the hash of the state machine after applying the hash
+// check marker at the given index (see Config.HashCheckInterval).
+func (rn *RawNode) ReportHash(index, hash uint64) {
+	_ = rn.raft.Step(stateHashMsg(index, hash))
+}
+
+// ReportEntries reports the entries read from lo in the background after
+// AsyncStorage.FetchEntries returned ErrEntriesPending, or the error the read
+// failed with.
+func (rn *RawNode) ReportEntries(lo uint64, ents []pb.Entry, err error) {
+	_ = rn.raft.Step(storageEntriesMsg(lo, ents, err))
+}
+
+// Wake wakes up the node if it is quiesced, making it tick again (see
+// Config.Quiesce).
+func (rn *RawNode) Wake() {
+	_ = rn.raft.Step(pb.Message{Type: pb.MsgWake})
+}
+
 // ReportSnapshot reports the status of the sent snapshot.
 func (rn *RawNode) ReportSnapshot(id uint64, status SnapshotStatus) {
 	rej := status == SnapshotFailure
@@ -553,6 +593,15 @@ func (rn *RawNode) ForgetLeader() error {
 	return rn.raft.Step(pb.Message{Type: pb.MsgForgetLeader})
 }
 
+// SafeReadIndex returns the safe read index known to this node if reads
+// served at it have a staleness of at most maxStaleness (see
+// Config.FollowerReads). Once the application has applied the returned index,
+// it can serve a read reflecting all writes that completed more than
+// maxStaleness ago, without contacting the leader.
+func (rn *RawNode) SafeReadIndex(maxStaleness time.Duration) (uint64, bool) {
+	return rn.raft.lease.safeReadIndex(rn.raft.lease.safeRead, maxStaleness)
+}
+
 // ReadIndex requests a read state. The read state will be set in ready.
 // Read State has a read index. Once the application advances further than the read
 // index, any linearizable read requests issued before the read request can be
@@ -98,10 +98,26 @@ func TestRawNodeStep(t *testing.T) {
 			if IsLocalMsg(msgt) {
 				assert.Equal(t, ErrStepLocalMsg, err, "#%d", i)
 			}
+			if isSnapChunkMsg(msgt) {
+				assert.Equal(t, ErrStepSnapChunk, err, "#%d", i)
+			}
 		})
 	}
 }
 
+// TestRawNodeStepCompressed ensures that messages carrying compressed entries
+// are refused rather than appended to the log.
+func TestRawNodeStepCompressed(t *testing.T) {
+	s := newTestMemoryStorage(withPeers(1, 2))
+	rawNode, err := NewRawNode(newTestConfig(1, 10, 1, s))
+	require.NoError(t, err)
+	m := pb.Message{From: 2, To: 1, Term: 1, Type: pb.MsgApp, Entries: []pb.Entry{
+		{Term: 1, Index: 1, Data: []byte("x")},
+	}, EntryCodecs: []pb.EntryCodec{pb.CodecFlate}}
+	require.Equal(t, ErrStepCompressed, rawNode.Step(m))
+	require.Zero(t, rawNode.raft.raftLog.lastIndex())
+}
+
 // TestNodeStepUnblock from node_test.go has no equivalent in rawNode because there is
 // no goroutine in RawNode.
 
@@ -0,0 +1,106 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapstream
+
+import (
+	"fmt"
+
+	pb "go.etcd.io/raft/v3/raftpb"
+)
+
+// Receiver receives the data of snapshots streamed by Senders, and writes it
+// to a Sink. It is not safe for concurrent use.
+type Receiver struct {
+	sink Sink
+	// streams holds the snapshot being received from each peer, and the
+	// offset of the next chunk expected, or the last snapshot received from
+	// the peer once finished.
+	streams map[uint64]stream
+}
+
+type stream struct {
+	key  snapshotKey
+	next uint64
+	// done is set once all data was received, after which the sender is
+	// only told so again, in case the final acknowledgement was lost.
+	done bool
+}
+
+// NewReceiver returns a Receiver writing to the given Sink.
+func NewReceiver(sink Sink) *Receiver {
+	return &Receiver{sink: sink, streams: map[uint64]stream{}}
+}
+
+// Step handles a MsgSnapChunk. It returns the MsgSnapChunkResp to send back
+// and, once all data of the snapshot has been received, the MsgSnap to step
+// into raft.
+func (r *Receiver) Step(m pb.Message) (resp pb.Message, snap *pb.Message, _ error) {
+	if m.Type != pb.MsgSnapChunk || m.Chunk == nil || m.Snapshot == nil {
+		return pb.Message{}, nil, fmt.Errorf("unexpected %s", m.Type)
+	}
+	meta := m.Snapshot.Metadata
+	st, ok := r.streams[m.From]
+	if !ok || st.key != keyOf(meta) {
+		// A new stream, possibly resuming data received before.
+		next, err := r.sink.Received(meta)
+		if err != nil {
+			return pb.Message{}, nil, err
+		}
+		st = stream{key: keyOf(meta), next: next}
+		r.streams[m.From] = st
+	}
+	resp = pb.Message{
+		Type:     pb.MsgSnapChunkResp,
+		To:       m.From,
+		From:     m.To,
+		Term:     m.Term,
+		Index:    m.Index,
+		Snapshot: &pb.Snapshot{Metadata: pb.SnapshotMetadata{Index: meta.Index, Term: meta.Term}},
+		Chunk:    &pb.SnapshotChunk{Offset: st.next},
+	}
+	if st.done {
+		resp.Chunk.Last = true
+		return resp, nil, nil
+	}
+	c := m.Chunk
+	if c.Offset != st.next || checksum(c.Data) != c.Checksum {
+		// Ask for the expected data. Chunks following a refused one are
+		// refused as well, but the sender only rewinds once per attempt.
+		resp.Reject = true
+		return resp, nil, nil
+	}
+	if err := r.sink.Write(meta, st.next, c.Data); err != nil {
+		return pb.Message{}, nil, err
+	}
+	st.next += uint64(len(c.Data))
+	r.streams[m.From] = st
+	resp.Chunk.Offset = st.next
+	if !c.Last {
+		return resp, nil, nil
+	}
+	if err := r.sink.Finish(meta); err != nil {
+		return pb.Message{}, nil, err
+	}
+	st.done = true
+	r.streams[m.From] = st
+	resp.Chunk.Last = true
+	return resp, &pb.Message{
+		Type:     pb.MsgSnap,
+		To:       m.To,
+		From:     m.From,
+		Term:     m.Term,
+		Snapshot: m.Snapshot,
+	}, nil
+}
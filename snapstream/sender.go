@@ -0,0 +1,168 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapstream
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	pb "go.etcd.io/raft/v3/raftpb"
+)
+
+// Config configures a Sender.
+type Config struct {
+	// ChunkSize is the maximum number of bytes of data per chunk. Defaults to
+	// 1 MiB.
+	ChunkSize int
+	// MaxInflight is the maximum number of chunks sent but not acknowledged
+	// yet. Defaults to 4.
+	MaxInflight int
+}
+
+const (
+	defaultChunkSize   = 1 << 20
+	defaultMaxInflight = 4
+)
+
+// Sender streams the data of a snapshot to a follower. It is not safe for
+// concurrent use.
+type Sender struct {
+	cfg Config
+	msg pb.Message // the MsgSnap
+	src Source
+
+	// acked is the offset up to which the receiver acknowledged the data.
+	acked uint64
+	// next is the offset of the next chunk to send.
+	next uint64
+	// inflight holds the end offsets of the chunks sent but not acknowledged
+	// yet, in increasing order.
+	inflight []uint64
+	// sentLast is set once the last chunk has been sent, and done once it has
+	// been acknowledged.
+	sentLast, done bool
+	// attempt counts the rewinds. It is sent in the Index field of the chunks
+	// and echoed by the receiver, which allows telling apart the refusals of
+	// chunks sent before the last rewind.
+	attempt uint64
+}
+
+// NewSender returns a Sender streaming the snapshot of the given MsgSnap,
+// reading its data from src. The MsgSnap is delivered to the receiver along
+// with the last chunk.
+func NewSender(m pb.Message, src Source, cfg Config) (*Sender, error) {
+	if m.Type != pb.MsgSnap || m.Snapshot == nil {
+		return nil, fmt.Errorf("%s is not a snapshot message", m.Type)
+	}
+	if cfg.ChunkSize <= 0 {
+		cfg.ChunkSize = defaultChunkSize
+	}
+	if cfg.MaxInflight <= 0 {
+		cfg.MaxInflight = defaultMaxInflight
+	}
+	return &Sender{cfg: cfg, msg: m, src: src}, nil
+}
+
+// To returns the ID of the peer the snapshot is sent to.
+func (s *Sender) To() uint64 { return s.msg.To }
+
+// Done returns true once the receiver acknowledged all data.
+func (s *Sender) Done() bool { return s.done }
+
+// Msgs returns the MsgSnapChunk messages to send, as many as the flow control
+// window permits.
+func (s *Sender) Msgs() ([]pb.Message, error) {
+	var msgs []pb.Message
+	for !s.sentLast && len(s.inflight) < s.cfg.MaxInflight {
+		buf := make([]byte, s.cfg.ChunkSize)
+		n, err := s.src.ReadAt(s.msg.Snapshot.Metadata, buf, int64(s.next))
+		last := errors.Is(err, io.EOF)
+		if err != nil && !last {
+			return msgs, err
+		}
+		chunk := &pb.SnapshotChunk{
+			Offset:   s.next,
+			Data:     buf[:n],
+			Checksum: checksum(buf[:n]),
+			Last:     last,
+		}
+		snap := &pb.Snapshot{Metadata: s.msg.Snapshot.Metadata}
+		if last {
+			snap = s.msg.Snapshot
+		}
+		msgs = append(msgs, pb.Message{
+			Type:     pb.MsgSnapChunk,
+			To:       s.msg.To,
+			From:     s.msg.From,
+			Term:     s.msg.Term,
+			Index:    s.attempt,
+			Snapshot: snap,
+			Chunk:    chunk,
+		})
+		s.next += uint64(n)
+		s.inflight = append(s.inflight, s.next)
+		s.sentLast = last
+	}
+	return msgs, nil
+}
+
+// Step handles a MsgSnapChunkResp from the receiver. Responses for other
+// snapshots are ignored.
+func (s *Sender) Step(m pb.Message) error {
+	if m.Type != pb.MsgSnapChunkResp || m.Chunk == nil || m.Snapshot == nil {
+		return fmt.Errorf("unexpected %s", m.Type)
+	}
+	if m.From != s.msg.To || keyOf(m.Snapshot.Metadata) != keyOf(s.msg.Snapshot.Metadata) {
+		return nil
+	}
+	if m.Reject {
+		if m.Index != s.attempt || s.done {
+			return nil // refers to a chunk sent before rewinding
+		}
+		// The receiver expects data at a different offset, either because
+		// chunks were lost or corrupted, or because it already has more data
+		// than this sender assumed.
+		s.acked = m.Chunk.Offset
+		s.rewind()
+		return nil
+	}
+	if m.Chunk.Offset < s.acked {
+		return nil // stale
+	}
+	s.acked = m.Chunk.Offset
+	for len(s.inflight) > 0 && s.inflight[0] <= s.acked {
+		s.inflight = s.inflight[1:]
+	}
+	if m.Chunk.Last {
+		s.done = true
+	}
+	return nil
+}
+
+// Rewind makes the sender resend all data not acknowledged yet. It is called
+// when the connection to the receiver was dropped, and chunks may have been
+// lost.
+func (s *Sender) Rewind() {
+	if !s.done {
+		s.rewind()
+	}
+}
+
+func (s *Sender) rewind() {
+	s.next = s.acked
+	s.inflight, s.sentLast = nil, false
+	s.attempt++
+}
@@ -0,0 +1,150 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package snapstream streams the data of snapshots between peers in chunks,
+// so that snapshots don't have to fit in memory or in a single message.
+//
+// Raft sends a snapshot to a follower in a MsgSnap message. With snapstream,
+// the snapshot in the MsgSnap (as returned by raft.Storage.Snapshot) only
+// carries the metadata and, optionally, a small amount of data, while the bulk
+// of the data is read from a Source on the sender and written to a Sink on
+// the receiver. The application hands the MsgSnap to a Sender instead of
+// sending it. The Sender produces MsgSnapChunk messages, which the application
+// delivers to the Receiver on the follower. The Receiver writes the chunks to
+// its Sink and acknowledges them with MsgSnapChunkResp messages, which the
+// application delivers back to the Sender. Once all data has been received,
+// the Receiver returns the original MsgSnap, to be stepped into the raft node
+// of the follower, and the application on the leader reports the outcome to
+// raft via ReportSnapshot. Neither message type is ever stepped into raft.
+//
+// Each chunk carries a CRC-32C checksum of its data, and chunks failing the
+// check are refused. The Sender keeps at most Config.MaxInflight chunks
+// unacknowledged. After a dropped connection, the Sender resumes from the
+// data acknowledged so far (see Sender.Rewind). The Receiver asks for the
+// chunk it expects whenever it receives a different one, which also lets a
+// new Sender resume a stream from the data that the Sink already stored.
+// The Receiver remembers the last snapshot it received from each peer, and
+// acknowledges it again to a Sender which rewinds after the final
+// acknowledgement was lost, rather than having it resend all data.
+// Chunks carry the number of rewinds of the Sender in the Index field, which
+// the Receiver echoes so that refusals of chunks sent before a rewind can be
+// told apart.
+package snapstream
+
+import (
+	"fmt"
+	"hash/crc32"
+	"io"
+
+	pb "go.etcd.io/raft/v3/raftpb"
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+func checksum(data []byte) uint32 { return crc32.Checksum(data, crcTable) }
+
+// Source produces the data of outgoing snapshots.
+type Source interface {
+	// ReadAt reads the data of the snapshot with the given metadata at the
+	// given offset into p, with the semantics of io.ReaderAt. In particular,
+	// it returns io.EOF when reaching the end of the data.
+	ReadAt(meta pb.SnapshotMetadata, p []byte, off int64) (int, error)
+}
+
+// Sink consumes the data of incoming snapshots.
+type Sink interface {
+	// Received returns the number of bytes of the data of the snapshot with
+	// the given metadata that were written before. A stream resumes from
+	// there, so the Sink should only count data that was stored durably.
+	Received(meta pb.SnapshotMetadata) (uint64, error)
+	// Write stores data at the given offset of the data of the snapshot.
+	// The offset is always the number of bytes received so far.
+	Write(meta pb.SnapshotMetadata, off uint64, data []byte) error
+	// Finish is called once all data of the snapshot has been written, before
+	// the snapshot is handed to raft.
+	Finish(meta pb.SnapshotMetadata) error
+}
+
+// snapshotKey identifies a snapshot.
+type snapshotKey struct {
+	term, index uint64
+}
+
+func keyOf(meta pb.SnapshotMetadata) snapshotKey {
+	return snapshotKey{term: meta.Term, index: meta.Index}
+}
+
+func (k snapshotKey) String() string {
+	return fmt.Sprintf("%d/%d", k.term, k.index)
+}
+
+// MemorySource is a Source holding the data of snapshots in memory, keyed by
+// their index.
+type MemorySource map[uint64][]byte
+
+// ReadAt implements Source.
+func (s MemorySource) ReadAt(meta pb.SnapshotMetadata, p []byte, off int64) (int, error) {
+	data, ok := s[meta.Index]
+	if !ok {
+		return 0, fmt.Errorf("no data for snapshot at index %d", meta.Index)
+	}
+	if off >= int64(len(data)) {
+		return 0, io.EOF
+	}
+	n := copy(p, data[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// MemorySink is a Sink keeping the data of snapshots in memory, keyed by
+// their index.
+type MemorySink struct {
+	partial  map[uint64][]byte
+	finished map[uint64][]byte
+}
+
+// NewMemorySink returns an empty MemorySink.
+func NewMemorySink() *MemorySink {
+	return &MemorySink{partial: map[uint64][]byte{}, finished: map[uint64][]byte{}}
+}
+
+// Received implements Sink.
+func (s *MemorySink) Received(meta pb.SnapshotMetadata) (uint64, error) {
+	return uint64(len(s.partial[meta.Index])), nil
+}
+
+// Write implements Sink.
+func (s *MemorySink) Write(meta pb.SnapshotMetadata, off uint64, data []byte) error {
+	if cur := uint64(len(s.partial[meta.Index])); off != cur {
+		return fmt.Errorf("write at offset %d, but %d bytes were received", off, cur)
+	}
+	s.partial[meta.Index] = append(s.partial[meta.Index], data...)
+	return nil
+}
+
+// Finish implements Sink.
+func (s *MemorySink) Finish(meta pb.SnapshotMetadata) error {
+	s.finished[meta.Index] = s.partial[meta.Index]
+	delete(s.partial, meta.Index)
+	return nil
+}
+
+// Data returns the data of the snapshot at the given index, once it has been
+// received completely.
+func (s *MemorySink) Data(index uint64) ([]byte, bool) {
+	data, ok := s.finished[index]
+	return data, ok
+}
@@ -0,0 +1,231 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapstream
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	pb "go.etcd.io/raft/v3/raftpb"
+)
+
+func snapMsg(index uint64) pb.Message {
+	return pb.Message{
+		Type: pb.MsgSnap,
+		From: 1,
+		To:   2,
+		Term: 3,
+		Snapshot: &pb.Snapshot{
+			Metadata: pb.SnapshotMetadata{Index: index, Term: 2},
+			Data:     []byte("small"),
+		},
+	}
+}
+
+// transfer runs the stream until the sender is done, passing each chunk
+// through the given filter, which may modify or drop it. When a round of
+// messages yields no response at all, the connection is considered dropped
+// and the sender rewinds. It returns the MsgSnap produced by the receiver.
+func transfer(t *testing.T, s *Sender, r *Receiver, filter func(*pb.Message) bool) *pb.Message {
+	var snap *pb.Message
+	for i := 0; !s.Done(); i++ {
+		require.Less(t, i, 1000, "stream doesn't make progress")
+		msgs, err := s.Msgs()
+		require.NoError(t, err)
+		require.LessOrEqual(t, len(msgs), s.cfg.MaxInflight)
+		var resps []pb.Message
+		for _, m := range msgs {
+			if filter != nil && !filter(&m) {
+				continue
+			}
+			resp, sn, err := r.Step(m)
+			require.NoError(t, err)
+			resps = append(resps, resp)
+			if sn != nil {
+				require.Nil(t, snap, "snapshot received twice")
+				snap = sn
+			}
+		}
+		if len(resps) == 0 {
+			s.Rewind()
+		}
+		for _, resp := range resps {
+			require.NoError(t, s.Step(resp))
+		}
+	}
+	return snap
+}
+
+func TestTransfer(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 110)
+	for _, size := range []int{0, 1, 10, 999, 1000, 1001} {
+		src := MemorySource{10: data[:size]}
+		sink := NewMemorySink()
+		s, err := NewSender(snapMsg(10), src, Config{ChunkSize: 64, MaxInflight: 3})
+		require.NoError(t, err)
+		snap := transfer(t, s, NewReceiver(sink), nil)
+		require.NotNil(t, snap)
+		require.Equal(t, pb.MsgSnap, snap.Type)
+		require.Equal(t, snapMsg(10).Snapshot, snap.Snapshot)
+		got, ok := sink.Data(10)
+		require.True(t, ok)
+		require.Equal(t, string(data[:size]), string(got))
+	}
+}
+
+func TestTransferLossAndCorruption(t *testing.T) {
+	data := bytes.Repeat([]byte("abcdefghij"), 100)
+	for _, tc := range []struct {
+		name   string
+		filter func(*pb.Message) bool
+	}{
+		{"drop", func() func(*pb.Message) bool {
+			n := 0
+			return func(*pb.Message) bool { n++; return n%5 != 0 }
+		}()},
+		{"drop-all-once", func() func(*pb.Message) bool {
+			n := 0
+			return func(*pb.Message) bool { n++; return n < 4 || n > 8 }
+		}()},
+		{"corrupt", func() func(*pb.Message) bool {
+			n := 0
+			return func(m *pb.Message) bool {
+				n++
+				if n%4 == 0 && len(m.Chunk.Data) > 0 {
+					c := *m.Chunk
+					c.Data = append([]byte{c.Data[0] + 1}, c.Data[1:]...)
+					m.Chunk = &c
+				}
+				return true
+			}
+		}()},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			sink := NewMemorySink()
+			s, err := NewSender(snapMsg(10), MemorySource{10: data}, Config{ChunkSize: 50, MaxInflight: 4})
+			require.NoError(t, err)
+			require.NotNil(t, transfer(t, s, NewReceiver(sink), tc.filter))
+			got, ok := sink.Data(10)
+			require.True(t, ok)
+			require.Equal(t, data, got)
+		})
+	}
+}
+
+// TestResume verifies that a new sender resumes a stream from the data that
+// the sink already received.
+func TestResume(t *testing.T) {
+	data := bytes.Repeat([]byte("xyz"), 100)
+	src := MemorySource{10: data}
+	sink := NewMemorySink()
+	r := NewReceiver(sink)
+
+	s, err := NewSender(snapMsg(10), src, Config{ChunkSize: 30, MaxInflight: 2})
+	require.NoError(t, err)
+	msgs, err := s.Msgs()
+	require.NoError(t, err)
+	require.Len(t, msgs, 2)
+	for _, m := range msgs {
+		_, snap, err := r.Step(m)
+		require.NoError(t, err)
+		require.Nil(t, snap)
+	}
+	received, err := sink.Received(snapMsg(10).Snapshot.Metadata)
+	require.NoError(t, err)
+	require.Equal(t, uint64(60), received)
+
+	// Both the sender and the receiver restart. The new sender starts from
+	// scratch and is told to skip ahead.
+	s, err = NewSender(snapMsg(10), src, Config{ChunkSize: 30, MaxInflight: 2})
+	require.NoError(t, err)
+	r = NewReceiver(sink)
+	msgs, err = s.Msgs()
+	require.NoError(t, err)
+	resp, _, err := r.Step(msgs[0])
+	require.NoError(t, err)
+	require.True(t, resp.Reject)
+	require.Equal(t, uint64(60), resp.Chunk.Offset)
+	require.NoError(t, s.Step(resp))
+	msgs, err = s.Msgs()
+	require.NoError(t, err)
+	require.Equal(t, uint64(60), msgs[0].Chunk.Offset)
+
+	require.NotNil(t, transfer(t, s, r, nil))
+	got, ok := sink.Data(10)
+	require.True(t, ok)
+	require.Equal(t, data, got)
+}
+
+// TestLostFinalAck verifies that a sender rewinding after the final
+// acknowledgement was lost is told the snapshot was received, rather than
+// sending all data again.
+func TestLostFinalAck(t *testing.T) {
+	data := bytes.Repeat([]byte("xyz"), 100)
+	sink := NewMemorySink()
+	r := NewReceiver(sink)
+	s, err := NewSender(snapMsg(10), MemorySource{10: data}, Config{ChunkSize: 30, MaxInflight: 20})
+	require.NoError(t, err)
+
+	msgs, err := s.Msgs()
+	require.NoError(t, err)
+	require.Len(t, msgs, 11)
+	var snap *pb.Message
+	for i, m := range msgs {
+		resp, sn, err := r.Step(m)
+		require.NoError(t, err)
+		if sn != nil {
+			snap = sn
+		}
+		if i < len(msgs)-1 {
+			require.NoError(t, s.Step(resp))
+		}
+	}
+	require.NotNil(t, snap)
+	require.False(t, s.Done())
+
+	// The sender resends the last chunk only, which is acknowledged without
+	// handing the snapshot to raft again.
+	s.Rewind()
+	msgs, err = s.Msgs()
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+	require.Equal(t, uint64(300), msgs[0].Chunk.Offset)
+	resp, sn, err := r.Step(msgs[0])
+	require.NoError(t, err)
+	require.Nil(t, sn)
+	require.False(t, resp.Reject)
+	require.NoError(t, s.Step(resp))
+	require.True(t, s.Done())
+
+	// So is a chunk sent from an earlier offset.
+	resp, sn, err = r.Step(pb.Message{Type: pb.MsgSnapChunk, From: 1, To: 2, Term: 3,
+		Snapshot: &pb.Snapshot{Metadata: snapMsg(10).Snapshot.Metadata}, Chunk: &pb.SnapshotChunk{}})
+	require.NoError(t, err)
+	require.Nil(t, sn)
+	require.True(t, resp.Chunk.Last)
+	require.Equal(t, uint64(300), resp.Chunk.Offset)
+
+	// A new snapshot is streamed as usual.
+	s, err = NewSender(snapMsg(20), MemorySource{20: data}, Config{ChunkSize: 30})
+	require.NoError(t, err)
+	require.NotNil(t, transfer(t, s, r, nil))
+}
+
+func TestNewSenderRequiresSnapshot(t *testing.T) {
+	_, err := NewSender(pb.Message{Type: pb.MsgApp}, MemorySource{}, Config{})
+	require.Error(t, err)
+}
@@ -16,6 +16,7 @@ package raft
 
 import (
 	"fmt"
+	"time"
 
 	pb "go.etcd.io/raft/v3/raftpb"
 	"go.etcd.io/raft/v3/tracker"
@@ -27,6 +28,9 @@ type Status struct {
 	BasicStatus
 	Config   tracker.Config
 	Progress map[uint64]tracker.Progress
+	// EntryCache contains statistics about the entry cache (see
+	// Config.EntryCacheSize).
+	EntryCache EntryCacheStats
 }
 
 // BasicStatus contains basic information about the Raft peer. It does not allocate.
@@ -39,6 +43,23 @@ type BasicStatus struct {
 	Applied uint64
 
 	LeadTransferee uint64
+
+	// LeaseExpiry is the time until which the leader holds its lease, or the
+	// zero time if this peer isn't a leader holding one (see Config.LeaseDuration).
+	// While the lease is held, no other leader can have been elected, so the
+	// leader may serve a linearizable read from its state machine without
+	// consulting the group, provided the read arrived before LeaseExpiry
+	// (according to Config.Clock) and the state machine has applied all
+	// entries up to HardState.Commit.
+	LeaseExpiry time.Time
+
+	// SafeRead is the latest safe read index known to this peer (see
+	// Config.FollowerReads).
+	SafeRead SafeRead
+
+	// Quiesced is set while the peer doesn't tick, because its group is idle
+	// (see Config.Quiesce). Calling Tick is then a no-op.
+	Quiesced bool
 }
 
 func getProgressCopy(r *raft) map[uint64]tracker.Progress {
@@ -61,6 +82,9 @@ func getBasicStatus(r *raft) BasicStatus {
 	s.HardState = r.hardState()
 	s.SoftState = r.softState()
 	s.Applied = r.raftLog.applied
+	s.LeaseExpiry = r.leaseExpiry()
+	s.SafeRead = r.lease.safeRead
+	s.Quiesced = r.quiesced
 	return s
 }
 
@@ -72,6 +96,7 @@ func getStatus(r *raft) Status {
 		s.Progress = getProgressCopy(r)
 	}
 	s.Config = r.trk.Config.Clone()
+	s.EntryCache = r.raftLog.cache.stats()
 	return s
 }
 
@@ -37,6 +37,10 @@ var ErrUnavailable = errors.New("requested entry at index is unavailable")
 // snapshot is temporarily unavailable.
 var ErrSnapshotTemporarilyUnavailable = errors.New("snapshot is temporarily unavailable")
 
+// ErrEntriesPending is returned by AsyncStorage.FetchEntries when the
+// requested entries are being read in the background.
+var ErrEntriesPending = errors.New("entries are pending")
+
 // Storage is an interface that may be implemented by the application
 // to retrieve log entries from storage.
 //
@@ -89,6 +93,25 @@ type Storage interface {
 	Snapshot() (pb.Snapshot, error)
 }
 
+// AsyncStorage is a Storage which can read log entries in the background. It
+// is used by the leader to read the entries it sends to followers when
+// Config.AsyncStorageReads is enabled, so that reading the entries of a
+// follower catching up doesn't block the raft node.
+type AsyncStorage interface {
+	Storage
+
+	// FetchEntries is like Entries, but may return ErrEntriesPending instead
+	// of blocking on reading the entries, after starting to read them in the
+	// background. Once read, the application delivers the entries with
+	// RawNode.ReportEntries (or Node.ReportEntries), passing lo, which makes
+	// the leader send them. Failed reads are reported the same way, and raft
+	// calls FetchEntries again when it next tries to send the entries.
+	//
+	// Raft doesn't call FetchEntries again for the same lo until the read in
+	// progress is reported. It may call it with other ranges in the meantime.
+	FetchEntries(lo, hi, maxSize uint64) ([]pb.Entry, error)
+}
+
 type inMemStorageCallStats struct {
 	initialState, firstIndex, lastIndex, entries, term, snapshot int
 }
@@ -0,0 +1,256 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storagetest
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.etcd.io/raft/v3"
+	pb "go.etcd.io/raft/v3/raftpb"
+)
+
+// unavailableSnapshots makes Snapshot return
+// raft.ErrSnapshotTemporarilyUnavailable the given number of times.
+type unavailableSnapshots struct {
+	Storage
+	failures int
+}
+
+func (s *unavailableSnapshots) Snapshot() (pb.Snapshot, error) {
+	if s.failures > 0 {
+		s.failures--
+		return pb.Snapshot{}, raft.ErrSnapshotTemporarilyUnavailable
+	}
+	return s.Storage.Snapshot()
+}
+
+// groupNode is a member of an in-process raft group, whose state machine
+// records the data of the applied entries.
+type groupNode struct {
+	rn      *raft.RawNode
+	storage *unavailableSnapshots
+	applied []string
+	index   uint64
+	cs      pb.ConfState
+}
+
+// group is an in-process raft group.
+type group struct {
+	t     *testing.T
+	nodes map[uint64]*groupNode
+	msgs  []pb.Message
+	// isolated holds the nodes whose messages are dropped.
+	isolated map[uint64]bool
+	// compactEvery makes the nodes compact their log once they applied this
+	// many entries since the last compaction.
+	compactEvery uint64
+}
+
+func newGroup(t *testing.T, newStorage func(*testing.T) Storage, n int) *group {
+	g := &group{t: t, nodes: map[uint64]*groupNode{}, isolated: map[uint64]bool{}, compactEvery: 10}
+	cs := pb.ConfState{}
+	for id := uint64(1); id <= uint64(n); id++ {
+		cs.Voters = append(cs.Voters, id)
+	}
+	for id := uint64(1); id <= uint64(n); id++ {
+		s := &unavailableSnapshots{Storage: newStorage(t)}
+		require.NoError(t, s.ApplySnapshot(pb.Snapshot{Metadata: pb.SnapshotMetadata{Index: 1, Term: 1, ConfState: cs}}))
+		rn, err := raft.NewRawNode(&raft.Config{
+			ID:              id,
+			ElectionTick:    10,
+			HeartbeatTick:   1,
+			Storage:         s,
+			Applied:         1,
+			MaxSizePerMsg:   1 << 10,
+			MaxInflightMsgs: 16,
+			Logger:          &raft.DefaultLogger{Logger: log.New(io.Discard, "", 0)},
+		})
+		require.NoError(t, err)
+		g.nodes[id] = &groupNode{rn: rn, storage: s, index: 1, cs: cs}
+	}
+	return g
+}
+
+// stabilize handles the Ready of all nodes and delivers all messages until
+// there is no more work.
+func (g *group) stabilize() {
+	for i := 0; ; i++ {
+		require.Less(g.t, i, 10000, "group doesn't stabilize")
+		done := true
+		for id := uint64(1); id <= uint64(len(g.nodes)); id++ {
+			if n := g.nodes[id]; n.rn.HasReady() {
+				done = false
+				g.handleReady(n)
+			}
+		}
+		msgs := g.msgs
+		g.msgs = nil
+		for _, m := range msgs {
+			done = false
+			if g.isolated[m.From] || g.isolated[m.To] {
+				continue
+			}
+			// Messages are stepped regardless of errors such as for responses
+			// from removed peers, like a transport would.
+			_ = g.nodes[m.To].rn.Step(m)
+		}
+		if done {
+			return
+		}
+	}
+}
+
+func (g *group) handleReady(n *groupNode) {
+	t := g.t
+	rd := n.rn.Ready()
+	if !raft.IsEmptySnap(rd.Snapshot) {
+		require.NoError(t, n.storage.ApplySnapshot(rd.Snapshot))
+		n.applied = decodeState(rd.Snapshot.Data)
+		n.index = rd.Snapshot.Metadata.Index
+		n.cs = rd.Snapshot.Metadata.ConfState
+	}
+	require.NoError(t, n.storage.Append(rd.Entries))
+	if !raft.IsEmptyHardState(rd.HardState) {
+		require.NoError(t, n.storage.SetHardState(rd.HardState))
+	}
+	g.msgs = append(g.msgs, rd.Messages...)
+	for _, e := range rd.CommittedEntries {
+		switch e.Type {
+		case pb.EntryNormal:
+			if len(e.Data) > 0 {
+				n.applied = append(n.applied, string(e.Data))
+			}
+		case pb.EntryConfChange:
+			var cc pb.ConfChange
+			require.NoError(t, cc.Unmarshal(e.Data))
+			n.cs = *n.rn.ApplyConfChange(cc)
+		case pb.EntryConfChangeV2:
+			var cc pb.ConfChangeV2
+			require.NoError(t, cc.Unmarshal(e.Data))
+			n.cs = *n.rn.ApplyConfChange(cc)
+		}
+		n.index = e.Index
+	}
+	n.rn.Advance(rd)
+
+	first, err := n.storage.FirstIndex()
+	require.NoError(t, err)
+	if g.compactEvery > 0 && n.index >= first-1+g.compactEvery {
+		_, err := n.storage.CreateSnapshot(n.index, &n.cs, encodeState(n.applied))
+		if !errors.Is(err, raft.ErrSnapOutOfDate) {
+			require.NoError(t, err)
+		}
+		require.NoError(t, n.storage.Compact(n.index))
+	}
+}
+
+func encodeState(applied []string) []byte { return []byte(strings.Join(applied, ",")) }
+
+func decodeState(data []byte) []string {
+	if len(data) == 0 {
+		return nil
+	}
+	return strings.Split(string(data), ",")
+}
+
+func (g *group) propose(id uint64, count int) {
+	for i := 0; i < count; i++ {
+		require.NoError(g.t, g.nodes[id].rn.Propose([]byte(fmt.Sprintf("p%d-%d", id, i))))
+		g.stabilize()
+	}
+}
+
+func (g *group) tick() {
+	for _, n := range g.nodes {
+		n.rn.Tick()
+	}
+	g.stabilize()
+}
+
+// requireConverged checks that all nodes applied the same entries, and that
+// their logs agree.
+func (g *group) requireConverged(wantApplied int) {
+	t := g.t
+	lead := g.nodes[1]
+	require.Len(t, lead.applied, wantApplied)
+	for id, n := range g.nodes {
+		require.Equal(t, lead.index, n.index, "applied index of %d", id)
+		require.Equal(t, lead.applied, n.applied, "state of %d", id)
+		first, err := n.storage.FirstIndex()
+		require.NoError(t, err)
+		last, err := n.storage.LastIndex()
+		require.NoError(t, err)
+		require.Equal(t, lead.index, last, "last index of %d", id)
+		for i := first - 1; i <= last; i++ {
+			term, err := n.storage.Term(i)
+			require.NoError(t, err)
+			leadTerm, err := lead.storage.Term(i)
+			if errors.Is(err, raft.ErrCompacted) {
+				continue
+			}
+			require.NoError(t, err)
+			require.Equal(t, leadTerm, term, "term of %d at %d", i, id)
+		}
+	}
+}
+
+// testGroup runs a raft group on top of the storage. A follower is isolated
+// while the others make progress and compact their logs, and catches up
+// through a snapshot once it is back.
+func testGroup(t *testing.T, newStorage func(*testing.T) Storage) {
+	g := newGroup(t, newStorage, 3)
+	require.NoError(t, g.nodes[1].rn.Campaign())
+	g.stabilize()
+	require.Equal(t, raft.StateLeader, g.nodes[1].rn.Status().RaftState)
+
+	g.propose(1, 15)
+	g.isolated[3] = true
+	g.propose(1, 30)
+	first, err := g.nodes[1].storage.FirstIndex()
+	require.NoError(t, err)
+	require.Greater(t, first, g.nodes[3].index+1, "leader didn't compact its log")
+
+	delete(g.isolated, 3)
+	for i := 0; i < 5; i++ {
+		g.tick()
+	}
+	g.propose(1, 5)
+	g.requireConverged(50)
+}
+
+// testGroupSnapshotTemporarilyUnavailable checks that raft retries sending a
+// snapshot that the storage of the leader doesn't have available at first.
+func testGroupSnapshotTemporarilyUnavailable(t *testing.T, newStorage func(*testing.T) Storage) {
+	g := newGroup(t, newStorage, 3)
+	require.NoError(t, g.nodes[1].rn.Campaign())
+	g.stabilize()
+	g.isolated[3] = true
+	g.propose(1, 30)
+
+	g.nodes[1].storage.failures = 3
+	delete(g.isolated, 3)
+	for i := 0; i < 10; i++ {
+		g.tick()
+	}
+	require.Zero(t, g.nodes[1].storage.failures, "snapshot not requested")
+	g.requireConverged(30)
+}
@@ -0,0 +1,150 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storagetest
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go.etcd.io/raft/v3"
+	pb "go.etcd.io/raft/v3/raftpb"
+)
+
+// model is the expected content of a storage: the last compacted entry,
+// followed by the entries of the log.
+type model []pb.Entry
+
+func (m model) compacted() uint64 { return m[0].Index }
+func (m model) last() uint64      { return m[len(m)-1].Index }
+
+// entries returns the entries expected from Storage.Entries.
+func (m model) entries(lo, hi, maxSize uint64) ([]pb.Entry, error) {
+	if lo <= m.compacted() {
+		return nil, raft.ErrCompacted
+	}
+	if m.last() == m.compacted() {
+		return nil, raft.ErrUnavailable
+	}
+	var ents []pb.Entry
+	var size uint64
+	for i := lo; i < hi; i++ {
+		e := m[i-m.compacted()]
+		if size += uint64(e.Size()); len(ents) > 0 && size > maxSize {
+			break
+		}
+		ents = append(ents, e)
+	}
+	return ents, nil
+}
+
+// append appends entries to the model like Storage.Append.
+func (m model) append(ents []pb.Entry) model {
+	return append(m[:ents[0].Index-m.compacted()], ents...)
+}
+
+// testRandom checks randomly generated logs, written through random appends
+// (overwriting part of the log) and compactions, against a model.
+func testRandom(t *testing.T, newStorage func(*testing.T) Storage) {
+	seed := time.Now().UnixNano()
+	t.Logf("seed: %d", seed)
+	rnd := rand.New(rand.NewSource(seed))
+
+	for run := 0; run < 10; run++ {
+		base := uint64(rnd.Intn(10))
+		s := newStorage(t)
+		m := model{{Index: base}}
+		if base > 0 {
+			m[0].Term = 1
+			require.NoError(t, s.ApplySnapshot(pb.Snapshot{Metadata: pb.SnapshotMetadata{Index: base, Term: 1}}))
+		}
+		term := uint64(1)
+		for op := 0; op < 30; op++ {
+			switch n := rnd.Intn(10); {
+			case n < 7:
+				// Append entries, possibly overwriting the tail of the log.
+				from := m.last() + 1
+				if rnd.Intn(3) == 0 {
+					from -= uint64(rnd.Intn(int(m.last()-m.compacted()) + 1))
+					from = max(from, m.compacted()+1)
+				}
+				term += uint64(rnd.Intn(2))
+				ents := make([]pb.Entry, 1+rnd.Intn(10))
+				for i := range ents {
+					ents[i] = pb.Entry{Index: from + uint64(i), Term: term}
+					if size := rnd.Intn(100); size > 0 {
+						ents[i].Data = make([]byte, size)
+						rnd.Read(ents[i].Data)
+					}
+				}
+				require.NoError(t, s.Append(ents))
+				m = m.append(ents)
+			case n < 9 && m.last() > m.compacted():
+				i := m.compacted() + 1 + uint64(rnd.Intn(int(m.last()-m.compacted())))
+				require.NoError(t, s.Compact(i))
+				m = m[i-m.compacted():]
+			}
+			checkModel(t, rnd, s, m)
+		}
+	}
+}
+
+func checkModel(t *testing.T, rnd *rand.Rand, s Storage, m model) {
+	first, err := s.FirstIndex()
+	require.NoError(t, err)
+	require.Equal(t, m.compacted()+1, first)
+	last, err := s.LastIndex()
+	require.NoError(t, err)
+	require.Equal(t, m.last(), last)
+
+	for i := m.compacted() - min(m.compacted(), 2); i <= m.last()+2; i++ {
+		term, err := s.Term(i)
+		switch {
+		case i < m.compacted():
+			require.ErrorIs(t, err, raft.ErrCompacted, "term of %d", i)
+		case i > m.last():
+			require.ErrorIs(t, err, raft.ErrUnavailable, "term of %d", i)
+		default:
+			require.NoError(t, err, "term of %d", i)
+			require.Equal(t, m[i-m.compacted()].Term, term, "term of %d", i)
+		}
+	}
+
+	var total uint64
+	for _, e := range m[1:] {
+		total += uint64(e.Size())
+	}
+	for q := 0; q < 20; q++ {
+		lo := m.compacted() - min(m.compacted(), 1) + uint64(rnd.Intn(int(m.last()-m.compacted())+2))
+		if lo == 0 || lo > m.last() {
+			continue
+		}
+		hi := lo + 1 + uint64(rnd.Intn(int(m.last()-lo)+1))
+		var maxSize uint64
+		switch rnd.Intn(3) {
+		case 0:
+			maxSize = math.MaxUint64
+		case 1:
+			maxSize = uint64(rnd.Int63n(int64(total) + 1))
+		}
+		want, wantErr := m.entries(lo, hi, maxSize)
+		got, err := s.Entries(lo, hi, maxSize)
+		require.ErrorIs(t, err, wantErr, "Entries(%d, %d, %d)", lo, hi, maxSize)
+		require.Equal(t, want, got, "Entries(%d, %d, %d)", lo, hi, maxSize)
+	}
+}
@@ -0,0 +1,418 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package storagetest provides a conformance test suite for implementations of
+// raft.Storage. It checks that an implementation behaves like
+// raft.MemoryStorage, which raft is tested against.
+package storagetest
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.etcd.io/raft/v3"
+	pb "go.etcd.io/raft/v3/raftpb"
+)
+
+// Storage is a raft.Storage that can be written to like raft.MemoryStorage.
+type Storage interface {
+	raft.Storage
+	SetHardState(st pb.HardState) error
+	Append(entries []pb.Entry) error
+	ApplySnapshot(snap pb.Snapshot) error
+	CreateSnapshot(i uint64, cs *pb.ConfState, data []byte) (pb.Snapshot, error)
+	Compact(compactIndex uint64) error
+}
+
+var _ Storage = (*raft.MemoryStorage)(nil)
+
+// Run runs the conformance suite. Each test calls newStorage for a new, empty
+// storage, which it may close using t.Cleanup.
+//
+// Besides table-driven tests of each method, the suite checks randomly
+// generated logs against a model, and runs an in-process raft group on top of
+// storages created by newStorage, with log compaction and followers catching
+// up through snapshots.
+func Run(t *testing.T, newStorage func(t *testing.T) Storage) {
+	for _, tc := range []struct {
+		name string
+		test func(*testing.T, func(*testing.T) Storage)
+	}{
+		{"Term", testTerm},
+		{"Entries", testEntries},
+		{"LastIndex", testLastIndex},
+		{"FirstIndex", testFirstIndex},
+		{"Compact", testCompact},
+		{"CreateSnapshot", testCreateSnapshot},
+		{"Append", testAppend},
+		{"ApplySnapshot", testApplySnapshot},
+		{"HardState", testHardState},
+		{"Empty", testEmpty},
+		{"Boundaries", testBoundaries},
+		{"Random", testRandom},
+		{"Group", testGroup},
+		{"GroupSnapshotTemporarilyUnavailable", testGroupSnapshotTemporarilyUnavailable},
+	} {
+		t.Run(tc.name, func(t *testing.T) { tc.test(t, newStorage) })
+	}
+}
+
+// entries returns entries with consecutive indexes starting at index, and the
+// given terms.
+func entries(index uint64, terms ...uint64) []pb.Entry {
+	ents := make([]pb.Entry, len(terms))
+	for i, term := range terms {
+		ents[i] = pb.Entry{Index: index + uint64(i), Term: term}
+	}
+	return ents
+}
+
+// withEntries returns a new storage whose log holds the given entries, the
+// first of which is the last compacted one, that is the one of the snapshot.
+func withEntries(t *testing.T, newStorage func(*testing.T) Storage, ents []pb.Entry) Storage {
+	s := newStorage(t)
+	require.NoError(t, s.ApplySnapshot(pb.Snapshot{Metadata: pb.SnapshotMetadata{Index: ents[0].Index, Term: ents[0].Term}}))
+	require.NoError(t, s.Append(ents[1:]))
+	return s
+}
+
+// requireLog checks that the log of the storage holds the given entries, the
+// first of which is the last compacted one.
+func requireLog(t *testing.T, s Storage, ents []pb.Entry) {
+	t.Helper()
+	first, err := s.FirstIndex()
+	require.NoError(t, err)
+	require.Equal(t, ents[0].Index+1, first)
+	last, err := s.LastIndex()
+	require.NoError(t, err)
+	require.Equal(t, ents[len(ents)-1].Index, last)
+	term, err := s.Term(ents[0].Index)
+	require.NoError(t, err)
+	require.Equal(t, ents[0].Term, term)
+	if len(ents) > 1 {
+		got, err := s.Entries(first, last+1, math.MaxUint64)
+		require.NoError(t, err)
+		require.Equal(t, ents[1:], got)
+	}
+}
+
+func testTerm(t *testing.T, newStorage func(*testing.T) Storage) {
+	ents := entries(3, 3, 4, 5)
+	for _, tt := range []struct {
+		i uint64
+
+		werr  error
+		wterm uint64
+	}{
+		{2, raft.ErrCompacted, 0},
+		{3, nil, 3},
+		{4, nil, 4},
+		{5, nil, 5},
+		{6, raft.ErrUnavailable, 0},
+	} {
+		t.Run("", func(t *testing.T) {
+			s := withEntries(t, newStorage, ents)
+			term, err := s.Term(tt.i)
+			require.ErrorIs(t, err, tt.werr)
+			require.Equal(t, tt.wterm, term)
+		})
+	}
+}
+
+func testEntries(t *testing.T, newStorage func(*testing.T) Storage) {
+	ents := entries(3, 3, 4, 5, 6)
+	for _, tt := range []struct {
+		lo, hi, maxsize uint64
+
+		werr     error
+		wentries []pb.Entry
+	}{
+		{2, 6, math.MaxUint64, raft.ErrCompacted, nil},
+		{3, 4, math.MaxUint64, raft.ErrCompacted, nil},
+		{4, 5, math.MaxUint64, nil, entries(4, 4)},
+		{4, 6, math.MaxUint64, nil, entries(4, 4, 5)},
+		{4, 7, math.MaxUint64, nil, entries(4, 4, 5, 6)},
+		// even if maxsize is zero, the first entry should be returned
+		{4, 7, 0, nil, entries(4, 4)},
+		// limit to 2
+		{4, 7, uint64(ents[1].Size() + ents[2].Size()), nil, entries(4, 4, 5)},
+		// limit to 2
+		{4, 7, uint64(ents[1].Size() + ents[2].Size() + ents[3].Size()/2), nil, entries(4, 4, 5)},
+		{4, 7, uint64(ents[1].Size() + ents[2].Size() + ents[3].Size() - 1), nil, entries(4, 4, 5)},
+		// all
+		{4, 7, uint64(ents[1].Size() + ents[2].Size() + ents[3].Size()), nil, entries(4, 4, 5, 6)},
+	} {
+		t.Run("", func(t *testing.T) {
+			s := withEntries(t, newStorage, ents)
+			got, err := s.Entries(tt.lo, tt.hi, tt.maxsize)
+			require.ErrorIs(t, err, tt.werr)
+			require.Equal(t, tt.wentries, got)
+		})
+	}
+}
+
+func testLastIndex(t *testing.T, newStorage func(*testing.T) Storage) {
+	s := withEntries(t, newStorage, entries(3, 3, 4, 5))
+
+	last, err := s.LastIndex()
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), last)
+
+	require.NoError(t, s.Append(entries(6, 5)))
+	last, err = s.LastIndex()
+	require.NoError(t, err)
+	require.Equal(t, uint64(6), last)
+}
+
+func testFirstIndex(t *testing.T, newStorage func(*testing.T) Storage) {
+	s := withEntries(t, newStorage, entries(3, 3, 4, 5))
+
+	first, err := s.FirstIndex()
+	require.NoError(t, err)
+	require.Equal(t, uint64(4), first)
+
+	require.NoError(t, s.Compact(4))
+	first, err = s.FirstIndex()
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), first)
+}
+
+func testCompact(t *testing.T, newStorage func(*testing.T) Storage) {
+	ents := entries(3, 3, 4, 5)
+	for _, tt := range []struct {
+		i uint64
+
+		werr  error
+		wents []pb.Entry
+	}{
+		{2, raft.ErrCompacted, ents},
+		{3, raft.ErrCompacted, ents},
+		{4, nil, entries(4, 4, 5)},
+		{5, nil, entries(5, 5)},
+	} {
+		t.Run("", func(t *testing.T) {
+			s := withEntries(t, newStorage, ents)
+			require.ErrorIs(t, s.Compact(tt.i), tt.werr)
+			requireLog(t, s, tt.wents)
+		})
+	}
+}
+
+func testCreateSnapshot(t *testing.T, newStorage func(*testing.T) Storage) {
+	ents := entries(3, 3, 4, 5)
+	cs := &pb.ConfState{Voters: []uint64{1, 2, 3}}
+	data := []byte("data")
+
+	for _, tt := range []struct {
+		i uint64
+
+		werr  error
+		wsnap pb.Snapshot
+	}{
+		{4, nil, pb.Snapshot{Data: data, Metadata: pb.SnapshotMetadata{Index: 4, Term: 4, ConfState: *cs}}},
+		{5, nil, pb.Snapshot{Data: data, Metadata: pb.SnapshotMetadata{Index: 5, Term: 5, ConfState: *cs}}},
+	} {
+		t.Run("", func(t *testing.T) {
+			s := withEntries(t, newStorage, ents)
+			snap, err := s.CreateSnapshot(tt.i, cs, data)
+			require.ErrorIs(t, err, tt.werr)
+			require.Equal(t, tt.wsnap, snap)
+			snap, err = s.Snapshot()
+			require.NoError(t, err)
+			require.Equal(t, tt.wsnap, snap)
+			// Creating a snapshot doesn't compact the log.
+			requireLog(t, s, ents)
+
+			_, err = s.CreateSnapshot(tt.i, cs, data)
+			require.ErrorIs(t, err, raft.ErrSnapOutOfDate)
+		})
+	}
+}
+
+func testAppend(t *testing.T, newStorage func(*testing.T) Storage) {
+	ents := entries(3, 3, 4, 5)
+	for _, tt := range []struct {
+		entries []pb.Entry
+
+		werr     error
+		wentries []pb.Entry
+	}{
+		{
+			entries(1, 1, 2),
+			nil,
+			entries(3, 3, 4, 5),
+		},
+		{
+			entries(3, 3, 4, 5),
+			nil,
+			entries(3, 3, 4, 5),
+		},
+		{
+			entries(3, 3, 6, 6),
+			nil,
+			entries(3, 3, 6, 6),
+		},
+		{
+			entries(3, 3, 4, 5, 5),
+			nil,
+			entries(3, 3, 4, 5, 5),
+		},
+		// Truncate incoming entries, truncate the existing entries and append.
+		{
+			entries(2, 3, 3, 5),
+			nil,
+			entries(3, 3, 5),
+		},
+		// Truncate the existing entries and append.
+		{
+			entries(4, 5),
+			nil,
+			entries(3, 3, 5),
+		},
+		// Direct append.
+		{
+			entries(6, 5),
+			nil,
+			entries(3, 3, 4, 5, 5),
+		},
+	} {
+		t.Run("", func(t *testing.T) {
+			s := withEntries(t, newStorage, ents)
+			require.ErrorIs(t, s.Append(tt.entries), tt.werr)
+			requireLog(t, s, tt.wentries)
+		})
+	}
+}
+
+func testApplySnapshot(t *testing.T, newStorage func(*testing.T) Storage) {
+	cs := pb.ConfState{Voters: []uint64{1, 2, 3}}
+	data := []byte("data")
+	snap := pb.Snapshot{Data: data, Metadata: pb.SnapshotMetadata{Index: 4, Term: 4, ConfState: cs}}
+
+	s := withEntries(t, newStorage, entries(3, 3, 4, 5, 6))
+	require.NoError(t, s.ApplySnapshot(snap))
+	got, err := s.Snapshot()
+	require.NoError(t, err)
+	require.Equal(t, snap, got)
+	_, gotCS, err := s.InitialState()
+	require.NoError(t, err)
+	require.Equal(t, cs, gotCS)
+	// The snapshot replaces the log.
+	requireLog(t, s, entries(4, 4))
+
+	// ApplySnapshot fails due to ErrSnapOutOfDate.
+	old := pb.Snapshot{Data: data, Metadata: pb.SnapshotMetadata{Index: 3, Term: 3, ConfState: cs}}
+	require.ErrorIs(t, s.ApplySnapshot(old), raft.ErrSnapOutOfDate)
+}
+
+func testHardState(t *testing.T, newStorage func(*testing.T) Storage) {
+	s := newStorage(t)
+	hs, cs, err := s.InitialState()
+	require.NoError(t, err)
+	require.Equal(t, pb.HardState{}, hs)
+	require.Equal(t, pb.ConfState{}, cs)
+
+	want := pb.HardState{Term: 3, Vote: 2, Commit: 5}
+	require.NoError(t, s.SetHardState(want))
+	hs, _, err = s.InitialState()
+	require.NoError(t, err)
+	require.Equal(t, want, hs)
+}
+
+func testEmpty(t *testing.T, newStorage func(*testing.T) Storage) {
+	s := newStorage(t)
+	first, err := s.FirstIndex()
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), first)
+	last, err := s.LastIndex()
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), last)
+	term, err := s.Term(0)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), term)
+	_, err = s.Term(1)
+	require.ErrorIs(t, err, raft.ErrUnavailable)
+	_, err = s.Entries(1, 1, math.MaxUint64)
+	require.ErrorIs(t, err, raft.ErrUnavailable)
+	snap, err := s.Snapshot()
+	require.NoError(t, err)
+	require.True(t, raft.IsEmptySnap(snap))
+}
+
+// testBoundaries checks the indexes and terms around the ends of the log as it
+// is appended to, compacted and replaced by a snapshot.
+func testBoundaries(t *testing.T, newStorage func(*testing.T) Storage) {
+	s := newStorage(t)
+	check := func(first, last uint64, terms map[uint64]uint64) {
+		t.Helper()
+		fi, err := s.FirstIndex()
+		require.NoError(t, err)
+		require.Equal(t, first, fi)
+		li, err := s.LastIndex()
+		require.NoError(t, err)
+		require.Equal(t, last, li)
+		if first > 1 {
+			_, err = s.Term(first - 2)
+			require.ErrorIs(t, err, raft.ErrCompacted)
+			_, err = s.Entries(first-1, last+1, math.MaxUint64)
+			require.ErrorIs(t, err, raft.ErrCompacted)
+		}
+		for i := first - 1; i <= last; i++ {
+			term, err := s.Term(i)
+			require.NoError(t, err)
+			require.Equal(t, terms[i], term, "term of %d", i)
+		}
+		_, err = s.Term(last + 1)
+		require.ErrorIs(t, err, raft.ErrUnavailable)
+		if last >= first {
+			ents, err := s.Entries(first, last+1, math.MaxUint64)
+			require.NoError(t, err)
+			require.Len(t, ents, int(last-first+1))
+			ents, err = s.Entries(last, last+1, 0)
+			require.NoError(t, err)
+			require.Equal(t, []pb.Entry{{Index: last, Term: terms[last]}}, ents)
+		}
+	}
+	terms := map[uint64]uint64{0: 0}
+	check(1, 0, terms)
+
+	require.NoError(t, s.Append(entries(1, 1, 1, 2, 2, 3)))
+	terms = map[uint64]uint64{0: 0, 1: 1, 2: 1, 3: 2, 4: 2, 5: 3}
+	check(1, 5, terms)
+
+	require.NoError(t, s.Compact(2))
+	check(3, 5, terms)
+
+	// Compacting all entries leaves the term of the last one.
+	require.NoError(t, s.Compact(5))
+	check(6, 5, terms)
+
+	require.NoError(t, s.Append(entries(6, 3, 4)))
+	terms[6], terms[7] = 3, 4
+	check(6, 7, terms)
+
+	// Overwrite the last entry.
+	require.NoError(t, s.Append(entries(7, 5, 5)))
+	terms[7], terms[8] = 5, 5
+	check(6, 8, terms)
+
+	// A snapshot ahead of the log replaces it.
+	require.NoError(t, s.ApplySnapshot(pb.Snapshot{Metadata: pb.SnapshotMetadata{Index: 20, Term: 6}}))
+	check(21, 20, map[uint64]uint64{20: 6})
+
+	require.NoError(t, s.Append(entries(21, 6)))
+	check(21, 21, map[uint64]uint64{20: 6, 21: 6})
+}
@@ -0,0 +1,383 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package storagewrap provides a raft.Storage decorator which observes and
+// perturbs the calls raft makes into its storage.
+//
+// Raft reads from its Storage synchronously, on the goroutine driving the
+// RawNode or Node, so a slow or failing storage directly stalls the group.
+// Wrapping the storage passed in raft.Config.Storage records, for each method
+// of raft.Storage, the number of calls and errors, the time spent in them and
+// the size of the returned entries and snapshots:
+//
+//	s := storagewrap.New(storage)
+//	cfg.Storage = s
+//	...
+//	st := s.Stats(storagewrap.MethodEntries)
+//
+// The wrapper can also inject faults, delays or errors, into the calls as
+// decided by an Injector. Schedule, Every and WithProbability build injectors
+// failing the calls of a method at given call numbers, periodically or
+// randomly, which for example allows testing how an application copes with
+// raft.ErrSnapshotTemporarilyUnavailable:
+//
+//	s.SetInjector(storagewrap.Every(storagewrap.MethodSnapshot, 2,
+//		storagewrap.Fault{Err: raft.ErrSnapshotTemporarilyUnavailable}))
+//
+// The wrapper implements raft.AsyncStorage, so that it can be used with
+// raft.Config.AsyncStorageReads. FetchEntries is forwarded to the wrapped
+// storage if it implements raft.AsyncStorage, and served by its Entries
+// otherwise, i.e. synchronously.
+//
+// The wrapper only covers the reads of raft.Storage; the application keeps
+// writing to the underlying storage directly.
+package storagewrap
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"go.etcd.io/raft/v3"
+	pb "go.etcd.io/raft/v3/raftpb"
+)
+
+// Method identifies a method of raft.Storage or raft.AsyncStorage.
+type Method int
+
+// The methods of raft.Storage.
+const (
+	MethodInitialState Method = iota
+	MethodEntries
+	MethodTerm
+	MethodLastIndex
+	MethodFirstIndex
+	MethodSnapshot
+	MethodFetchEntries
+	numMethods
+)
+
+var methodNames = [numMethods]string{
+	MethodInitialState: "InitialState",
+	MethodEntries:      "Entries",
+	MethodTerm:         "Term",
+	MethodLastIndex:    "LastIndex",
+	MethodFirstIndex:   "FirstIndex",
+	MethodSnapshot:     "Snapshot",
+	MethodFetchEntries: "FetchEntries",
+}
+
+// Methods lists all methods of raft.Storage and raft.AsyncStorage.
+var Methods = []Method{
+	MethodInitialState, MethodEntries, MethodTerm, MethodLastIndex, MethodFirstIndex, MethodSnapshot,
+	MethodFetchEntries,
+}
+
+func (m Method) String() string {
+	if m < 0 || m >= numMethods {
+		return fmt.Sprintf("Method(%d)", int(m))
+	}
+	return methodNames[m]
+}
+
+// ParseMethod returns the method with the given name, as returned by
+// Method.String.
+func ParseMethod(name string) (Method, error) {
+	for m, n := range methodNames {
+		if n == name {
+			return Method(m), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown storage method %q", name)
+}
+
+// MethodStats holds the statistics of the calls to a method.
+type MethodStats struct {
+	// Calls is the number of calls, including the failed ones.
+	Calls uint64
+	// Errors is the number of calls that returned an error, including the
+	// injected ones. raft.ErrEntriesPending, returned by FetchEntries for the
+	// reads started in the background, doesn't count as an error.
+	Errors uint64
+	// Latency is the total time spent in the calls, including injected
+	// delays.
+	Latency time.Duration
+	// MaxLatency is the longest time spent in a single call.
+	MaxLatency time.Duration
+	// Bytes is the total size of the returned entries (for Entries and
+	// FetchEntries) or snapshots (for Snapshot).
+	Bytes uint64
+}
+
+// Fault is injected into a call to the storage.
+type Fault struct {
+	// Delay is waited before the call.
+	Delay time.Duration
+	// Err, if not nil, is returned without calling the underlying storage.
+	Err error
+}
+
+// Injector decides the faults to inject into the calls to the storage.
+type Injector interface {
+	// Fault returns the fault to inject into the given call of the method,
+	// if any. Calls are numbered from 1 for each method.
+	Fault(m Method, call uint64) (Fault, bool)
+}
+
+// InjectorFunc is an Injector implemented by a function.
+type InjectorFunc func(m Method, call uint64) (Fault, bool)
+
+// Fault implements Injector.
+func (f InjectorFunc) Fault(m Method, call uint64) (Fault, bool) { return f(m, call) }
+
+// Schedule returns an Injector injecting the fault into the given calls of
+// the method, numbered from 1.
+func Schedule(m Method, f Fault, calls ...uint64) Injector {
+	set := make(map[uint64]bool, len(calls))
+	for _, c := range calls {
+		set[c] = true
+	}
+	return InjectorFunc(func(method Method, call uint64) (Fault, bool) {
+		return f, method == m && set[call]
+	})
+}
+
+// Every returns an Injector injecting the fault into every nth call of the
+// method.
+func Every(m Method, n uint64, f Fault) Injector {
+	if n == 0 {
+		panic("n must be greater than 0")
+	}
+	return InjectorFunc(func(method Method, call uint64) (Fault, bool) {
+		return f, method == m && call%n == 0
+	})
+}
+
+// WithProbability returns an Injector injecting the fault into the calls of
+// the method with probability p. The decisions are drawn from a source
+// seeded with the given seed, so that a run can be reproduced.
+func WithProbability(m Method, p float64, seed int64, f Fault) Injector {
+	var mu sync.Mutex
+	rnd := rand.New(rand.NewSource(seed))
+	return InjectorFunc(func(method Method, call uint64) (Fault, bool) {
+		if method != m {
+			return Fault{}, false
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		return f, rnd.Float64() < p
+	})
+}
+
+// Combine returns an Injector injecting the fault of the first of the given
+// injectors that injects one.
+func Combine(injectors ...Injector) Injector {
+	return InjectorFunc(func(m Method, call uint64) (Fault, bool) {
+		for _, inj := range injectors {
+			if f, ok := inj.Fault(m, call); ok {
+				return f, true
+			}
+		}
+		return Fault{}, false
+	})
+}
+
+// Storage is a raft.Storage decorator recording statistics about the calls
+// to the wrapped storage and injecting faults into them. It is safe for
+// concurrent use.
+type Storage struct {
+	storage raft.Storage
+
+	// now and sleep are replaced in tests.
+	now   func() time.Time
+	sleep func(time.Duration)
+
+	mu       sync.Mutex
+	stats    [numMethods]MethodStats
+	injector Injector
+}
+
+var _ raft.AsyncStorage = (*Storage)(nil)
+
+// New returns a Storage wrapping the given storage, with no faults injected.
+func New(s raft.Storage) *Storage {
+	return &Storage{storage: s, now: time.Now, sleep: time.Sleep}
+}
+
+// Unwrap returns the wrapped storage.
+func (s *Storage) Unwrap() raft.Storage { return s.storage }
+
+// SetInjector sets the Injector deciding the faults to inject into the
+// subsequent calls. A nil Injector stops the injection.
+func (s *Storage) SetInjector(inj Injector) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.injector = inj
+}
+
+// Stats returns the statistics of the calls to the given method.
+func (s *Storage) Stats(m Method) MethodStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stats[m]
+}
+
+// ResetStats resets the statistics of all methods. The numbering of the calls
+// passed to the Injector restarts as well.
+func (s *Storage) ResetStats() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stats = [numMethods]MethodStats{}
+}
+
+// call runs fn as a call to the given method, injecting the fault decided by
+// the Injector, if any, and records its statistics. fn returns the number of
+// bytes read.
+func (s *Storage) call(m Method, fn func() (uint64, error)) error {
+	s.mu.Lock()
+	s.stats[m].Calls++
+	callNum := s.stats[m].Calls
+	inj := s.injector
+	s.mu.Unlock()
+
+	var fault Fault
+	if inj != nil {
+		if f, ok := inj.Fault(m, callNum); ok {
+			fault = f
+		}
+	}
+
+	start := s.now()
+	if fault.Delay > 0 {
+		s.sleep(fault.Delay)
+	}
+	var bytes uint64
+	err := fault.Err
+	if err == nil {
+		bytes, err = fn()
+	}
+	latency := s.now().Sub(start)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := &s.stats[m]
+	if err != nil && err != raft.ErrEntriesPending {
+		st.Errors++
+	}
+	st.Latency += latency
+	if latency > st.MaxLatency {
+		st.MaxLatency = latency
+	}
+	st.Bytes += bytes
+	return err
+}
+
+// InitialState implements raft.Storage.
+func (s *Storage) InitialState() (hs pb.HardState, cs pb.ConfState, err error) {
+	err = s.call(MethodInitialState, func() (uint64, error) {
+		var err error
+		hs, cs, err = s.storage.InitialState()
+		return 0, err
+	})
+	return hs, cs, err
+}
+
+// Entries implements raft.Storage.
+func (s *Storage) Entries(lo, hi, maxSize uint64) ([]pb.Entry, error) {
+	return s.entries(MethodEntries, s.storage.Entries, lo, hi, maxSize)
+}
+
+// FetchEntries implements raft.AsyncStorage. If the wrapped storage doesn't
+// implement it, the entries are read synchronously with Entries.
+func (s *Storage) FetchEntries(lo, hi, maxSize uint64) ([]pb.Entry, error) {
+	fetch := s.storage.Entries
+	if as, ok := s.storage.(raft.AsyncStorage); ok {
+		fetch = as.FetchEntries
+	}
+	return s.entries(MethodFetchEntries, fetch, lo, hi, maxSize)
+}
+
+func (s *Storage) entries(
+	m Method, read func(lo, hi, maxSize uint64) ([]pb.Entry, error), lo, hi, maxSize uint64,
+) (ents []pb.Entry, err error) {
+	err = s.call(m, func() (uint64, error) {
+		var err error
+		ents, err = read(lo, hi, maxSize)
+		var size uint64
+		for i := range ents {
+			size += uint64(ents[i].Size())
+		}
+		return size, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ents, nil
+}
+
+// Term implements raft.Storage.
+func (s *Storage) Term(i uint64) (term uint64, err error) {
+	err = s.call(MethodTerm, func() (uint64, error) {
+		var err error
+		term, err = s.storage.Term(i)
+		return 0, err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return term, nil
+}
+
+// LastIndex implements raft.Storage.
+func (s *Storage) LastIndex() (index uint64, err error) {
+	err = s.call(MethodLastIndex, func() (uint64, error) {
+		var err error
+		index, err = s.storage.LastIndex()
+		return 0, err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return index, nil
+}
+
+// FirstIndex implements raft.Storage.
+func (s *Storage) FirstIndex() (index uint64, err error) {
+	err = s.call(MethodFirstIndex, func() (uint64, error) {
+		var err error
+		index, err = s.storage.FirstIndex()
+		return 0, err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return index, nil
+}
+
+// Snapshot implements raft.Storage.
+func (s *Storage) Snapshot() (snap pb.Snapshot, err error) {
+	err = s.call(MethodSnapshot, func() (uint64, error) {
+		var err error
+		snap, err = s.storage.Snapshot()
+		if err != nil {
+			return 0, err
+		}
+		return uint64(snap.Size()), nil
+	})
+	if err != nil {
+		return pb.Snapshot{}, err
+	}
+	return snap, nil
+}
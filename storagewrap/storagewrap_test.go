@@ -0,0 +1,221 @@
+// Copyright 2026 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storagewrap
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.etcd.io/raft/v3"
+	pb "go.etcd.io/raft/v3/raftpb"
+)
+
+// newTestStorage returns a Storage wrapping a MemoryStorage holding entries
+// 4 to 6 and a snapshot at index 3, whose clock only advances by sleeping.
+func newTestStorage(t *testing.T) (*Storage, *raft.MemoryStorage) {
+	ms := raft.NewMemoryStorage()
+	require.NoError(t, ms.ApplySnapshot(pb.Snapshot{Metadata: pb.SnapshotMetadata{Index: 3, Term: 3}}))
+	require.NoError(t, ms.Append([]pb.Entry{
+		{Index: 4, Term: 4, Data: []byte("a")},
+		{Index: 5, Term: 5, Data: []byte("bb")},
+		{Index: 6, Term: 6, Data: []byte("ccc")},
+	}))
+	s := New(ms)
+	now := time.Unix(0, 0)
+	s.now = func() time.Time { return now }
+	s.sleep = func(d time.Duration) { now = now.Add(d) }
+	return s, ms
+}
+
+func TestStats(t *testing.T) {
+	s, ms := newTestStorage(t)
+
+	ents, err := s.Entries(4, 7, 1000)
+	require.NoError(t, err)
+	require.Len(t, ents, 3)
+	_, err = s.Entries(5, 6, 1000)
+	require.NoError(t, err)
+	_, err = s.Term(2)
+	require.Equal(t, raft.ErrCompacted, err)
+	term, err := s.Term(5)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), term)
+	snap, err := s.Snapshot()
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), snap.Metadata.Index)
+
+	size := ents[0].Size() + ents[1].Size() + ents[2].Size()
+	assert.Equal(t, MethodStats{Calls: 2, Bytes: uint64(size + ents[1].Size())}, s.Stats(MethodEntries))
+	assert.Equal(t, MethodStats{Calls: 2, Errors: 1}, s.Stats(MethodTerm))
+	assert.Equal(t, MethodStats{Calls: 1, Bytes: uint64(snap.Size())}, s.Stats(MethodSnapshot))
+	assert.Equal(t, MethodStats{}, s.Stats(MethodLastIndex))
+
+	// The wrapper passes the results of the underlying storage through.
+	for _, f := range []func(raft.Storage) (uint64, error){
+		raft.Storage.FirstIndex, raft.Storage.LastIndex,
+	} {
+		want, wantErr := f(ms)
+		got, err := f(s)
+		require.Equal(t, wantErr, err)
+		require.Equal(t, want, got)
+	}
+	hs, cs, err := s.InitialState()
+	require.NoError(t, err)
+	wantHS, wantCS, _ := ms.InitialState()
+	require.Equal(t, wantHS, hs)
+	require.Equal(t, wantCS, cs)
+
+	s.ResetStats()
+	for _, m := range Methods {
+		assert.Equal(t, MethodStats{}, s.Stats(m), m.String())
+	}
+}
+
+func TestInjectErrors(t *testing.T) {
+	s, _ := newTestStorage(t)
+	errFake := errors.New("fake")
+	s.SetInjector(Combine(
+		Schedule(MethodSnapshot, Fault{Err: raft.ErrSnapshotTemporarilyUnavailable}, 1, 3),
+		Every(MethodEntries, 2, Fault{Err: errFake}),
+	))
+
+	var snapErrs []error
+	for i := 0; i < 4; i++ {
+		_, err := s.Snapshot()
+		snapErrs = append(snapErrs, err)
+	}
+	assert.Equal(t, []error{raft.ErrSnapshotTemporarilyUnavailable, nil, raft.ErrSnapshotTemporarilyUnavailable, nil}, snapErrs)
+
+	var entErrs []error
+	for i := 0; i < 4; i++ {
+		ents, err := s.Entries(4, 5, 1000)
+		if err != nil {
+			require.Empty(t, ents)
+		}
+		entErrs = append(entErrs, err)
+	}
+	assert.Equal(t, []error{nil, errFake, nil, errFake}, entErrs)
+	assert.Equal(t, uint64(2), s.Stats(MethodEntries).Errors)
+	assert.Equal(t, uint64(2), s.Stats(MethodSnapshot).Errors)
+
+	// Other methods are not affected.
+	_, err := s.Term(4)
+	require.NoError(t, err)
+
+	s.SetInjector(nil)
+	_, err = s.Snapshot()
+	require.NoError(t, err)
+}
+
+// pendingStorage is an AsyncStorage whose reads are pending the first time.
+type pendingStorage struct {
+	*raft.MemoryStorage
+	fetched map[uint64]bool
+}
+
+func (s pendingStorage) FetchEntries(lo, hi, maxSize uint64) ([]pb.Entry, error) {
+	if !s.fetched[lo] {
+		s.fetched[lo] = true
+		return nil, raft.ErrEntriesPending
+	}
+	return s.Entries(lo, hi, maxSize)
+}
+
+func TestFetchEntries(t *testing.T) {
+	// A storage which doesn't read in the background serves the entries
+	// synchronously.
+	s, ms := newTestStorage(t)
+	ents, err := s.FetchEntries(4, 6, 1000)
+	require.NoError(t, err)
+	require.Len(t, ents, 2)
+	assert.Equal(t, MethodStats{Calls: 1, Bytes: uint64(ents[0].Size() + ents[1].Size())}, s.Stats(MethodFetchEntries))
+	assert.Equal(t, MethodStats{}, s.Stats(MethodEntries))
+
+	// Otherwise the reads are forwarded, and pending reads are no errors.
+	s = New(pendingStorage{MemoryStorage: ms, fetched: map[uint64]bool{}})
+	_, err = s.FetchEntries(4, 6, 1000)
+	require.Equal(t, raft.ErrEntriesPending, err)
+	ents, err = s.FetchEntries(4, 6, 1000)
+	require.NoError(t, err)
+	require.Len(t, ents, 2)
+	s.SetInjector(Schedule(MethodFetchEntries, Fault{Err: raft.ErrUnavailable}, 3))
+	_, err = s.FetchEntries(4, 6, 1000)
+	require.Equal(t, raft.ErrUnavailable, err)
+	st := s.Stats(MethodFetchEntries)
+	assert.Equal(t, uint64(3), st.Calls)
+	assert.Equal(t, uint64(1), st.Errors)
+
+	// The wrapper can be used with AsyncStorageReads.
+	_, err = raft.NewRawNode(&raft.Config{
+		ID: 1, ElectionTick: 10, HeartbeatTick: 1, Storage: New(ms),
+		MaxSizePerMsg: 1 << 20, MaxInflightMsgs: 256, AsyncStorageReads: true,
+	})
+	require.NoError(t, err)
+}
+
+func TestInjectDelay(t *testing.T) {
+	s, _ := newTestStorage(t)
+	s.SetInjector(InjectorFunc(func(m Method, call uint64) (Fault, bool) {
+		return Fault{Delay: time.Duration(call) * time.Second}, m == MethodLastIndex
+	}))
+	for i := 0; i < 3; i++ {
+		last, err := s.LastIndex()
+		require.NoError(t, err)
+		require.Equal(t, uint64(6), last)
+	}
+	assert.Equal(t, MethodStats{Calls: 3, Latency: 6 * time.Second, MaxLatency: 3 * time.Second}, s.Stats(MethodLastIndex))
+	_, err := s.FirstIndex()
+	require.NoError(t, err)
+	assert.Equal(t, MethodStats{Calls: 1}, s.Stats(MethodFirstIndex))
+}
+
+func TestWithProbability(t *testing.T) {
+	run := func(seed int64) []bool {
+		inj := WithProbability(MethodTerm, 0.5, seed, Fault{Err: raft.ErrUnavailable})
+		var res []bool
+		for call := uint64(1); call <= 100; call++ {
+			_, ok := inj.Fault(MethodTerm, call)
+			res = append(res, ok)
+			_, ok = inj.Fault(MethodEntries, call)
+			require.False(t, ok)
+		}
+		return res
+	}
+	res := run(1)
+	require.Equal(t, res, run(1), "injection is not reproducible")
+	var n int
+	for _, ok := range res {
+		if ok {
+			n++
+		}
+	}
+	require.Greater(t, n, 20)
+	require.Less(t, n, 80)
+}
+
+func TestParseMethod(t *testing.T) {
+	for _, m := range Methods {
+		got, err := ParseMethod(m.String())
+		require.NoError(t, err)
+		require.Equal(t, m, got)
+	}
+	_, err := ParseMethod("Append")
+	require.Error(t, err)
+	require.Equal(t, "Method(17)", Method(17).String())
+}
@@ -114,6 +114,12 @@ type Progress struct {
 
 	// IsLearner is true if this progress is tracked for a learner.
 	IsLearner bool
+
+	// IsWitness is true if this progress is tracked for a witness, i.e. a
+	// voter that stores only the terms and indices of the log. The leader
+	// strips entry payloads from the appends it sends to a witness and sends
+	// it metadata-only snapshots.
+	IsWitness bool
 }
 
 // ResetState moves the Progress into the specified State, resetting MsgAppFlowPaused,
@@ -278,6 +284,9 @@ func (pr *Progress) String() string {
 	if pr.IsLearner {
 		fmt.Fprint(&buf, " learner")
 	}
+	if pr.IsWitness {
+		fmt.Fprint(&buf, " witness")
+	}
 	if pr.IsPaused() {
 		fmt.Fprint(&buf, " paused")
 	}
@@ -15,7 +15,9 @@
 package tracker
 
 import (
+	"cmp"
 	"fmt"
+	"maps"
 	"slices"
 	"strings"
 
@@ -75,6 +77,35 @@ type Config struct {
 	// right away when entering the joint configuration, so that it is caught up
 	// as soon as possible.
 	LearnersNext map[uint64]struct{}
+	// Witnesses is the set of voters (in either half of the joint config)
+	// that are witnesses. A witness votes and acknowledges appends like any
+	// other voter, but only persists the terms and indices of the log and
+	// never becomes leader.
+	//
+	// Invariant: Witnesses is a subset of the voters, and in each half of the
+	// joint config the witnesses alone do not form a replication quorum (see
+	// Quorum). The latter guarantees that every quorum contains a voter that
+	// stores the entries; Committed additionally requires two of them.
+	Witnesses map[uint64]struct{}
+	// Weights holds the voting weights of the voters in the incoming and
+	// outgoing configs, in the same order as Voters. Voters without an entry
+	// carry a weight of one, and an unweighted config has no entries at all.
+	//
+	// Invariant: Weights[i] only has entries for voters in Voters[i], and all
+	// of them differ from one.
+	Weights quorum.JointWeights
+	// Zones holds the zones (failure domains) of the nodes in the config.
+	// Nodes without an entry are not in any known zone.
+	//
+	// Invariant: Zones only has entries for nodes that have a Progress, and
+	// none of them is empty.
+	Zones quorum.Zones
+	// Priorities holds the election priorities of the nodes in the config.
+	// Nodes without an entry have priority zero, the lowest.
+	//
+	// Invariant: Priorities only has entries for nodes that have a Progress,
+	// and none of them is zero.
+	Priorities map[uint64]uint64
 }
 
 func (c Config) String() string {
@@ -86,6 +117,19 @@ func (c Config) String() string {
 	if c.LearnersNext != nil {
 		fmt.Fprintf(&buf, " learners_next=%s", quorum.MajorityConfig(c.LearnersNext).String())
 	}
+	if c.Witnesses != nil {
+		fmt.Fprintf(&buf, " witnesses=%s", quorum.MajorityConfig(c.Witnesses).String())
+	}
+	if c.Weights[0] != nil || c.Weights[1] != nil {
+		fmt.Fprintf(&buf, " weights=%s", c.Weights)
+	}
+	if c.Zones != nil {
+		fmt.Fprintf(&buf, " zones=%s", c.Zones)
+	}
+	if c.Priorities != nil {
+		// Priorities are formatted like weights.
+		fmt.Fprintf(&buf, " priorities=%s", quorum.Weights(c.Priorities))
+	}
 	if c.AutoLeave {
 		fmt.Fprint(&buf, " autoleave")
 	}
@@ -108,6 +152,10 @@ func (c *Config) Clone() Config {
 		Voters:       quorum.JointConfig{clone(c.Voters[0]), clone(c.Voters[1])},
 		Learners:     clone(c.Learners),
 		LearnersNext: clone(c.LearnersNext),
+		Witnesses:    clone(c.Witnesses),
+		Weights:      quorum.JointWeights{maps.Clone(c.Weights[0]), maps.Clone(c.Weights[1])},
+		Zones:        maps.Clone(c.Zones),
+		Priorities:   maps.Clone(c.Priorities),
 	}
 }
 
@@ -121,6 +169,20 @@ type ProgressTracker struct {
 
 	Votes map[uint64]bool
 
+	// Quorum holds the election and replication thresholds. The zero value
+	// uses majorities for both.
+	Quorum quorum.FlexibleQuorum
+	// MinCommitZones is the number of zones that the voters acknowledging a
+	// committed entry, or voting for a leader, have to span (in each half of
+	// the config). A config spanning fewer zones has to span all of them.
+	// Witnesses vote like other voters, but their acknowledgements don't count
+	// towards the zones since they don't store the entries.
+	MinCommitZones int
+	// LenientZoneChecks relaxes the zone check of configuration changes (see
+	// confchange.Changer.Simple) to only refuse changes after which voters
+	// that tolerated the loss of a zone no longer do.
+	LenientZoneChecks bool
+
 	MaxInflight      int
 	MaxInflightBytes uint64
 }
@@ -137,6 +199,7 @@ func MakeProgressTracker(maxInflight int, maxBytes uint64) ProgressTracker {
 			},
 			Learners:     nil, // only populated when used
 			LearnersNext: nil, // only populated when used
+			Witnesses:    nil, // only populated when used
 		},
 		Votes:    map[uint64]bool{},
 		Progress: map[uint64]*Progress{},
@@ -147,12 +210,62 @@ func MakeProgressTracker(maxInflight int, maxBytes uint64) ProgressTracker {
 // ConfState returns a ConfState representing the active configuration.
 func (p *ProgressTracker) ConfState() pb.ConfState {
 	return pb.ConfState{
-		Voters:         p.Voters[0].Slice(),
-		VotersOutgoing: p.Voters[1].Slice(),
-		Learners:       quorum.MajorityConfig(p.Learners).Slice(),
-		LearnersNext:   quorum.MajorityConfig(p.LearnersNext).Slice(),
-		AutoLeave:      p.AutoLeave,
+		Voters:          p.Voters[0].Slice(),
+		VotersOutgoing:  p.Voters[1].Slice(),
+		Learners:        quorum.MajorityConfig(p.Learners).Slice(),
+		LearnersNext:    quorum.MajorityConfig(p.LearnersNext).Slice(),
+		Witnesses:       quorum.MajorityConfig(p.Witnesses).Slice(),
+		Weights:         voterWeights(p.Weights[0]),
+		WeightsOutgoing: voterWeights(p.Weights[1]),
+		Zones:           nodeZones(p.Zones),
+		Priorities:      nodePriorities(p.Priorities),
+		AutoLeave:       p.AutoLeave,
+	}
+}
+
+// voterWeights returns the weights as a slice sorted by ID.
+func voterWeights(w quorum.Weights) []pb.VoterWeight {
+	if len(w) == 0 {
+		return nil
+	}
+	sl := make([]pb.VoterWeight, 0, len(w))
+	for id, weight := range w {
+		sl = append(sl, pb.VoterWeight{NodeID: id, Weight: weight})
 	}
+	slices.SortFunc(sl, func(a, b pb.VoterWeight) int {
+		return cmp.Compare(a.NodeID, b.NodeID)
+	})
+	return sl
+}
+
+// nodeZones returns the zones as a slice sorted by ID.
+func nodeZones(z quorum.Zones) []pb.NodeZone {
+	if len(z) == 0 {
+		return nil
+	}
+	sl := make([]pb.NodeZone, 0, len(z))
+	for id, zone := range z {
+		sl = append(sl, pb.NodeZone{NodeID: id, Zone: zone})
+	}
+	slices.SortFunc(sl, func(a, b pb.NodeZone) int {
+		return cmp.Compare(a.NodeID, b.NodeID)
+	})
+	return sl
+}
+
+// nodePriorities returns the priorities as a slice sorted by ID.
+func nodePriorities(m map[uint64]uint64) []pb.NodePriority {
+	if len(m) == 0 {
+		return nil
+	}
+	sl := make([]pb.NodePriority, 0, len(m))
+	for id, priority := range m {
+		sl = append(sl, pb.NodePriority{NodeID: id, Priority: priority})
+	}
+	slices.SortFunc(sl, func(a, b pb.NodePriority) int {
+		return cmp.Compare(a.NodeID, b.NodeID)
+	})
+	return sl
 }
 
 // IsSingleton returns true if (and only if) there is only one voting member
@@ -175,9 +288,16 @@ func (l matchAckIndexer) AckedIndex(id uint64) (quorum.Index, bool) {
 }
 
 // Committed returns the largest log index known to be committed based on what
-// the voting members of the group have acknowledged.
+// the voting members of the group have acknowledged. With witnesses in the
+// config, an entry also has to be acknowledged by two voters storing the log
+// (or a majority of them, if there are fewer than three).
 func (p *ProgressTracker) Committed() uint64 {
-	return uint64(p.Voters.CommittedIndex(matchAckIndexer(p.Progress)))
+	l := matchAckIndexer(p.Progress)
+	return uint64(min(
+		p.Voters.FlexibleCommittedIndex(p.Quorum, p.Weights, l),
+		p.Voters.ZoneCommittedIndex(p.Zones, p.Witnesses, p.MinCommitZones, l),
+		p.Voters.StoringCommittedIndex(p.Witnesses, l),
+	))
 }
 
 // Visit invokes the supplied closure for all tracked progresses in stable order.
@@ -214,7 +334,7 @@ func (p *ProgressTracker) QuorumActive() bool {
 		votes[id] = pr.RecentActive
 	})
 
-	return p.Voters.VoteResult(votes) == quorum.VoteWon
+	return p.AckResult(votes) == quorum.VoteWon
 }
 
 // VoterNodes returns a sorted slice of voters.
@@ -276,6 +396,30 @@ func (p *ProgressTracker) TallyVotes() (granted int, rejected int, _ quorum.Vote
 			rejected++
 		}
 	}
-	result := p.Voters.VoteResult(p.Votes)
+	result := p.VoteResult(p.Votes)
 	return granted, rejected, result
 }
+
+// VoteResult returns the outcome of the given votes in an election in the
+// active configuration, taking the voting weights and zones into account.
+func (p *ProgressTracker) VoteResult(votes map[uint64]bool) quorum.VoteResult {
+	return quorum.CombineVoteResults(
+		p.Voters.FlexibleVoteResult(p.Quorum, p.Weights, votes),
+		p.Voters.ZoneVoteResult(p.Zones, p.MinCommitZones, votes),
+	)
+}
+
+// AckResult is like VoteResult, but checks the given acknowledgements against
+// the replication quorum, which like in Committed has to include two voters
+// storing the log if there are witnesses. A leader acknowledged by a
+// replication quorum knows that no other leader has been elected, since every
+// election quorum intersects it.
+func (p *ProgressTracker) AckResult(acks map[uint64]bool) quorum.VoteResult {
+	return quorum.CombineVoteResults(
+		quorum.CombineVoteResults(
+			p.Voters.FlexibleAckResult(p.Quorum, p.Weights, acks),
+			p.Voters.ZoneAckResult(p.Zones, p.Witnesses, p.MinCommitZones, acks),
+		),
+		p.Voters.StoringAckResult(p.Witnesses, acks),
+	)
+}
@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"fmt"
 	"strings"
+	"time"
 
 	pb "go.etcd.io/raft/v3/raftpb"
 )
@@ -36,6 +37,9 @@ var isLocalMsg = [...]bool{
 	pb.MsgStorageAppendResp: true,
 	pb.MsgStorageApply:      true,
 	pb.MsgStorageApplyResp:  true,
+	pb.MsgStateHash:         true,
+	pb.MsgStorageEntries:    true,
+	pb.MsgWake:              true,
 }
 
 var isResponseMsg = [...]bool{
@@ -47,6 +51,7 @@ var isResponseMsg = [...]bool{
 	pb.MsgPreVoteResp:       true,
 	pb.MsgStorageAppendResp: true,
 	pb.MsgStorageApplyResp:  true,
+	pb.MsgSnapChunkResp:     true,
 }
 
 func isMsgInArray(msgt pb.MessageType, arr []bool) bool {
@@ -62,6 +67,24 @@ func IsResponseMsg(msgt pb.MessageType) bool {
 	return isMsgInArray(msgt, isResponseMsg[:])
 }
 
+// isSnapChunkMsg returns true for the messages streaming snapshot data, which
+// are exchanged by the snapstream package rather than raft itself.
+func isSnapChunkMsg(msgt pb.MessageType) bool {
+	return msgt == pb.MsgSnapChunk || msgt == pb.MsgSnapChunkResp
+}
+
+// hasCompressedEntries returns true if the message carries entries whose data
+// is still compressed, which the transport should have undone (see the
+// compression package).
+func hasCompressedEntries(m pb.Message) bool {
+	for _, c := range m.EntryCodecs {
+		if c != pb.CodecNone {
+			return true
+		}
+	}
+	return false
+}
+
 func IsLocalMsgTarget(id uint64) bool {
 	return id == LocalAppendThread || id == LocalApplyThread
 }
@@ -93,10 +116,56 @@ func DescribeSoftState(ss SoftState) string {
 }
 
 func DescribeConfState(state pb.ConfState) string {
-	return fmt.Sprintf(
+	s := fmt.Sprintf(
 		"Voters:%v VotersOutgoing:%v Learners:%v LearnersNext:%v AutoLeave:%v",
 		state.Voters, state.VotersOutgoing, state.Learners, state.LearnersNext, state.AutoLeave,
 	)
+	if len(state.Witnesses) > 0 {
+		s += fmt.Sprintf(" Witnesses:%v", state.Witnesses)
+	}
+	describeWeights := func(weights []pb.VoterWeight) string {
+		var buf strings.Builder
+		buf.WriteByte('[')
+		for i, w := range weights {
+			if i > 0 {
+				buf.WriteByte(' ')
+			}
+			fmt.Fprintf(&buf, "%d:%d", w.NodeID, w.Weight)
+		}
+		buf.WriteByte(']')
+		return buf.String()
+	}
+	if len(state.Weights) > 0 {
+		s += " Weights:" + describeWeights(state.Weights)
+	}
+	if len(state.WeightsOutgoing) > 0 {
+		s += " WeightsOutgoing:" + describeWeights(state.WeightsOutgoing)
+	}
+	if len(state.Zones) > 0 {
+		var buf strings.Builder
+		buf.WriteByte('[')
+		for i, z := range state.Zones {
+			if i > 0 {
+				buf.WriteByte(' ')
+			}
+			fmt.Fprintf(&buf, "%d:%s", z.NodeID, z.Zone)
+		}
+		buf.WriteByte(']')
+		s += " Zones:" + buf.String()
+	}
+	if len(state.Priorities) > 0 {
+		var buf strings.Builder
+		buf.WriteByte('[')
+		for i, p := range state.Priorities {
+			if i > 0 {
+				buf.WriteByte(' ')
+			}
+			fmt.Fprintf(&buf, "%d:%d", p.NodeID, p.Priority)
+		}
+		buf.WriteByte(']')
+		s += " Priorities:" + buf.String()
+	}
+	return s
 }
 
 func DescribeSnapshot(snap pb.Snapshot) string {
@@ -164,6 +233,13 @@ func describeMessageWithIndent(indent string, m pb.Message, f EntryFormatter) st
 	if m.Vote != 0 {
 		fmt.Fprintf(&buf, " Vote:%d", m.Vote)
 	}
+	if m.Quiesce {
+		fmt.Fprint(&buf, " Quiesce")
+	}
+	if m.SafeReadTime != 0 {
+		// The time is printed as the duration since the Unix epoch.
+		fmt.Fprintf(&buf, " SafeRead:%d@%v", m.SafeReadIndex, time.Duration(m.SafeReadTime))
+	}
 	if ln := len(m.Entries); ln == 1 {
 		fmt.Fprintf(&buf, " Entries:[%s]", DescribeEntry(m.Entries[0], f))
 	} else if ln > 1 {
@@ -174,6 +250,12 @@ func describeMessageWithIndent(indent string, m pb.Message, f EntryFormatter) st
 		}
 		fmt.Fprintf(&buf, "\n%s]", indent)
 	}
+	if c := m.Chunk; c != nil {
+		fmt.Fprintf(&buf, " Chunk:%d+%d", c.Offset, len(c.Data))
+		if c.Last {
+			fmt.Fprint(&buf, " (last)")
+		}
+	}
 	if s := m.Snapshot; s != nil && !IsEmptySnap(*s) {
 		fmt.Fprintf(&buf, "\n%s  Snapshot: %s", indent, DescribeSnapshot(*s))
 	}
@@ -232,6 +314,15 @@ func DescribeEntry(e pb.Entry, f EntryFormatter) string {
 		} else {
 			formatted = formatConfChange(cc)
 		}
+	case pb.EntryHashCheck:
+		if len(e.Data) > 0 {
+			var hc pb.HashCheck
+			if err := hc.Unmarshal(e.Data); err != nil {
+				formatted = err.Error()
+			} else {
+				formatted = fmt.Sprintf("index=%d hash=%x", hc.Index, hc.Hash)
+			}
+		}
 	}
 	if formatted != "" {
 		formatted = " " + formatted
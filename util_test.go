@@ -97,6 +97,12 @@ func TestIsLocalMsg(t *testing.T) {
 		{pb.MsgStorageAppendResp, true},
 		{pb.MsgStorageApply, true},
 		{pb.MsgStorageApplyResp, true},
+		{pb.MsgForgetLeader, false},
+		{pb.MsgSnapChunk, false},
+		{pb.MsgSnapChunkResp, false},
+		{pb.MsgStateHash, true},
+		{pb.MsgStorageEntries, true},
+		{pb.MsgWake, true},
 	}
 
 	for _, tt := range tests {
@@ -134,6 +140,12 @@ func TestIsResponseMsg(t *testing.T) {
 		{pb.MsgStorageAppendResp, true},
 		{pb.MsgStorageApply, false},
 		{pb.MsgStorageApplyResp, true},
+		{pb.MsgForgetLeader, false},
+		{pb.MsgSnapChunk, false},
+		{pb.MsgSnapChunkResp, true},
+		{pb.MsgStateHash, false},
+		{pb.MsgStorageEntries, false},
+		{pb.MsgWake, false},
 	}
 
 	for i, tt := range tests {